@@ -0,0 +1,1037 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+//region Workload generation
+
+// Distribution is a random distribution for GenerateOptions.
+type Distribution int
+
+const (
+	// Uniform draws from a fixed uniform range.
+	Uniform Distribution = iota
+	// Poisson makes arrivals a Poisson process: the gaps between arrivals are exponential.
+	Poisson
+	// Exponential draws exponentially distributed bursts.
+	Exponential
+	// Bursty makes arrivals come in clusters of ClusterSize processes, one cluster every
+	// ClusterGap time units starting at 0, each process arriving within 2 units of the start of
+	// its cluster.
+	Bursty
+	// Fixed always draws the same value, for a BurstDistribution.
+	Fixed
+)
+
+// GenerateOptions chooses the distributions of GenerateProcessesWith. The zero value is the
+// workload of GenerateProcesses.
+type GenerateOptions struct {
+	// Arrivals is Uniform, for arrivals uniform in [0, 3n), Poisson, for arrivals at an
+	// average of ArrivalRate processes per time unit, each time rounded down, starting at 0, or
+	// Bursty. ArrivalRate defaults to 1, ClusterSize to 5 and ClusterGap to 30.
+	Arrivals    Distribution
+	ArrivalRate float64
+	ClusterSize int
+	ClusterGap  int64
+	// Bursts is Uniform, for bursts uniform in [1, 10], or Exponential, for bursts with a mean
+	// of about MeanBurst rounded up so every burst is at least 1. MeanBurst defaults to 5.
+	Bursts    Distribution
+	MeanBurst float64
+}
+
+// GenerateProcesses returns n random processes named P0 to Pn-1 in arrival order. Bursts are
+// uniform in [1, 10], arrivals uniform in [0, 3n), and priorities uniform in [1, 5]. The same
+// seed always produces the same workload.
+func GenerateProcesses(n int, seed int64) []Process {
+	return GenerateProcessesWith(n, seed, GenerateOptions{})
+}
+
+// GenerateProcessesWith is GenerateProcesses with the arrival and burst distributions of opts.
+// Priorities are always uniform in [1, 5], and the same seed and opts always produce the same
+// workload.
+func GenerateProcessesWith(n int, seed int64, opts GenerateOptions) []Process {
+	if opts.ArrivalRate <= 0 {
+		opts.ArrivalRate = 1
+	}
+	if opts.MeanBurst <= 0 {
+		opts.MeanBurst = 5
+	}
+	if opts.ClusterSize <= 0 {
+		opts.ClusterSize = 5
+	}
+	if opts.ClusterGap <= 0 {
+		opts.ClusterGap = 30
+	}
+	rng := rand.New(rand.NewSource(seed))
+	processes := make([]Process, n)
+	var clock float64 // the last Poisson arrival.
+	for i := range processes {
+		p := &processes[i]
+		if opts.Bursts == Exponential {
+			p.BurstDuration = int64(math.Ceil(rng.ExpFloat64() * opts.MeanBurst))
+			p.BurstDuration = max(1, p.BurstDuration)
+		} else {
+			p.BurstDuration = 1 + rng.Int63n(10)
+		}
+		switch opts.Arrivals {
+		case Poisson:
+			if i > 0 {
+				clock += rng.ExpFloat64() / opts.ArrivalRate
+			}
+			p.ArrivalTime = int64(clock)
+		case Bursty:
+			p.ArrivalTime = int64(i/opts.ClusterSize)*opts.ClusterGap + rng.Int63n(2)
+		default:
+			p.ArrivalTime = rng.Int63n(int64(3 * n))
+		}
+		p.Priority = 1 + rng.Int63n(5)
+	}
+	sort.SliceStable(processes, func(i, j int) bool {
+		return processes[i].ArrivalTime < processes[j].ArrivalTime
+	})
+	for i := range processes {
+		processes[i].ProcessID = fmt.Sprintf("P%d", i)
+	}
+
+	return processes
+}
+
+// GenerateReplayable is GenerateProcessesWith that also returns the seed it used, so a workload
+// that shows a problem can be generated again. A seed of 0 picks a random nonzero seed; any
+// other seed is used as given.
+func GenerateReplayable(n int, seed int64, opts GenerateOptions) (processes []Process, used int64) {
+	for seed == 0 {
+		seed = rand.Int63()
+	}
+	return GenerateProcessesWith(n, seed, opts), seed
+}
+
+// BucketByArrival groups processes by arrival window: window k holds the processes arriving in
+// [k*windowSize, (k+1)*windowSize), so a process arriving exactly on an edge starts the next
+// window. Processes keep their input order within a window and empty windows are left out. A
+// windowSize <= 0 returns nil.
+func BucketByArrival(processes []Process, windowSize int64) map[int64][]Process {
+	if windowSize <= 0 {
+		return nil
+	}
+	buckets := make(map[int64][]Process)
+	for _, p := range processes {
+		k := p.ArrivalTime / windowSize
+		if p.ArrivalTime%windowSize < 0 {
+			k-- // round down, not toward zero.
+		}
+		buckets[k] = append(buckets[k], p)
+	}
+	return buckets
+}
+
+//endregion
+
+//region Comparisons
+
+// WaitDifference is the average waiting time of one scheduler minus another's over a set of
+// workloads, with a 95% confidence interval for the mean.
+type WaitDifference struct {
+	Mean float64
+	Low  float64
+	High float64
+}
+
+// CompareAvgWait generates m workloads of n processes from seed and reports the mean of
+// a's average wait minus b's average wait across them. The confidence interval uses the
+// normal approximation, so it is only meaningful for a reasonably large m; with a single
+// workload it collapses to the mean. m and n must be positive.
+func CompareAvgWait(a, b Scheduler, m, n int, seed int64) (WaitDifference, error) {
+	if m < 1 || n < 1 {
+		return WaitDifference{}, fmt.Errorf("%w: %d workloads of %d processes", ErrInvalidArgs, m, n)
+	}
+	rng := rand.New(rand.NewSource(seed))
+	diffs := make([]float64, m)
+	var sum float64
+	for i := range diffs {
+		processes := GenerateProcesses(n, rng.Int63())
+		diffs[i] = a.Schedule(processes).AvgWait - b.Schedule(processes).AvgWait
+		sum += diffs[i]
+	}
+
+	d := WaitDifference{Mean: sum / float64(m)}
+	d.Low, d.High = d.Mean, d.Mean
+	if m < 2 {
+		return d, nil
+	}
+	var squares float64
+	for _, diff := range diffs {
+		squares += (diff - d.Mean) * (diff - d.Mean)
+	}
+	stddev := math.Sqrt(squares / float64(m-1))
+	margin := 1.96 * stddev / math.Sqrt(float64(m))
+	d.Low, d.High = d.Mean-margin, d.Mean+margin
+
+	return d, nil
+}
+
+// WaitDistribution is the spread of a scheduler's average wait over a set of workloads. Waits
+// holds each workload's average wait in the order the workloads were generated, and StdDev is
+// the sample standard deviation, 0 for fewer than two workloads.
+type WaitDistribution struct {
+	Waits  []float64
+	Mean   float64
+	StdDev float64
+	Min    float64
+	Median float64
+	Max    float64
+}
+
+// VaryArrivals runs s on m workloads that keep the bursts, priorities and IDs of processes and
+// vary only their arrival times, and returns the distribution of the average wait. Each
+// workload draws its arrivals from seed as GenerateProcessesWith draws them under opts, whose
+// burst options are ignored, and hands them out in order, so processes[i] is always the i-th to
+// arrive. It returns the zero WaitDistribution if m is not positive.
+func VaryArrivals(s Scheduler, processes []Process, m int, seed int64, opts GenerateOptions) WaitDistribution {
+	if m <= 0 {
+		return WaitDistribution{}
+	}
+	rng := rand.New(rand.NewSource(seed))
+	d := WaitDistribution{Waits: make([]float64, m)}
+	var sum float64
+	for k := range d.Waits {
+		workload := append([]Process(nil), processes...)
+		for i, p := range GenerateProcessesWith(len(workload), rng.Int63(), opts) {
+			workload[i].ArrivalTime = p.ArrivalTime
+		}
+		d.Waits[k] = s.Schedule(workload).AvgWait
+		sum += d.Waits[k]
+	}
+
+	d.Mean = sum / float64(m)
+	sorted := append([]float64(nil), d.Waits...)
+	sort.Float64s(sorted)
+	d.Min, d.Max = sorted[0], sorted[m-1]
+	d.Median = sorted[m/2]
+	if m%2 == 0 {
+		d.Median = (sorted[m/2-1] + sorted[m/2]) / 2
+	}
+	if m > 1 {
+		var squares float64
+		for _, wait := range d.Waits {
+			squares += (wait - d.Mean) * (wait - d.Mean)
+		}
+		d.StdDev = math.Sqrt(squares / float64(m-1))
+	}
+	return d
+}
+
+// ResponseSummary is the response time, from arrival to first reaching the CPU, of one
+// algorithm in BurstyResponse: the average and the 95th percentile, in time units.
+type ResponseSummary struct {
+	Algo   string
+	Result ScheduleResult
+	Avg    float64
+	P95    float64
+}
+
+// BurstyResponse generates n processes from seed arriving in Bursty clusters and reports the
+// response times of round-robin with quantum and of SRTF on them, in that order.
+func BurstyResponse(n int, seed int64, quantum int64) []ResponseSummary {
+	processes := GenerateProcessesWith(n, seed, GenerateOptions{Arrivals: Bursty})
+	var summaries []ResponseSummary
+	for _, s := range []Scheduler{RR{Quantum: quantum}, SRTF{}} {
+		r := s.Schedule(processes)
+		responses := ResponseTimes(r)
+		values := make([]float64, len(responses))
+		summary := ResponseSummary{Algo: s.Name(), Result: r}
+		for i, t := range responses {
+			values[i] = float64(t) / float64(max(1, r.Resolution))
+			summary.Avg += values[i] / float64(len(values))
+		}
+		summary.P95 = Percentile(values, 95)
+		summaries = append(summaries, summary)
+	}
+	return summaries
+}
+
+// BestByAvgWait runs every non-preemptive scheduler on its own copy of processes and returns
+// the name and result of the one with the lowest average wait. Ties go to the simplest
+// algorithm, in the order FCFS, SJF, SJF with priority.
+func BestByAvgWait(processes []Process) (algo string, result ScheduleResult) {
+	for n, s := range []Scheduler{FCFS{}, SJF{}, SJFPriority{}} {
+		r := s.Schedule(append([]Process(nil), processes...))
+		if n == 0 || r.AvgWait < result.AvgWait {
+			algo, result = s.Name(), r
+		}
+	}
+	return algo, result
+}
+
+// Convoy measures the convoy effect in a schedule: the Short processes, those with a burst
+// below the average, waited Wait time units on average, against SJFWait when SJF schedules the
+// same processes.
+type Convoy struct {
+	Short   []string
+	Wait    float64
+	SJFWait float64
+}
+
+// Magnitude is the extra average wait the short processes incurred, in time units; 0 or less
+// means there was no convoy.
+func (c Convoy) Magnitude() float64 { return c.Wait - c.SJFWait }
+
+// MeasureConvoy measures the convoy effect in r, usually an FCFS schedule, in which short
+// processes pile up behind a long one that got the CPU first, by scheduling its processes again
+// with SJF, which runs the short processes first.
+func MeasureConvoy(r ScheduleResult) Convoy {
+	processes := make([]Process, len(r.Rows))
+	var work int64
+	for i, row := range r.Rows {
+		processes[i] = row.Process
+		work += row.Process.BurstDuration
+	}
+	sjf := SJF{}.Schedule(processes)
+	var c Convoy
+	for i, p := range processes {
+		if p.BurstDuration*int64(len(processes)) >= work {
+			continue
+		}
+		c.Short = append(c.Short, p.ProcessID)
+		c.Wait += float64(r.Rows[i].Waiting) / float64(max(1, r.Resolution))
+		c.SJFWait += float64(sjf.Rows[i].Waiting)
+	}
+	if len(c.Short) > 0 {
+		c.Wait /= float64(len(c.Short))
+		c.SJFWait /= float64(len(c.Short))
+	}
+	return c
+}
+
+// Load compares the work offered to a schedule with the work it got through, in queueing
+// theory's terms: processes arrive at ArrivalRate (λ) per time unit over the Span from the first
+// arrival to the last, and a core completes them at ServiceRate (μ), one over the average burst.
+// Rho (ρ) is the offered load, λ over the Cores' combined μ: the total burst over the span,
+// per core. Throughput is the rate the schedule achieved.
+type Load struct {
+	Span        float64
+	ArrivalRate float64
+	ServiceRate float64
+	Cores       int
+	Rho         float64
+	Throughput  float64
+}
+
+// Stable reports whether the cores can keep up with the arrivals, ρ < 1. Past that the ready
+// queue grows for as long as the arrivals keep coming, and waits with it, whatever the
+// algorithm.
+func (l Load) Stable() bool { return l.Rho < 1 }
+
+// MeasureLoad measures the offered load of r's processes with work. When they all arrive at
+// once, Span is 0 and ArrivalRate and Rho are infinite.
+func MeasureLoad(r ScheduleResult) Load {
+	l := Load{Cores: r.Cores, Throughput: r.Throughput}
+	if l.Cores < 1 {
+		l.Cores = 1
+	}
+	var (
+		n                 int
+		work, first, last int64
+	)
+	for _, row := range r.Rows {
+		p := row.Process
+		if p.BurstDuration == 0 {
+			continue
+		}
+		if n == 0 || p.ArrivalTime < first {
+			first = p.ArrivalTime
+		}
+		last = max(last, p.ArrivalTime)
+		work += p.BurstDuration
+		n++
+	}
+	if n == 0 {
+		return l
+	}
+	l.Span = float64(last - first)
+	l.ArrivalRate = float64(n) / l.Span
+	l.ServiceRate = float64(n) / float64(work)
+	l.Rho = l.ArrivalRate / (l.ServiceRate * float64(l.Cores))
+	return l
+}
+
+// Comparison is the result of one algorithm in CompareAll. VsFCFS is the percentage by which
+// its average wait beats FCFS's on the same workload: positive is an improvement, negative a
+// regression, and 0 when FCFS has no wait to improve on. Preemptions is the number of times a
+// process left the CPU before completing; the context switches are in Result.
+type Comparison struct {
+	Algo        string
+	Result      ScheduleResult
+	VsFCFS      float64
+	Preemptions int
+}
+
+// CompareAll runs each command-line algorithm with cfg on its own copy of processes, FCFS first.
+// The algorithms run concurrently, one goroutine each.
+func CompareAll(processes []Process, cfg SchedulerConfig) []Comparison {
+	algorithms := []Algorithm{fcfs, sjf, sjfp, rr}
+	comparisons := make([]Comparison, len(algorithms))
+	var wg sync.WaitGroup
+	for n, a := range algorithms {
+		wg.Add(1)
+		go func(n int, s Scheduler) {
+			defer wg.Done()
+			r := s.Schedule(append([]Process(nil), processes...))
+			c := Comparison{Algo: s.Name(), Result: r}
+			for _, e := range Events(r) {
+				if e.Type == EventPreempt {
+					c.Preemptions++
+				}
+			}
+			comparisons[n] = c
+		}(n, schedulerFor(a, cfg))
+	}
+	wg.Wait()
+	if base := comparisons[0].Result.AvgWait; base > 0 {
+		for n := range comparisons[1:] {
+			c := &comparisons[n+1]
+			c.VsFCFS = 100 * (base - c.Result.AvgWait) / base
+		}
+	}
+	return comparisons
+}
+
+// DispatchComparison is the result of one DispatchPolicy in CompareDispatch. Spread is the busy
+// time of the busiest core less that of the idlest, in the result's ticks; 0 is perfectly
+// balanced.
+type DispatchComparison struct {
+	Policy DispatchPolicy
+	Result ScheduleResult
+	Spread int64
+}
+
+// CompareDispatch runs MultiCore on cores CPUs under every DispatchPolicy, each on its own copy
+// of processes.
+func CompareDispatch(processes []Process, cores int) []DispatchComparison {
+	var comparisons []DispatchComparison
+	for _, policy := range DispatchPolicies {
+		r := MultiCore{Cores: cores, Dispatch: policy}.Schedule(append([]Process(nil), processes...))
+		usage, _ := CoreUtilization(r)
+		busiest, idlest := usage[0].Busy, usage[0].Busy
+		for _, u := range usage[1:] {
+			busiest, idlest = max(busiest, u.Busy), min(idlest, u.Busy)
+		}
+		comparisons = append(comparisons, DispatchComparison{Policy: policy, Result: r, Spread: busiest - idlest})
+	}
+	return comparisons
+}
+
+// BackfillComparison is a gang MultiCore schedule run Strict, with no process overtaking one
+// waiting for cores, and then Backfilled.
+type BackfillComparison struct {
+	Strict     ScheduleResult
+	Backfilled ScheduleResult
+}
+
+// Improvement is the fraction of the strict makespan that backfilling saved; it is negative if
+// backfilling made the schedule longer.
+func (c BackfillComparison) Improvement() float64 {
+	if c.Strict.Makespan == 0 {
+		return 0
+	}
+	return float64(c.Strict.Makespan-c.Backfilled.Makespan) / float64(c.Strict.Makespan)
+}
+
+// CompareBackfill runs MultiCore on cores CPUs in Gang mode with and without Backfill, each on
+// its own copy of processes.
+func CompareBackfill(processes []Process, cores int) BackfillComparison {
+	return BackfillComparison{
+		Strict:     MultiCore{Cores: cores, Gang: true}.Schedule(append([]Process(nil), processes...)),
+		Backfilled: MultiCore{Cores: cores, Backfill: true}.Schedule(append([]Process(nil), processes...)),
+	}
+}
+
+// OptimalRRQuantum runs round-robin on processes with every quantum lo, lo+step, ... up to hi
+// and returns the one with the lowest average turnaround. Ties go to the quantum with fewer
+// context switches, then to the smaller quantum. It returns 0 if the range holds no positive
+// quantum or step is not positive.
+func OptimalRRQuantum(processes []Process, lo, hi, step int64) int64 {
+	if step <= 0 {
+		return 0
+	}
+	var (
+		best  int64
+		bestR ScheduleResult
+	)
+	for q := max(1, lo); q <= hi; q += step {
+		r := RR{Quantum: q}.Schedule(processes)
+		if best == 0 || r.AvgTurnaround < bestR.AvgTurnaround ||
+			r.AvgTurnaround == bestR.AvgTurnaround && r.ContextSwitches < bestR.ContextSwitches {
+			best, bestR = q, r
+		}
+		if q > hi-step {
+			break // q + step would overflow or pass hi.
+		}
+	}
+	return best
+}
+
+// ErrInfeasible is returned by MinCores when no core count up to its cap meets every deadline.
+var ErrInfeasible = errors.New("deadlines cannot be met")
+
+// MinCores returns the fewest cores, up to maxCores, on which the scheduler newScheduler returns
+// for that many cores completes every process by its deadline, each run on its own copy of
+// processes:
+//
+//	MinCores(processes, func(cores int) Scheduler { return EDF{Cores: cores} }, 8)
+//
+// Every count is tried in turn from 1, since a multi-core scheduler can miss a deadline on more
+// cores that it met on fewer. If none meets every deadline the error names the processes late
+// on maxCores.
+func MinCores(processes []Process, newScheduler func(cores int) Scheduler, maxCores int) (int, error) {
+	if maxCores < 1 {
+		return 0, fmt.Errorf("%w: a cap of %d cores", ErrInvalidArgs, maxCores)
+	}
+	var late []string
+	for cores := 1; cores <= maxCores; cores++ {
+		r := newScheduler(cores).Schedule(append([]Process(nil), processes...))
+		if late = MissedDeadlines(r); len(late) == 0 {
+			return cores, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: on %d cores %s finish late", ErrInfeasible, maxCores, strings.Join(late, ", "))
+}
+
+// Annotate runs s on processes without printing anything, records each process's outcome in
+// its own Completed, Waiting and Turnaround fields, in s's ticks, and returns processes.
+func Annotate(s Scheduler, processes []Process) []Process {
+	r := s.Schedule(processes)
+	for i, row := range r.Rows {
+		processes[i].Completed = true
+		processes[i].Waiting = int(row.Waiting)
+		processes[i].Turnaround = int(row.Turnaround)
+	}
+	return processes
+}
+
+// MeasureDecisions runs s on processes and also returns the wall-clock time it spent deciding
+// which process runs next, leaving out the rest of the simulation. ok is false, and decisions
+// zero, for a Scheduler outside this package whose decisions cannot be measured.
+func MeasureDecisions(s Scheduler, processes []Process) (r ScheduleResult, decisions time.Duration, ok bool) {
+	return MeasureDecisionsWith(s, processes, wallClock{})
+}
+
+// MeasureDecisionsWith is MeasureDecisions reading the time from now.
+func MeasureDecisionsWith(s Scheduler, processes []Process, now Clock) (r ScheduleResult, decisions time.Duration, ok bool) {
+	c, ok := s.(clocked)
+	if !ok {
+		return s.Schedule(processes), 0, false
+	}
+	clock := decisionClock{now: now}
+	r = c.scheduleClocked(processes, &clock)
+	return r, clock.total, true
+}
+
+//endregion
+
+//region Checks
+
+// IdleViolation is an interval during which the CPU sat idle although the processes in Ready had
+// arrived and were not yet complete.
+type IdleViolation struct {
+	Start int64
+	Stop  int64
+	Ready []string
+}
+
+// WorkConservationViolations scans gantt, the schedule of processes, for idle intervals during
+// which a process was ready to run. A work-conserving scheduler never produces any. A process is
+// complete at the last Stop of its slices, and ready once it has arrived and every process it
+// DependsOn is complete.
+func WorkConservationViolations(processes []Process, gantt []TimeSlice) []IdleViolation {
+	completion := make(map[string]int64, len(processes))
+	for _, slice := range gantt {
+		completion[slice.PID] = max(completion[slice.PID], slice.Stop)
+	}
+	readyAt := make([]int64, len(processes))
+	for i, p := range processes {
+		readyAt[i] = p.ArrivalTime
+		for _, dep := range p.DependsOn {
+			readyAt[i] = max(readyAt[i], completion[dep])
+		}
+	}
+	slices := append([]TimeSlice(nil), gantt...)
+	sort.SliceStable(slices, func(i, j int) bool {
+		return slices[i].Start < slices[j].Start
+	})
+
+	var violations []IdleViolation
+	var idleFrom int64
+	for n, slice := range slices {
+		if n == 0 || slice.Start > idleFrom {
+			// The CPU is idle over [idleFrom, slice.Start); before the first slice it idles from
+			// the first arrival.
+			if n == 0 {
+				idleFrom = slice.Start
+				for _, p := range processes {
+					idleFrom = min(idleFrom, p.ArrivalTime)
+				}
+			}
+			v := IdleViolation{Start: slice.Start, Stop: slice.Start}
+			for i, p := range processes {
+				if readyAt[i] < slice.Start && completion[p.ProcessID] > idleFrom {
+					v.Start = min(v.Start, max(idleFrom, readyAt[i]))
+					v.Ready = append(v.Ready, p.ProcessID)
+				}
+			}
+			if len(v.Ready) > 0 {
+				violations = append(violations, v)
+			}
+		}
+		idleFrom = max(idleFrom, slice.Stop)
+	}
+
+	return violations
+}
+
+// ErrGanttMismatch is wrapped by the error AssertGantt returns for a schedule that differs from
+// the expected one.
+var ErrGanttMismatch = errors.New("gantt mismatch")
+
+// AssertGantt runs sched on processes and returns an error describing the first slice where its
+// Gantt diverges from expected, or nil if the two are identical.
+func AssertGantt(expected []TimeSlice, sched Scheduler, processes []Process) error {
+	got := sched.Schedule(processes).Gantt
+	for i := 0; i < len(expected) || i < len(got); i++ {
+		switch {
+		case i >= len(got):
+			return fmt.Errorf("%w: %s: slice %d: want %s, got end of schedule", ErrGanttMismatch, sched.Name(), i, sliceString(expected[i]))
+		case i >= len(expected):
+			return fmt.Errorf("%w: %s: slice %d: want end of schedule, got %s", ErrGanttMismatch, sched.Name(), i, sliceString(got[i]))
+		case got[i] != expected[i]:
+			return fmt.Errorf("%w: %s: slice %d: want %s, got %s", ErrGanttMismatch, sched.Name(), i, sliceString(expected[i]), sliceString(got[i]))
+		}
+	}
+	return nil
+}
+
+// sliceString formats slice for AssertGantt, naming its core only in a multi-core schedule.
+func sliceString(slice TimeSlice) string {
+	if slice.Core != 0 {
+		return fmt.Sprintf("{PID:%s Start:%d Stop:%d Core:%d}", slice.PID, slice.Start, slice.Stop, slice.Core)
+	}
+	return fmt.Sprintf("{PID:%s Start:%d Stop:%d}", slice.PID, slice.Start, slice.Stop)
+}
+
+// ErrTimingMismatch is wrapped by the error VerifyTurnaround returns.
+var ErrTimingMismatch = errors.New("turnaround is not waiting plus burst")
+
+// VerifyTurnaround checks that every row of r has a turnaround equal to its waiting time plus
+// its burst plus the time it was blocked for I/O, in r's ticks, which holds for any schedule.
+// It returns an error naming the first process that breaks it, or nil.
+func VerifyTurnaround(r ScheduleResult) error {
+	for _, row := range r.Rows {
+		burst := row.Process.BurstDuration * max(1, r.Resolution)
+		if row.Turnaround != row.Waiting+burst+row.Blocked {
+			return fmt.Errorf("%w: %s: turnaround %d, waiting %d, burst %d, blocked %d",
+				ErrTimingMismatch, row.Process.ProcessID, row.Turnaround, row.Waiting, burst, row.Blocked)
+		}
+	}
+	return nil
+}
+
+// ErrOverlap is wrapped by the error VerifyNoOverlap returns.
+var ErrOverlap = errors.New("slices overlap")
+
+// VerifyNoOverlap checks that no two slices of gantt, the schedule of a single CPU, run at the
+// same time: ordered by Start, each slice starts no earlier than every slice before it stops.
+// Zero-width checkpoint markers hold no CPU and are skipped. It returns an error naming the
+// first overlapping pair, or nil.
+func VerifyNoOverlap(gantt []TimeSlice) error {
+	order := make([]int, 0, len(gantt))
+	for i, slice := range gantt {
+		if slice.Start < slice.Stop {
+			order = append(order, i)
+		}
+	}
+	sort.SliceStable(order, func(a, b int) bool { return gantt[order[a]].Start < gantt[order[b]].Start })
+	var latest TimeSlice // the slice so far that stops last.
+	for k, i := range order {
+		if k > 0 && gantt[i].Start < latest.Stop {
+			return fmt.Errorf("%w: %s and %s", ErrOverlap, sliceString(latest), sliceString(gantt[i]))
+		}
+		latest = gantt[i]
+	}
+	return nil
+}
+
+// VerifyNoOverlapOn is VerifyNoOverlap for a schedule on cores CPUs: each core's slices must not
+// overlap, and every slice must be on one of the cores.
+func VerifyNoOverlapOn(gantt []TimeSlice, cores int) error {
+	tracks := make([][]TimeSlice, max(1, int64(cores)))
+	for _, slice := range gantt {
+		if slice.Core < 0 || slice.Core >= len(tracks) {
+			return fmt.Errorf("%w: %s is not on one of %d cores", ErrOverlap, sliceString(slice), len(tracks))
+		}
+		tracks[slice.Core] = append(tracks[slice.Core], slice)
+	}
+	for _, track := range tracks {
+		if err := VerifyNoOverlap(track); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TieVariation is a summary metric that changed when CheckTies resolved ties differently: Want
+// is its value with the processes in their given order and Got its value with them in Order.
+type TieVariation struct {
+	Metric string
+	Want   float64
+	Got    float64
+	Order  []string
+}
+
+// CheckTies runs s on processes and then on runs shuffles of their order, drawn from seed, and
+// returns every summary metric that differs from the given order's. Each scheduler settles what
+// its own rules leave tied, such as two processes arriving together, by the order the processes
+// were given, so shuffling the order resolves those ties at random. The average wait,
+// average turnaround, throughput and makespan should not depend on how ties were resolved;
+// nil means they did not over these runs.
+func CheckTies(s Scheduler, processes []Process, runs int, seed int64) []TieVariation {
+	metrics := func(r ScheduleResult) []float64 {
+		makespan := float64(r.Makespan) / float64(max(1, r.Resolution))
+		return []float64{r.AvgWait, r.AvgTurnaround, r.Throughput, makespan}
+	}
+	names := []string{"average wait", "average turnaround", "throughput", "makespan"}
+	want := metrics(s.Schedule(append([]Process(nil), processes...)))
+
+	rng := rand.New(rand.NewSource(seed))
+	var variations []TieVariation
+	tried := make(map[string]bool) // orders already run, so each variation is reported once.
+	for run := 0; run < runs; run++ {
+		shuffled := append([]Process(nil), processes...)
+		rng.Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+		order := make([]string, len(shuffled))
+		for i, p := range shuffled {
+			order[i] = p.ProcessID
+		}
+		key := strings.Join(order, "\x00")
+		if tried[key] {
+			continue
+		}
+		tried[key] = true
+		for k, got := range metrics(s.Schedule(shuffled)) {
+			if math.Abs(got-want[k]) <= 1e-9 {
+				continue
+			}
+			variations = append(variations, TieVariation{Metric: names[k], Want: want[k], Got: got, Order: order})
+		}
+	}
+	return variations
+}
+
+// Canonicalize returns r in a canonical form, so that two results describing the same schedule
+// compare equal however they were built:
+//
+//   - the Gantt is ordered by start time, then by core, keeping the given order otherwise;
+//   - a slice that continues the previous slice of the same PID on the same core, starting as it
+//     stops, is merged into it, while zero-width checkpoint markers are kept as they are;
+//   - the rows are ordered by ProcessID, keeping the given order of duplicates.
+//
+// The metrics are left alone. r is not modified.
+func Canonicalize(r ScheduleResult) ScheduleResult {
+	gantt := append([]TimeSlice(nil), r.Gantt...)
+	sort.SliceStable(gantt, func(i, j int) bool {
+		if gantt[i].Start != gantt[j].Start {
+			return gantt[i].Start < gantt[j].Start
+		}
+		return gantt[i].Core < gantt[j].Core
+	})
+	r.Gantt = gantt[:0]
+	last := make(map[int]int) // the index in r.Gantt of the last slice on each core.
+	for _, slice := range gantt {
+		if n, ok := last[slice.Core]; ok && slice.Start < slice.Stop {
+			if prev := &r.Gantt[n]; prev.PID == slice.PID && prev.Stop == slice.Start && prev.Start < prev.Stop {
+				prev.Stop = slice.Stop
+				continue
+			}
+		}
+		last[slice.Core] = len(r.Gantt)
+		r.Gantt = append(r.Gantt, slice)
+	}
+
+	r.Rows = append([]ProcessResult(nil), r.Rows...)
+	sort.SliceStable(r.Rows, func(i, j int) bool {
+		return r.Rows[i].Process.ProcessID < r.Rows[j].Process.ProcessID
+	})
+	return r
+}
+
+// DiffResults describes how a and b differ once both are canonicalized: each slice of the Gantt
+// and each row that differs, then each summary metric that differs as printed. It returns nil if
+// they describe the same schedule.
+func DiffResults(a, b ScheduleResult) []string {
+	a, b = Canonicalize(a), Canonicalize(b)
+	var diffs []string
+	for i := 0; i < len(a.Gantt) || i < len(b.Gantt); i++ {
+		as, bs := "none", "none"
+		if i < len(a.Gantt) {
+			as = sliceString(a.Gantt[i])
+		}
+		if i < len(b.Gantt) {
+			bs = sliceString(b.Gantt[i])
+		}
+		if as != bs {
+			diffs = append(diffs, fmt.Sprintf("gantt[%d]: %s vs %s", i, as, bs))
+		}
+	}
+	for i := 0; i < len(a.Rows) || i < len(b.Rows); i++ {
+		as, bs := "none", "none"
+		if i < len(a.Rows) {
+			as = rowString(a.Rows[i])
+		}
+		if i < len(b.Rows) {
+			bs = rowString(b.Rows[i])
+		}
+		if as != bs {
+			diffs = append(diffs, fmt.Sprintf("rows[%d]: %s vs %s", i, as, bs))
+		}
+	}
+	for _, m := range []struct {
+		name string
+		a, b string
+	}{
+		{"average wait", fmt.Sprintf("%.2f", a.AvgWait), fmt.Sprintf("%.2f", b.AvgWait)},
+		{"average turnaround", fmt.Sprintf("%.2f", a.AvgTurnaround), fmt.Sprintf("%.2f", b.AvgTurnaround)},
+		{"throughput", fmt.Sprintf("%.2f", a.Throughput), fmt.Sprintf("%.2f", b.Throughput)},
+		{"makespan", fmt.Sprint(a.Makespan), fmt.Sprint(b.Makespan)},
+		{"resolution", fmt.Sprint(max(1, a.Resolution)), fmt.Sprint(max(1, b.Resolution))},
+	} {
+		if m.a != m.b {
+			diffs = append(diffs, fmt.Sprintf("%s: %s vs %s", m.name, m.a, m.b))
+		}
+	}
+	return diffs
+}
+
+// rowString formats row for DiffResults.
+func rowString(row ProcessResult) string {
+	return fmt.Sprintf("%s wait %d turnaround %d completion %d",
+		row.Process.ProcessID, row.Waiting, row.Turnaround, row.Completion)
+}
+
+// MissedDeadlines returns the IDs of r's processes that have a Deadline and completed after it,
+// in the order of r's rows.
+func MissedDeadlines(r ScheduleResult) []string {
+	var late []string
+	for _, row := range r.Rows {
+		if missesDeadline(row, r.Resolution) {
+			late = append(late, row.Process.ProcessID)
+		}
+	}
+	return late
+}
+
+// missesDeadline reports whether row, timed in ticks of 1/resolution units, completed after its
+// process's deadline.
+func missesDeadline(row ProcessResult, resolution int64) bool {
+	return row.Process.Deadline > 0 && row.Completion > row.Process.Deadline*max(1, resolution)
+}
+
+// MissedResponseDeadlines returns the IDs of r's processes that have a ResponseDeadline and
+// first reached the CPU after it, in the order of r's rows.
+func MissedResponseDeadlines(r ScheduleResult) []string {
+	var late []string
+	for i, response := range ResponseTimes(r) {
+		p := r.Rows[i].Process
+		if p.ResponseDeadline > 0 && response > p.ResponseDeadline*max(1, r.Resolution) {
+			late = append(late, p.ProcessID)
+		}
+	}
+	return late
+}
+
+// ResponseTimes returns how long each of r's processes waited from its arrival until it first
+// reached the CPU, in the order of r's rows and in r's ticks. A process's warmup counts as
+// reaching the CPU, and one that never ran, having no work, responds when it completes.
+func ResponseTimes(r ScheduleResult) []int64 {
+	first := make(map[string]int64, len(r.Rows))
+	for _, slice := range r.Gantt {
+		if slice.Start == slice.Stop {
+			continue // checkpoint markers never hold the CPU.
+		}
+		pid := strings.TrimPrefix(slice.PID, warmupPrefix)
+		if t, ok := first[pid]; !ok || slice.Start < t {
+			first[pid] = slice.Start
+		}
+	}
+	responses := make([]int64, len(r.Rows))
+	for i, row := range r.Rows {
+		start, ok := first[row.Process.ProcessID]
+		if !ok {
+			start = row.Completion
+		}
+		responses[i] = start - row.Process.ArrivalTime*max(1, r.Resolution)
+	}
+	return responses
+}
+
+// QuantumTie is an instant, in ticks, at which a process left the CPU before completing while
+// one or more processes that have work Arrived, so the Boundary decided which of them joined the
+// ready queue first.
+type QuantumTie struct {
+	Time      int64
+	Preempted string
+	Arrived   []string
+}
+
+// QuantumTies returns the instants in r at which a process was preempted as others arrived, in
+// time order. A process leaving the CPU to block for I/O is not preempted, and a checkpoint is
+// not counted as arriving.
+func QuantumTies(r ScheduleResult) []QuantumTie {
+	resolution := max(1, r.Resolution)
+	arrived := make(map[int64][]string)
+	rows := make(map[string]ProcessResult, len(r.Rows))
+	for _, row := range r.Rows {
+		rows[row.Process.ProcessID] = row
+		if row.Process.BurstDuration > 0 {
+			t := row.Process.ArrivalTime * resolution
+			arrived[t] = append(arrived[t], row.Process.ProcessID)
+		}
+	}
+
+	var ties []QuantumTie
+	ran := make(map[string]int64, len(r.Rows)) // each process's CPU time so far.
+	for _, slice := range r.Gantt {
+		row, ok := rows[slice.PID]
+		if !ok || slice.Start == slice.Stop {
+			continue // warmups and checkpoint markers.
+		}
+		ran[slice.PID] += slice.Stop - slice.Start
+		if slice.Stop == row.Completion || blocksAt(row.Process, ran[slice.PID], resolution) {
+			continue
+		}
+		if pids := arrived[slice.Stop]; len(pids) > 0 {
+			ties = append(ties, QuantumTie{Time: slice.Stop, Preempted: slice.PID, Arrived: pids})
+		}
+	}
+	return ties
+}
+
+// blocksAt reports whether p blocks for I/O once it has run for ran ticks of 1/resolution units.
+func blocksAt(p Process, ran, resolution int64) bool {
+	for _, b := range p.IO {
+		if b.At*resolution == ran {
+			return true
+		}
+	}
+	return false
+}
+
+// Percentile returns the p-th percentile of values by the nearest-rank method: the smallest
+// value that at least p percent of values are no greater than. p is clamped to [0, 100], with 0
+// giving the minimum. It returns 0 if values is empty, and leaves values as is.
+func Percentile(values []float64, p float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	rank := int(math.Ceil(math.Min(100, p) / 100 * float64(len(sorted))))
+	if rank < 1 {
+		return sorted[0]
+	}
+	return sorted[rank-1]
+}
+
+// TimingPercentiles returns each of percentiles of the waiting and the turnaround times of r's
+// processes, in time units, by Percentile's nearest-rank method.
+func TimingPercentiles(r ScheduleResult, percentiles []float64) (waits, turnarounds []float64) {
+	scale := float64(max(1, r.Resolution))
+	waiting := make([]float64, len(r.Rows))
+	turnaround := make([]float64, len(r.Rows))
+	for i, row := range r.Rows {
+		waiting[i] = float64(row.Waiting) / scale
+		turnaround[i] = float64(row.Turnaround) / scale
+	}
+	for _, p := range percentiles {
+		waits = append(waits, Percentile(waiting, p))
+		turnarounds = append(turnarounds, Percentile(turnaround, p))
+	}
+	return waits, turnarounds
+}
+
+// ResultSignature returns a hex SHA-256 of the canonical form of r's Gantt and its summary
+// metrics rounded to two decimal places, so two runs have the same signature exactly when they
+// produced the same schedule and metrics as printed.
+func ResultSignature(r ScheduleResult) string {
+	h := sha256.New()
+	for _, slice := range Canonicalize(r).Gantt {
+		_, _ = fmt.Fprintf(h, "%q %d %d\n", slice.PID, slice.Start, slice.Stop)
+	}
+	_, _ = fmt.Fprintf(h, "wait=%.2f turnaround=%.2f throughput=%.2f queue=%.2f makespan=%d resolution=%d\n",
+		r.AvgWait, r.AvgTurnaround, r.Throughput, r.AvgQueueLength, r.Makespan, max(1, r.Resolution))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// CriticalPath returns the earliest time every process could complete with unlimited CPUs, a
+// lower bound on any schedule's makespan, and the chain of processes that sets it, from first
+// to run to last. A process starts once it has arrived and its prerequisites have completed, so
+// with no arrival gaps the length is the longest chain of dependent bursts. Ties go to the
+// process given first. Unknown prerequisites are ignored and a cycle, which ValidateProcesses
+// rejects, is broken where it is found.
+func CriticalPath(processes []Process) (length int64, path []string) {
+	byID := make(map[string]int, len(processes))
+	for i, p := range processes {
+		byID[p.ProcessID] = i
+	}
+	var (
+		finish   = make([]int64, len(processes))
+		via      = make([]int, len(processes)) // the prerequisite that gates each start, or -1.
+		visited  = make([]bool, len(processes))
+		visiting = make([]bool, len(processes))
+	)
+	var visit func(i int)
+	visit = func(i int) {
+		if visited[i] || visiting[i] {
+			return
+		}
+		visiting[i] = true
+		start, gate := processes[i].ArrivalTime, -1
+		for _, pid := range processes[i].DependsOn {
+			d, ok := byID[pid]
+			if !ok {
+				continue
+			}
+			visit(d)
+			if visited[d] && finish[d] > start {
+				start, gate = finish[d], d
+			}
+		}
+		finish[i], via[i] = start+processes[i].BurstDuration, gate
+		visiting[i], visited[i] = false, true
+	}
+
+	last := -1
+	for i := range processes {
+		visit(i)
+		if last < 0 || finish[i] > finish[last] {
+			last = i
+		}
+	}
+	if last < 0 {
+		return 0, nil
+	}
+	for i := last; i >= 0; i = via[i] {
+		path = append([]string{processes[i].ProcessID}, path...)
+	}
+	return finish[last], path
+}
+
+//endregion
@@ -0,0 +1,1241 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"math/rand"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestGenerateProcesses(t *testing.T) {
+	t.Parallel()
+	a, b := GenerateProcesses(20, 42), GenerateProcesses(20, 42)
+	if diff := cmp.Diff(a, b); diff != "" {
+		t.Fatalf("same seed produced different workloads: %s", diff)
+	}
+	for i, p := range a {
+		if p.BurstDuration < 1 || p.BurstDuration > 10 {
+			t.Errorf("%s: burst %d out of range", p.ProcessID, p.BurstDuration)
+		}
+		if i > 0 && p.ArrivalTime < a[i-1].ArrivalTime {
+			t.Errorf("%s: not in arrival order", p.ProcessID)
+		}
+	}
+}
+
+func TestGenerateReplayable(t *testing.T) {
+	t.Parallel()
+	opts := GenerateOptions{Arrivals: Poisson}
+	got, seed := GenerateReplayable(20, 42, opts)
+	if seed != 42 {
+		t.Errorf("seed = %d, want 42", seed)
+	}
+	if diff := cmp.Diff(got, GenerateProcessesWith(20, 42, opts)); diff != "" {
+		t.Errorf(diff)
+	}
+
+	// A random seed replays the same workload.
+	random, seed := GenerateReplayable(20, 0, opts)
+	if seed == 0 {
+		t.Fatal("seed 0 was not replaced by a random seed")
+	}
+	again, _ := GenerateReplayable(20, seed, opts)
+	if diff := cmp.Diff(again, random); diff != "" {
+		t.Errorf("seed %d did not replay: %s", seed, diff)
+	}
+}
+
+func TestGenerateProcessesWith(t *testing.T) {
+	t.Parallel()
+	if diff := cmp.Diff(GenerateProcessesWith(20, 42, GenerateOptions{}), GenerateProcesses(20, 42)); diff != "" {
+		t.Errorf("zero options differ from GenerateProcesses: %s", diff)
+	}
+
+	const n = 5000
+	opts := GenerateOptions{Arrivals: Poisson, ArrivalRate: 0.5, Bursts: Exponential, MeanBurst: 4}
+	a := GenerateProcessesWith(n, 7, opts)
+	if diff := cmp.Diff(a, GenerateProcessesWith(n, 7, opts)); diff != "" {
+		t.Fatalf("same seed produced different workloads: %s", diff)
+	}
+	var bursts float64
+	for _, p := range a {
+		if p.BurstDuration < 1 {
+			t.Fatalf("%s: burst %d below 1", p.ProcessID, p.BurstDuration)
+		}
+		bursts += float64(p.BurstDuration)
+	}
+	// Rounding up adds about half a unit to the mean burst.
+	if mean := bursts / n; mean < 4.2 || mean > 4.8 {
+		t.Errorf("mean burst %v, want about 4.5", mean)
+	}
+	if a[0].ArrivalTime != 0 {
+		t.Errorf("first arrival at %d, want 0", a[0].ArrivalTime)
+	}
+	if rate := n / float64(a[n-1].ArrivalTime); rate < 0.45 || rate > 0.55 {
+		t.Errorf("arrival rate %v, want about 0.5", rate)
+	}
+}
+
+func TestGenerateProcessesWith_Bursty(t *testing.T) {
+	t.Parallel()
+	processes := GenerateProcessesWith(9, 5, GenerateOptions{Arrivals: Bursty, ClusterSize: 3, ClusterGap: 20})
+	for i, p := range processes {
+		if start := int64(i/3) * 20; p.ArrivalTime < start || p.ArrivalTime > start+1 {
+			t.Errorf("%s arrives at %d, want within 2 of %d", p.ProcessID, p.ArrivalTime, start)
+		}
+	}
+}
+
+func TestBucketByArrival(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: "P0", ArrivalTime: 0},
+		{ProcessID: "P1", ArrivalTime: 4},
+		{ProcessID: "P2", ArrivalTime: 5},
+		{ProcessID: "P3", ArrivalTime: 3},
+		{ProcessID: "P4", ArrivalTime: 17},
+	}
+	tests := []struct {
+		name       string
+		windowSize int64
+		want       map[int64][]Process
+	}{
+		{
+			name:       "edges start the next window",
+			windowSize: 5,
+			want: map[int64][]Process{
+				0: {processes[0], processes[1], processes[3]},
+				1: {processes[2]},
+				3: {processes[4]},
+			},
+		},
+		{
+			name:       "one window",
+			windowSize: 100,
+			want:       map[int64][]Process{0: processes},
+		},
+		{
+			name:       "invalid window",
+			windowSize: 0,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if diff := cmp.Diff(BucketByArrival(processes, tt.windowSize), tt.want); diff != "" {
+				t.Errorf(diff)
+			}
+		})
+	}
+}
+
+func TestCompareAvgWait(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		a, b Scheduler
+		want func(d WaitDifference) bool
+	}{
+		{
+			name: "same scheduler",
+			a:    FCFS{},
+			b:    FCFS{},
+			want: func(d WaitDifference) bool { return d == WaitDifference{} },
+		},
+		{
+			name: "huge quantum round-robin",
+			a:    RR{Quantum: 1 << 40},
+			b:    FCFS{},
+			want: func(d WaitDifference) bool { return d == WaitDifference{} },
+		},
+		{
+			name: "interval contains mean",
+			a:    RR{Quantum: 1},
+			b:    FCFS{},
+			want: func(d WaitDifference) bool { return d.Low <= d.Mean && d.Mean <= d.High && d.Low < d.High },
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			d, err := CompareAvgWait(tt.a, tt.b, 30, 8, 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !tt.want(d) {
+				t.Errorf("unexpected difference %+v", d)
+			}
+			if again, _ := CompareAvgWait(tt.a, tt.b, 30, 8, 1); again != d {
+				t.Errorf("not reproducible: %+v != %+v", again, d)
+			}
+		})
+	}
+
+	// One workload gives a difference without an interval.
+	if d, err := CompareAvgWait(RR{Quantum: 1}, FCFS{}, 1, 8, 1); err != nil || d.Low != d.Mean || d.High != d.Mean || math.IsNaN(d.Mean) {
+		t.Errorf("one workload: %+v, %v", d, err)
+	}
+	for _, size := range [][2]int{{0, 8}, {-1, 8}, {30, 0}} {
+		if _, err := CompareAvgWait(FCFS{}, SJF{}, size[0], size[1], 1); !errors.Is(err, ErrInvalidArgs) {
+			t.Errorf("%d workloads of %d: error = %v, want %v", size[0], size[1], err, ErrInvalidArgs)
+		}
+	}
+}
+
+func TestVaryArrivals(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: "A", BurstDuration: 8, Priority: 2},
+		{ProcessID: "B", BurstDuration: 1, Priority: 1},
+		{ProcessID: "C", BurstDuration: 3, Priority: 3},
+	}
+	input := append([]Process(nil), processes...)
+	d := VaryArrivals(FCFS{}, processes, 25, 3, GenerateOptions{})
+	if diff := cmp.Diff(processes, input); diff != "" {
+		t.Fatalf("input modified: %s", diff)
+	}
+	if len(d.Waits) != 25 {
+		t.Fatalf("got %d waits, want 25", len(d.Waits))
+	}
+	if !(d.Min <= d.Median && d.Median <= d.Max && d.Min < d.Max && d.StdDev > 0) {
+		t.Errorf("inconsistent distribution %+v", d)
+	}
+	var sum float64
+	for _, wait := range d.Waits {
+		sum += wait
+	}
+	if math.Abs(sum/25-d.Mean) > 1e-9 {
+		t.Errorf("mean %v, want %v", d.Mean, sum/25)
+	}
+	if diff := cmp.Diff(VaryArrivals(FCFS{}, processes, 25, 3, GenerateOptions{}), d); diff != "" {
+		t.Errorf("not reproducible: %s", diff)
+	}
+
+	// Only the arrivals vary: the first workload is the input with the generator's arrivals.
+	rng := rand.New(rand.NewSource(3))
+	first := append([]Process(nil), processes...)
+	for i, p := range GenerateProcessesWith(len(first), rng.Int63(), GenerateOptions{}) {
+		first[i].ArrivalTime = p.ArrivalTime
+	}
+	if want := (FCFS{}).Schedule(first).AvgWait; d.Waits[0] != want {
+		t.Errorf("first workload waits %v, want %v", d.Waits[0], want)
+	}
+
+	// A lone process never waits, wherever it arrives.
+	if got := VaryArrivals(SJF{}, processes[:1], 4, 1, GenerateOptions{Arrivals: Poisson}); got.Max != 0 || got.StdDev != 0 {
+		t.Errorf("lone process: %+v", got)
+	}
+	if got := VaryArrivals(FCFS{}, processes, 0, 1, GenerateOptions{}); got.Waits != nil {
+		t.Errorf("no workloads: %+v", got)
+	}
+}
+
+func TestResponseTimes(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: "P0", ArrivalTime: 0, BurstDuration: 4},
+		{ProcessID: "P1", ArrivalTime: 1, BurstDuration: 2},
+		{ProcessID: "P2", ArrivalTime: 1, BurstDuration: 1},
+		{ProcessID: "P3", ArrivalTime: 2},
+	}
+	tests := []struct {
+		s    Scheduler
+		want []int64
+	}{
+		// P3 has no work, so it responds when it completes.
+		{s: FCFS{}, want: []int64{0, 3, 5, 5}},
+		// Round-robin reaches P1 at 1, P2 at 2 and P3 at 4, in tenths of a unit.
+		{s: RR{Quantum: 10, Resolution: 10}, want: []int64{0, 0, 10, 20}},
+		{s: SRTF{}, want: []int64{0, 1, 0, 0}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.s.Name(), func(t *testing.T) {
+			t.Parallel()
+			if diff := cmp.Diff(ResponseTimes(tt.s.Schedule(processes)), tt.want); diff != "" {
+				t.Errorf(diff)
+			}
+		})
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	t.Parallel()
+	values := []float64{7, 1, 3, 9, 5, 2, 8, 4, 6, 10}
+	tests := []struct {
+		p    float64
+		want float64
+	}{
+		{p: 0, want: 1},
+		{p: 10, want: 1},
+		{p: 11, want: 2},
+		{p: 50, want: 5},
+		{p: 95, want: 10},
+		{p: 100, want: 10},
+		{p: 150, want: 10},
+	}
+	for _, tt := range tests {
+		if got := Percentile(values, tt.p); got != tt.want {
+			t.Errorf("Percentile(%v) = %v, want %v", tt.p, got, tt.want)
+		}
+	}
+	if values[0] != 7 {
+		t.Errorf("Percentile sorted its input")
+	}
+	if got := Percentile(nil, 50); got != 0 {
+		t.Errorf("empty Percentile = %v, want 0", got)
+	}
+}
+
+func TestTimingPercentiles(t *testing.T) {
+	t.Parallel()
+	// Round-robin with a quantum longer than any burst waits 0, 4, 5 and 5 units and turns
+	// around in 4, 6, 6 and 6, though it counts in tenths of a unit.
+	processes := []Process{
+		{ProcessID: "P0", ArrivalTime: 0, BurstDuration: 4},
+		{ProcessID: "P1", ArrivalTime: 0, BurstDuration: 2},
+		{ProcessID: "P2", ArrivalTime: 1, BurstDuration: 1},
+		{ProcessID: "P3", ArrivalTime: 2, BurstDuration: 1},
+	}
+	r := RR{Quantum: 100, Resolution: 10}.Schedule(processes)
+	waits, turnarounds := TimingPercentiles(r, []float64{25, 50, 100})
+	if diff := cmp.Diff(waits, []float64{0, 4, 5}); diff != "" {
+		t.Errorf("waits: %s", diff)
+	}
+	if diff := cmp.Diff(turnarounds, []float64{4, 6, 6}); diff != "" {
+		t.Errorf("turnarounds: %s", diff)
+	}
+
+	var w bytes.Buffer
+	outputResult(&w, "Percentiles", r, RenderOptions{Percentiles: []float64{50, 99.9}})
+	for _, want := range []string{"Wait percentiles: p50 4 p99.9 5\n", "Turnaround percentiles: p50 6 p99.9 6\n"} {
+		if !strings.Contains(w.String(), want) {
+			t.Errorf("output is missing %q:\n%s", want, w.String())
+		}
+	}
+}
+
+func TestBurstyResponse(t *testing.T) {
+	t.Parallel()
+	got := BurstyResponse(20, 4, 2)
+	if len(got) != 2 || got[0].Algo != "rr" || got[1].Algo != "srtf" {
+		t.Fatalf("got %+v, want rr then srtf", got)
+	}
+	for _, s := range got {
+		var values []float64
+		var sum float64
+		for _, t := range ResponseTimes(s.Result) {
+			values = append(values, float64(t))
+			sum += float64(t)
+		}
+		if math.Abs(s.Avg-sum/20) > 1e-9 || s.P95 != Percentile(values, 95) {
+			t.Errorf("%s: average %v and p95 %v, want %v and %v", s.Algo, s.Avg, s.P95, sum/20, Percentile(values, 95))
+		}
+		if s.P95 < s.Avg {
+			t.Errorf("%s: p95 %v below average %v", s.Algo, s.P95, s.Avg)
+		}
+	}
+	if diff := cmp.Diff(BurstyResponse(20, 4, 2), got); diff != "" {
+		t.Errorf("not reproducible: %s", diff)
+	}
+}
+
+func TestBestByAvgWait(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		processes []Process
+		want      string
+		wantWait  float64
+	}{
+		{
+			name: "convoy",
+			processes: []Process{
+				{ProcessID: "P0", ArrivalTime: 0, BurstDuration: 1},
+				{ProcessID: "P1", ArrivalTime: 1, BurstDuration: 10},
+				{ProcessID: "P2", ArrivalTime: 1, BurstDuration: 1},
+			},
+			want:     "sjf",
+			wantWait: 1.0 / 3,
+		},
+		{
+			name: "tie goes to fcfs",
+			processes: []Process{
+				{ProcessID: "P0", ArrivalTime: 0, BurstDuration: 2},
+				{ProcessID: "P1", ArrivalTime: 5, BurstDuration: 2},
+			},
+			want: "fcfs",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			algo, r := BestByAvgWait(tt.processes)
+			if algo != tt.want || r.AvgWait != tt.wantWait {
+				t.Errorf("BestByAvgWait() = %s with %v, want %s with %v", algo, r.AvgWait, tt.want, tt.wantWait)
+			}
+		})
+	}
+}
+
+func TestWorkConservationViolations(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: "P0", ArrivalTime: 0, BurstDuration: 2},
+		{ProcessID: "P1", ArrivalTime: 1, BurstDuration: 2},
+		{ProcessID: "P2", ArrivalTime: 9, BurstDuration: 1},
+	}
+	tests := []struct {
+		name  string
+		gantt []TimeSlice
+		want  []IdleViolation
+	}{
+		{
+			name: "conserving",
+			gantt: []TimeSlice{
+				{PID: "P0", Start: 0, Stop: 2},
+				{PID: "P1", Start: 2, Stop: 4},
+				{PID: "P2", Start: 9, Stop: 10},
+			},
+		},
+		{
+			name: "idles with P1 ready",
+			gantt: []TimeSlice{
+				{PID: "P0", Start: 0, Stop: 2},
+				{PID: "P1", Start: 5, Stop: 7},
+				{PID: "P2", Start: 9, Stop: 10},
+			},
+			want: []IdleViolation{{Start: 2, Stop: 5, Ready: []string{"P1"}}},
+		},
+		{
+			name: "late start",
+			gantt: []TimeSlice{
+				{PID: "P1", Start: 1, Stop: 3},
+				{PID: "P0", Start: 3, Stop: 5},
+				{PID: "P2", Start: 9, Stop: 10},
+			},
+			want: []IdleViolation{{Start: 0, Stop: 1, Ready: []string{"P0"}}},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := WorkConservationViolations(processes, tt.gantt)
+			if diff := cmp.Diff(got, tt.want); diff != "" {
+				t.Errorf(diff)
+			}
+		})
+	}
+
+	// P3 has arrived but is blocked until P2 completes, so the CPU idling over [4, 9) is not a
+	// violation.
+	blocked := append(append([]Process(nil), processes...), Process{ProcessID: "P3", BurstDuration: 1, DependsOn: []string{"P2"}})
+	gantt := []TimeSlice{
+		{PID: "P0", Start: 0, Stop: 2},
+		{PID: "P1", Start: 2, Stop: 4},
+		{PID: "P2", Start: 9, Stop: 10},
+		{PID: "P3", Start: 10, Stop: 11},
+	}
+	if v := WorkConservationViolations(blocked, gantt); len(v) > 0 {
+		t.Errorf("dependency: idle while ready: %+v", v)
+	}
+}
+
+func TestSchedulersConserveWork(t *testing.T) {
+	t.Parallel()
+	schedulers := []Scheduler{FCFS{}, SJF{}, SJFPriority{}, RR{Quantum: 2}}
+	for _, s := range schedulers {
+		s := s
+		t.Run(s.Name(), func(t *testing.T) {
+			t.Parallel()
+			for seed := int64(0); seed < 20; seed++ {
+				processes := GenerateProcesses(10, seed)
+				if v := WorkConservationViolations(processes, s.Schedule(processes).Gantt); len(v) > 0 {
+					t.Errorf("seed %d: idle while ready: %+v", seed, v)
+				}
+			}
+		})
+	}
+}
+
+func TestAssertGantt(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: "P0", ArrivalTime: 0, BurstDuration: 3},
+		{ProcessID: "P1", ArrivalTime: 1, BurstDuration: 2},
+	}
+	tests := []struct {
+		name     string
+		expected []TimeSlice
+		wantErr  string
+	}{
+		{
+			name:     "match",
+			expected: []TimeSlice{{PID: "P0", Start: 0, Stop: 3}, {PID: "P1", Start: 3, Stop: 5}},
+		},
+		{
+			name:     "different slice",
+			expected: []TimeSlice{{PID: "P0", Start: 0, Stop: 3}, {PID: "P1", Start: 3, Stop: 6}},
+			wantErr:  "gantt mismatch: fcfs: slice 1: want {PID:P1 Start:3 Stop:6}, got {PID:P1 Start:3 Stop:5}",
+		},
+		{
+			name:     "schedule too long",
+			expected: []TimeSlice{{PID: "P0", Start: 0, Stop: 3}},
+			wantErr:  "gantt mismatch: fcfs: slice 1: want end of schedule, got {PID:P1 Start:3 Stop:5}",
+		},
+		{
+			name:     "schedule too short",
+			expected: []TimeSlice{{PID: "P0", Start: 0, Stop: 3}, {PID: "P1", Start: 3, Stop: 5}, {PID: "P2", Start: 5, Stop: 6}},
+			wantErr:  "gantt mismatch: fcfs: slice 2: want {PID:P2 Start:5 Stop:6}, got end of schedule",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := AssertGantt(tt.expected, FCFS{}, processes)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrGanttMismatch) {
+				t.Fatalf("error %v is not ErrGanttMismatch", err)
+			}
+			if diff := cmp.Diff(err.Error(), tt.wantErr); diff != "" {
+				t.Errorf(diff)
+			}
+		})
+	}
+}
+
+func TestResultSignature(t *testing.T) {
+	t.Parallel()
+	processes := GenerateProcesses(10, 3)
+	fcfsSig := ResultSignature(FCFS{}.Schedule(processes))
+	if again := ResultSignature(FCFS{}.Schedule(processes)); again != fcfsSig {
+		t.Errorf("same schedule, different signatures %s and %s", fcfsSig, again)
+	}
+	// A round-robin quantum longer than any burst schedules exactly as FCFS.
+	if rrSig := ResultSignature(RR{Quantum: 100}.Schedule(processes)); rrSig != fcfsSig {
+		t.Errorf("identical schedules, different signatures %s and %s", fcfsSig, rrSig)
+	}
+	if rrSig := ResultSignature(RR{Quantum: 1}.Schedule(processes)); rrSig == fcfsSig {
+		t.Errorf("different schedules share signature %s", rrSig)
+	}
+	if got := ResultSignature(ScheduleResult{Resolution: 1}); got != ResultSignature(ScheduleResult{}) {
+		t.Errorf("resolutions 0 and 1 should sign the same")
+	}
+}
+
+func TestCanonicalize(t *testing.T) {
+	t.Parallel()
+	r := ScheduleResult{
+		Gantt: []TimeSlice{
+			{PID: "P1", Start: 0, Stop: 2, Core: 1},
+			{PID: "P0", Start: 0, Stop: 2},
+			{PID: "P0", Start: 2, Stop: 4},
+			{PID: "P1", Start: 2, Stop: 3, Core: 1},
+			{PID: "P2", Start: 4, Stop: 4},
+			{PID: "P0", Start: 4, Stop: 5},
+			{PID: "P0", Start: 6, Stop: 7},
+		},
+		Rows: []ProcessResult{
+			{Process: Process{ProcessID: "P2"}},
+			{Process: Process{ProcessID: "P0"}, Completion: 7},
+			{Process: Process{ProcessID: "P1"}, Completion: 3},
+		},
+		Makespan: 7,
+	}
+	want := ScheduleResult{
+		Gantt: []TimeSlice{
+			// Runs on one core merge unless a checkpoint or idle time splits them.
+			{PID: "P0", Start: 0, Stop: 4},
+			{PID: "P1", Start: 0, Stop: 3, Core: 1},
+			{PID: "P2", Start: 4, Stop: 4},
+			{PID: "P0", Start: 4, Stop: 5},
+			{PID: "P0", Start: 6, Stop: 7},
+		},
+		Rows: []ProcessResult{
+			{Process: Process{ProcessID: "P0"}, Completion: 7},
+			{Process: Process{ProcessID: "P1"}, Completion: 3},
+			{Process: Process{ProcessID: "P2"}},
+		},
+		Makespan: 7,
+	}
+	if diff := cmp.Diff(Canonicalize(r), want); diff != "" {
+		t.Errorf(diff)
+	}
+	if r.Gantt[0].PID != "P1" || r.Rows[0].Process.ProcessID != "P2" {
+		t.Errorf("Canonicalize modified its input")
+	}
+	if diff := cmp.Diff(Canonicalize(want), want); diff != "" {
+		t.Errorf("canonical form is not stable: %s", diff)
+	}
+	if ResultSignature(r) != ResultSignature(want) {
+		t.Errorf("equivalent results sign differently")
+	}
+}
+
+func TestDiffResults(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: "P0", ArrivalTime: 0, BurstDuration: 4},
+		{ProcessID: "P1", ArrivalTime: 1, BurstDuration: 1},
+	}
+	fcfs := FCFS{}.Schedule(processes)
+	split := fcfs
+	split.Gantt = []TimeSlice{{PID: "P0", Start: 0, Stop: 1}, {PID: "P0", Start: 1, Stop: 4}, {PID: "P1", Start: 4, Stop: 5}}
+	split.Rows = []ProcessResult{fcfs.Rows[1], fcfs.Rows[0]}
+	if diffs := DiffResults(fcfs, split); diffs != nil {
+		t.Errorf("equivalent results differ: %q", diffs)
+	}
+
+	want := []string{
+		"gantt[0]: {PID:P0 Start:0 Stop:4} vs {PID:P0 Start:0 Stop:1}",
+		"gantt[1]: {PID:P1 Start:4 Stop:5} vs {PID:P1 Start:1 Stop:2}",
+		"gantt[2]: none vs {PID:P0 Start:2 Stop:5}",
+		"rows[0]: P0 wait 0 turnaround 4 completion 4 vs P0 wait 1 turnaround 5 completion 5",
+		"rows[1]: P1 wait 3 turnaround 4 completion 5 vs P1 wait 0 turnaround 1 completion 2",
+		"average wait: 1.50 vs 0.50",
+		"average turnaround: 4.00 vs 3.00",
+	}
+	if diff := cmp.Diff(DiffResults(fcfs, RR{Quantum: 1}.Schedule(processes)), want); diff != "" {
+		t.Errorf(diff)
+	}
+}
+
+// opaque hides the scheduler it wraps from MeasureDecisions.
+type opaque struct{ Scheduler }
+
+func (o opaque) Schedule(processes []Process) ScheduleResult { return o.Scheduler.Schedule(processes) }
+
+func TestMeasureDecisions(t *testing.T) {
+	t.Parallel()
+	processes := GenerateProcesses(200, 5)
+	schedulers := []Scheduler{
+		FCFS{}, SJF{}, SJFPriority{}, RR{Quantum: 2}, HRRN{Preemptive: true}, UtilityAccrual{},
+		Notify(schedulerFor(sjf, SchedulerConfig{Checkpoints: true}), func(Process, int64) {}),
+	}
+	for _, s := range schedulers {
+		s := s
+		t.Run(s.Name(), func(t *testing.T) {
+			t.Parallel()
+			r, decisions, ok := MeasureDecisions(s, processes)
+			if !ok || decisions <= 0 {
+				t.Errorf("decisions = %v, %v, want a positive duration", decisions, ok)
+			}
+			if diff := cmp.Diff(r, s.Schedule(processes)); diff != "" {
+				t.Errorf("measuring changed the schedule: %s", diff)
+			}
+		})
+	}
+
+	if _, decisions, ok := MeasureDecisions(opaque{FCFS{}}, processes); ok || decisions != 0 {
+		t.Errorf("opaque scheduler: decisions = %v, %v, want 0, false", decisions, ok)
+	}
+}
+
+func TestMeasureDecisionsWith(t *testing.T) {
+	t.Parallel()
+	// Every decision reads the clock twice, so it takes exactly one tick.
+	processes := []Process{
+		{ProcessID: "P0", ArrivalTime: 0, BurstDuration: 4},
+		{ProcessID: "P1", ArrivalTime: 1, BurstDuration: 2},
+		{ProcessID: "P2", ArrivalTime: 9, BurstDuration: 1},
+	}
+	_, got, ok := MeasureDecisionsWith(FCFS{}, processes, &fakeClock{tick: time.Millisecond})
+	_, again, _ := MeasureDecisionsWith(FCFS{}, processes, &fakeClock{tick: time.Millisecond})
+	if !ok || got <= 0 || got != again {
+		t.Errorf("decisions = %v and %v, %v, want the same positive duration", got, again, ok)
+	}
+	if got%time.Millisecond != 0 {
+		t.Errorf("decisions = %v, want whole ticks", got)
+	}
+}
+
+func TestVerifyTurnaround(t *testing.T) {
+	t.Parallel()
+	schedulers := []Scheduler{
+		FCFS{}, SJF{}, SJFPriority{}, SJFPriority{SchedulerConfig{HigherPriorityFirst: true}},
+		RR{Quantum: 1}, RR{Quantum: 3}, RR{Quantum: 5, Resolution: 10},
+		RR{Quantum: 2, SwitchPenalty: func(Process, int64) int64 { return 1 }},
+		HRRN{}, HRRN{Preemptive: true}, UtilityAccrual{}, FairShare{Quantum: 2}, MultiCore{Cores: 3},
+		Decay{Quantum: 1, Usage: 2, Recovery: 1}, SRTF{}, PreemptivePriority{}, GangRR{Cores: 3, Quantum: 2},
+		EDF{}, EDF{Cores: 3}, RRIO{Quantum: 2}, MLFQ{Quanta: []int64{1, 2, 4}, Boost: 10}, PowerDown{Scheduler: RR{Quantum: 5, Resolution: 10}, Threshold: 1, WakeLatency: 2},
+		Dropout{Scheduler: SRTF{}, Fraction: 0.5, Seed: 2},
+		schedulerFor(fcfs, SchedulerConfig{Checkpoints: true}),
+	}
+	for _, s := range schedulers {
+		s := s
+		t.Run(s.Name(), func(t *testing.T) {
+			t.Parallel()
+			for seed := int64(0); seed < 10; seed++ {
+				if err := VerifyTurnaround(s.Schedule(GenerateProcesses(15, seed))); err != nil {
+					t.Errorf("seed %d: %v", seed, err)
+				}
+			}
+		})
+	}
+
+	bad := FCFS{}.Schedule([]Process{{ProcessID: "P0", BurstDuration: 2}})
+	bad.Rows[0].Waiting++
+	if err := VerifyTurnaround(bad); !errors.Is(err, ErrTimingMismatch) {
+		t.Errorf("got %v, want ErrTimingMismatch", err)
+	}
+}
+
+func TestVerifyNoOverlap(t *testing.T) {
+	t.Parallel()
+	single := []Scheduler{
+		FCFS{}, SJF{}, SJFPriority{}, RR{Quantum: 1}, RR{Quantum: 5, Resolution: 10},
+		RR{Quantum: 2, SwitchPenalty: func(Process, int64) int64 { return 1 }},
+		HRRN{}, HRRN{Preemptive: true}, UtilityAccrual{}, FairShare{Quantum: 2},
+		Decay{Quantum: 1, Usage: 2, Recovery: 1}, SRTF{}, PreemptivePriority{}, EDF{},
+		Custom{Select: SelectShortest}, CustomPreemptive{Select: SelectShortest}, RRIO{Quantum: 2},
+		MLFQ{Quanta: []int64{1, 2, 4}, Boost: 10}, schedulerFor(fcfs, SchedulerConfig{Checkpoints: true}),
+	}
+	multi := []Scheduler{
+		MultiCore{Cores: 3}, MultiCore{Cores: 3, Dispatch: LeastLoaded}, MultiCore{Cores: 3, Backfill: true},
+		GangRR{Cores: 3, Quantum: 2}, EDF{Cores: 3},
+	}
+	for _, s := range append(single, multi...) {
+		s := s
+		t.Run(s.Name(), func(t *testing.T) {
+			t.Parallel()
+			for seed := int64(0); seed < 10; seed++ {
+				r := s.Schedule(GenerateProcesses(15, seed))
+				err := VerifyNoOverlapOn(r.Gantt, r.Cores)
+				if r.Cores == 0 {
+					err = VerifyNoOverlap(r.Gantt)
+				}
+				if err != nil {
+					t.Errorf("seed %d: %v", seed, err)
+				}
+			}
+		})
+	}
+
+	tests := []struct {
+		name    string
+		gantt   []TimeSlice
+		cores   int
+		wantErr string
+	}{
+		{name: "back to back", gantt: []TimeSlice{{PID: "P1", Start: 2, Stop: 5}, {PID: "P0", Start: 0, Stop: 2}}},
+		{name: "checkpoint inside a slice", gantt: []TimeSlice{{PID: "P0", Start: 0, Stop: 4}, {PID: "C", Start: 2, Stop: 2}}},
+		{
+			name:    "overlap",
+			gantt:   []TimeSlice{{PID: "P0", Start: 0, Stop: 3}, {PID: "P1", Start: 2, Stop: 5}},
+			wantErr: "slices overlap: {PID:P0 Start:0 Stop:3} and {PID:P1 Start:2 Stop:5}",
+		},
+		{
+			name:    "nested",
+			gantt:   []TimeSlice{{PID: "P0", Start: 0, Stop: 10}, {PID: "P1", Start: 10, Stop: 12}, {PID: "P2", Start: 4, Stop: 6}},
+			wantErr: "slices overlap: {PID:P0 Start:0 Stop:10} and {PID:P2 Start:4 Stop:6}",
+		},
+		{
+			name:  "separate cores",
+			gantt: []TimeSlice{{PID: "P0", Start: 0, Stop: 3}, {PID: "P1", Start: 1, Stop: 5, Core: 1}},
+			cores: 2,
+		},
+		{
+			name:    "same core",
+			gantt:   []TimeSlice{{PID: "P0", Start: 0, Stop: 3, Core: 1}, {PID: "P1", Start: 1, Stop: 5, Core: 1}},
+			cores:   2,
+			wantErr: "slices overlap: {PID:P0 Start:0 Stop:3 Core:1} and {PID:P1 Start:1 Stop:5 Core:1}",
+		},
+		{
+			name:    "missing core",
+			gantt:   []TimeSlice{{PID: "P0", Start: 0, Stop: 3, Core: 2}},
+			cores:   2,
+			wantErr: "slices overlap: {PID:P0 Start:0 Stop:3 Core:2} is not on one of 2 cores",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := VerifyNoOverlapOn(tt.gantt, tt.cores)
+			if tt.cores == 0 {
+				err = VerifyNoOverlap(tt.gantt)
+			}
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Error(err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrOverlap) || err.Error() != tt.wantErr {
+				t.Errorf("got %v, want %s", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckTies(t *testing.T) {
+	t.Parallel()
+	// SJF and SJF with priority only tie processes with the same burst, so the order they run in
+	// cannot change the totals.
+	for _, s := range []Scheduler{SJF{}, SJFPriority{}} {
+		for seed := int64(0); seed < 5; seed++ {
+			if v := CheckTies(s, GenerateProcesses(15, seed), 10, seed); v != nil {
+				t.Errorf("%s, seed %d: %+v", s.Name(), seed, v)
+			}
+		}
+	}
+
+	// FCFS runs A or B first as the tie falls, and A first waits only 1 in all.
+	processes := []Process{
+		{ProcessID: "A", ArrivalTime: 0, BurstDuration: 1},
+		{ProcessID: "B", ArrivalTime: 0, BurstDuration: 5},
+	}
+	want := []TieVariation{
+		{Metric: "average wait", Want: 0.5, Got: 2.5, Order: []string{"B", "A"}},
+		{Metric: "average turnaround", Want: 3.5, Got: 5.5, Order: []string{"B", "A"}},
+	}
+	if diff := cmp.Diff(want, CheckTies(FCFS{}, processes, 10, 1)); diff != "" {
+		t.Errorf(diff)
+	}
+}
+
+func TestOptimalRRQuantum(t *testing.T) {
+	t.Parallel()
+	// Short jobs behind a long one: a quantum of 2 runs each short job in one slice right after
+	// the long job's first, which a smaller quantum delays and a larger one pushes back.
+	processes := []Process{
+		{ProcessID: "P0", ArrivalTime: 0, BurstDuration: 8},
+		{ProcessID: "P1", ArrivalTime: 0, BurstDuration: 2},
+		{ProcessID: "P2", ArrivalTime: 0, BurstDuration: 2},
+	}
+	tests := []struct {
+		name         string
+		lo, hi, step int64
+		want         int64
+	}{
+		{name: "full range", lo: 1, hi: 10, step: 1, want: 2},
+		{name: "stepped", lo: 3, hi: 9, step: 3, want: 3},
+		{name: "zero lower bound", lo: 0, hi: 1, step: 1, want: 1},
+		{name: "empty range", lo: 5, hi: 4, step: 1, want: 0},
+		{name: "bad step", lo: 1, hi: 10, step: 0, want: 0},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := OptimalRRQuantum(processes, tt.lo, tt.hi, tt.step); got != tt.want {
+				t.Errorf("OptimalRRQuantum() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompareAll_Concurrent(t *testing.T) {
+	t.Parallel()
+	// CompareAll's goroutines, and several CompareAll calls at once, share processes; go test
+	// -race reports any of them writing to it.
+	processes := GenerateProcesses(20, 7)
+	processes[3].DependsOn = []string{processes[1].ProcessID}
+	input := append([]Process(nil), processes...)
+	cfg := SchedulerConfig{Quantum: 2}
+	var want []ScheduleResult
+	for _, a := range []Algorithm{fcfs, sjf, sjfp, rr} {
+		want = append(want, schedulerFor(a, cfg).Schedule(append([]Process(nil), processes...)))
+	}
+
+	got := make([][]Comparison, 4)
+	var wg sync.WaitGroup
+	for n := range got {
+		wg.Add(1)
+		go func(n int) {
+			defer wg.Done()
+			got[n] = CompareAll(processes, cfg)
+		}(n)
+	}
+	wg.Wait()
+	if diff := cmp.Diff(input, processes); diff != "" {
+		t.Fatalf("input modified: %s", diff)
+	}
+	for _, comparisons := range got {
+		for k, c := range comparisons {
+			if diff := cmp.Diff(want[k], c.Result); diff != "" {
+				t.Errorf("%s: %s", c.Algo, diff)
+			}
+		}
+	}
+}
+
+func TestMeasureConvoy(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		processes []Process
+		want      Convoy
+		line      string
+	}{
+		{
+			// The short processes queue behind P0 under FCFS; SJF runs them first.
+			name: "convoy",
+			processes: []Process{
+				{ProcessID: "P0", ArrivalTime: 0, BurstDuration: 10},
+				{ProcessID: "P1", ArrivalTime: 0, BurstDuration: 1},
+				{ProcessID: "P2", ArrivalTime: 0, BurstDuration: 1},
+				{ProcessID: "P3", ArrivalTime: 0, BurstDuration: 1},
+			},
+			want: Convoy{Short: []string{"P1", "P2", "P3"}, Wait: 11, SJFWait: 1},
+			line: "Convoy effect: short processes (P1, P2, P3) wait 11.00 under fcfs and 1.00 under sjf, 10.00 more\n",
+		},
+		{
+			name: "equal bursts",
+			processes: []Process{
+				{ProcessID: "P0", ArrivalTime: 0, BurstDuration: 2},
+				{ProcessID: "P1", ArrivalTime: 0, BurstDuration: 2},
+			},
+			want: Convoy{},
+			line: "Convoy effect: none, no burst is below the average\n",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := MeasureConvoy(FCFS{}.Schedule(tt.processes))
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf(diff)
+			}
+			var w bytes.Buffer
+			outputConvoy(&w, "fcfs", got)
+			if diff := cmp.Diff(tt.line, w.String()); diff != "" {
+				t.Errorf("outputConvoy: %s", diff)
+			}
+		})
+	}
+	if c := MeasureConvoy(SJF{}.Schedule(tests[0].processes)); c.Magnitude() != 0 {
+		t.Errorf("SJF convoy = %v, want 0", c.Magnitude())
+	}
+}
+
+func TestCompareAll(t *testing.T) {
+	t.Parallel()
+	// FCFS waits 0, 9 and 18; SJF runs P2 before P1 and waits 0, 10 and 9.
+	processes := []Process{
+		{ProcessID: "P0", ArrivalTime: 0, BurstDuration: 10},
+		{ProcessID: "P1", ArrivalTime: 1, BurstDuration: 9},
+		{ProcessID: "P2", ArrivalTime: 1, BurstDuration: 1},
+	}
+	got := CompareAll(processes, SchedulerConfig{Quantum: 2})
+	var algos []string
+	for _, c := range got {
+		algos = append(algos, c.Algo)
+	}
+	if diff := cmp.Diff(algos, []string{"fcfs", "sjf", "sjfp", "rr"}); diff != "" {
+		t.Fatalf(diff)
+	}
+	if got[0].VsFCFS != 0 {
+		t.Errorf("FCFS vs itself = %v, want 0", got[0].VsFCFS)
+	}
+	if want := 100 * (9 - 19.0/3) / 9; math.Abs(got[1].VsFCFS-want) > 1e-9 {
+		t.Errorf("SJF vs FCFS = %v, want %v", got[1].VsFCFS, want)
+	}
+
+	// The non-preemptive algorithms switch once per process after the first and never preempt.
+	for _, c := range got[:3] {
+		if c.Result.ContextSwitches != len(processes)-1 || c.Preemptions != 0 {
+			t.Errorf("%s: %d switches and %d preemptions, want %d and 0", c.Algo, c.Result.ContextSwitches, c.Preemptions, len(processes)-1)
+		}
+	}
+	if rr := got[3]; rr.Preemptions == 0 || rr.Result.ContextSwitches <= rr.Preemptions {
+		t.Errorf("rr: %d switches and %d preemptions", rr.Result.ContextSwitches, rr.Preemptions)
+	}
+
+	var w bytes.Buffer
+	outputComparison(&w, got, RenderOptions{})
+	if !strings.Contains(w.String(), "| rr        | 7.33 |      14.00 |       0.15 |       20 |    1.00 |       10 |           8 | +18.52%      |") {
+		t.Errorf("unexpected comparison table:\n%s", w.String())
+	}
+}
+
+func Test_outputProcessComparison(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: "P0", ArrivalTime: 0, BurstDuration: 4},
+		{ProcessID: "P1", ArrivalTime: 1, BurstDuration: 1},
+	}
+	var w bytes.Buffer
+	outputProcessComparison(&w, CompareAll(processes, SchedulerConfig{Quantum: 5, Resolution: 10}))
+	want := []string{
+		"Per-process comparison",
+		"+----+-----------+-----------------+----------+----------------+-----------+-----------------+---------+---------------+",
+		"| ID | FCFS WAIT | FCFS TURNAROUND | SJF WAIT | SJF TURNAROUND | SJFP WAIT | SJFP TURNAROUND | RR WAIT | RR TURNAROUND |",
+		"+----+-----------+-----------------+----------+----------------+-----------+-----------------+---------+---------------+",
+		"| P0 |         0 |               4 |        0 |              4 |         0 |               4 |       1 |             5 |",
+		"| P1 |         3 |               4 |        3 |              4 |         3 |               4 |     0.5 |           1.5 |",
+		"+----+-----------+-----------------+----------+----------------+-----------+-----------------+---------+---------------+",
+	}
+	if diff := cmp.Diff(strings.Join(want, "\n")+"\n", w.String()); diff != "" {
+		t.Errorf(diff)
+	}
+}
+
+func TestMissedResponseDeadlines(t *testing.T) {
+	t.Parallel()
+	// Every process should first run within a time unit or two of arriving, which only a
+	// short round-robin quantum manages.
+	processes := []Process{
+		{ProcessID: "A", ArrivalTime: 0, BurstDuration: 4, ResponseDeadline: 1},
+		{ProcessID: "B", ArrivalTime: 0, BurstDuration: 2, ResponseDeadline: 1},
+		{ProcessID: "C", ArrivalTime: 1, BurstDuration: 1, ResponseDeadline: 2},
+	}
+	tests := []struct {
+		s        Scheduler
+		wantLate []string
+	}{
+		{s: FCFS{}, wantLate: []string{"B", "C"}},
+		{s: SJF{}, wantLate: []string{"A"}},
+		{s: RR{Quantum: 1}},
+		{s: RR{Quantum: 10, Resolution: 10}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.s.Name(), func(t *testing.T) {
+			t.Parallel()
+			if diff := cmp.Diff(tt.wantLate, MissedResponseDeadlines(tt.s.Schedule(processes))); diff != "" {
+				t.Errorf(diff)
+			}
+		})
+	}
+
+	var w bytes.Buffer
+	outputSchedule(&w, nil, FCFS{}.Schedule(processes), RenderOptions{})
+	if line := "Response deadline misses: 2 (B, C)\n"; !strings.Contains(w.String(), line) {
+		t.Errorf("summary is missing %q:\n%s", line, w.String())
+	}
+	w.Reset()
+	outputComparison(&w, CompareAll(processes, SchedulerConfig{Quantum: 1}), RenderOptions{})
+	if !strings.Contains(w.String(), "RESPONSE MISSES") || !strings.Contains(w.String(), "| rr        | 2.33 |       4.67 |       0.43 |        7 |    1.00 |        5 |           3 | +22.22%      |               0 |") {
+		t.Errorf("comparison is missing the response misses:\n%s", w.String())
+	}
+}
+
+func TestMeasureLoad(t *testing.T) {
+	t.Parallel()
+	overloaded := []Process{
+		{ProcessID: "P0", ArrivalTime: 0, BurstDuration: 4},
+		{ProcessID: "P1", ArrivalTime: 1, BurstDuration: 4},
+		{ProcessID: "P2", ArrivalTime: 2, BurstDuration: 4},
+	}
+	tests := []struct {
+		name      string
+		s         Scheduler
+		processes []Process
+		want      Load
+		line      string
+	}{
+		{
+			name: "under-loaded",
+			s:    FCFS{},
+			processes: []Process{
+				{ProcessID: "P0", ArrivalTime: 0, BurstDuration: 1},
+				{ProcessID: "P1", ArrivalTime: 4, BurstDuration: 1},
+				{ProcessID: "P2", ArrivalTime: 8, BurstDuration: 1},
+			},
+			want: Load{Span: 8, ArrivalRate: 0.375, ServiceRate: 1, Cores: 1, Rho: 0.375, Throughput: 1.0 / 3},
+			line: "Offered load: ρ = 0.38 (λ 0.38 arrivals, μ 1.00 completions per time unit on 1 core(s)); throughput 0.33 of at most 1.00, under-loaded\n",
+		},
+		{
+			name:      "overloaded",
+			s:         FCFS{},
+			processes: overloaded,
+			want:      Load{Span: 2, ArrivalRate: 1.5, ServiceRate: 0.25, Cores: 1, Rho: 6, Throughput: 0.25},
+			line:      "Offered load: ρ = 6.00 (λ 1.50 arrivals, μ 0.25 completions per time unit on 1 core(s)); throughput 0.25 of at most 0.25, overloaded, ρ ≥ 1 is unstable and waits grow with every arrival\n",
+		},
+		{
+			name:      "two cores",
+			s:         MultiCore{Cores: 2},
+			processes: overloaded,
+			want:      Load{Span: 2, ArrivalRate: 1.5, ServiceRate: 0.25, Cores: 2, Rho: 3, Throughput: 0.375},
+			line:      "Offered load: ρ = 3.00 (λ 1.50 arrivals, μ 0.25 completions per time unit on 2 core(s)); throughput 0.38 of at most 0.50, overloaded, ρ ≥ 1 is unstable and waits grow with every arrival\n",
+		},
+		{
+			name: "simultaneous",
+			s:    FCFS{},
+			processes: []Process{
+				{ProcessID: "P0", ArrivalTime: 0, BurstDuration: 2},
+				{ProcessID: "P1", ArrivalTime: 0, BurstDuration: 2},
+			},
+			want: Load{ArrivalRate: math.Inf(1), ServiceRate: 0.5, Cores: 1, Rho: math.Inf(1), Throughput: 0.5},
+			line: "Offered load: every process arrives at once, so ρ is unbounded; throughput 0.50 of at most 0.50\n",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := MeasureLoad(tt.s.Schedule(tt.processes))
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf(diff)
+			}
+			var w bytes.Buffer
+			outputLoad(&w, got)
+			if diff := cmp.Diff(tt.line, w.String()); diff != "" {
+				t.Errorf("outputLoad: %s", diff)
+			}
+		})
+	}
+}
+
+func TestQuantumTies(t *testing.T) {
+	t.Parallel()
+	// B arrives as A's first quantum expires; D arrives as B completes, which is no tie.
+	processes := []Process{
+		{ProcessID: "A", ArrivalTime: 0, BurstDuration: 4},
+		{ProcessID: "B", ArrivalTime: 2, BurstDuration: 2},
+		{ProcessID: "D", ArrivalTime: 4, BurstDuration: 1},
+	}
+	blocking := append([]Process(nil), processes...)
+	blocking[0].IO = []IOBurst{{At: 2, Duration: 1}}
+	tests := []struct {
+		name      string
+		s         Scheduler
+		processes []Process
+		want      []QuantumTie
+	}{
+		{name: "rr", s: RR{Quantum: 2}, processes: processes, want: []QuantumTie{{Time: 2, Preempted: "A", Arrived: []string{"B"}}}},
+		{name: "rr decision first", s: RR{Quantum: 2, Boundary: DecisionFirst}, processes: processes, want: []QuantumTie{{Time: 2, Preempted: "A", Arrived: []string{"B"}}}},
+		{name: "rr in ticks", s: RR{Quantum: 20, Resolution: 10}, processes: processes, want: []QuantumTie{{Time: 20, Preempted: "A", Arrived: []string{"B"}}}},
+		{name: "fcfs", s: FCFS{}, processes: processes},
+		{name: "blocked for io", s: RRIO{}, processes: blocking},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if diff := cmp.Diff(tt.want, QuantumTies(tt.s.Schedule(tt.processes))); diff != "" {
+				t.Errorf(diff)
+			}
+		})
+	}
+
+	var w bytes.Buffer
+	r := RR{Quantum: 20, Resolution: 10}.Schedule(processes)
+	outputQuantumTies(&w, QuantumTies(r), r.Resolution, DecisionFirst)
+	want := "Quantum boundary ties (preempted process queued first)\n  At 2: B arrived as A was preempted\n"
+	if diff := cmp.Diff(want, w.String()); diff != "" {
+		t.Errorf(diff)
+	}
+}
+
+func TestCriticalPath(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name       string
+		processes  []Process
+		wantLength int64
+		wantPath   []string
+	}{
+		{name: "no processes"},
+		{
+			name: "independent",
+			processes: []Process{
+				{ProcessID: "P0", ArrivalTime: 0, BurstDuration: 3},
+				{ProcessID: "P1", ArrivalTime: 2, BurstDuration: 2},
+			},
+			wantLength: 4,
+			wantPath:   []string{"P1"},
+		},
+		{
+			name: "longest chain",
+			processes: []Process{
+				{ProcessID: "A", BurstDuration: 2},
+				{ProcessID: "B", BurstDuration: 5},
+				{ProcessID: "C", BurstDuration: 1, DependsOn: []string{"A", "B"}},
+				{ProcessID: "D", BurstDuration: 3, DependsOn: []string{"C"}},
+				{ProcessID: "E", BurstDuration: 4, DependsOn: []string{"A"}},
+			},
+			wantLength: 9,
+			wantPath:   []string{"B", "C", "D"},
+		},
+		{
+			name: "late arrival gates the chain",
+			processes: []Process{
+				{ProcessID: "A", BurstDuration: 2},
+				{ProcessID: "B", ArrivalTime: 6, BurstDuration: 1, DependsOn: []string{"A"}},
+			},
+			wantLength: 7,
+			wantPath:   []string{"B"},
+		},
+		{
+			name: "cycle",
+			processes: []Process{
+				{ProcessID: "A", BurstDuration: 1, DependsOn: []string{"B"}},
+				{ProcessID: "B", BurstDuration: 2, DependsOn: []string{"A", "missing"}},
+			},
+			wantLength: 3,
+			wantPath:   []string{"B", "A"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			length, path := CriticalPath(tt.processes)
+			if length != tt.wantLength {
+				t.Errorf("length = %d, want %d", length, tt.wantLength)
+			}
+			if diff := cmp.Diff(path, tt.wantPath); diff != "" {
+				t.Errorf(diff)
+			}
+			// No schedule finishes before the critical path.
+			r := FCFS{}.Schedule(tt.processes)
+			if r.Makespan < length {
+				t.Errorf("FCFS makespan %d is shorter than the critical path %d", r.Makespan, length)
+			}
+		})
+	}
+
+	var w bytes.Buffer
+	outputCriticalPath(&w, FCFS{}.Schedule(tests[2].processes))
+	if diff := cmp.Diff(w.String(), "Critical path: 9 (B -> C -> D), makespan 15\n"); diff != "" {
+		t.Errorf(diff)
+	}
+}
+
+func TestAnnotate(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: "P0", ArrivalTime: 0, BurstDuration: 5},
+		{ProcessID: "P1", ArrivalTime: 1, BurstDuration: 2},
+		{ProcessID: "P2", ArrivalTime: 2, BurstDuration: 1},
+	}
+	schedulers := []Scheduler{FCFS{}, SJF{}, RR{Quantum: 2}, RR{Quantum: 5, Resolution: 10}, HRRN{}, Decay{Quantum: 1}}
+	for _, s := range schedulers {
+		s := s
+		t.Run(s.Name(), func(t *testing.T) {
+			t.Parallel()
+			input := append([]Process(nil), processes...)
+			r := s.Schedule(input)
+			got := Annotate(s, input)
+			if &got[0] != &input[0] {
+				t.Errorf("Annotate returned a copy, want processes itself")
+			}
+			for i, p := range got {
+				row := r.Rows[i]
+				if !p.Completed || p.Waiting != int(row.Waiting) || p.Turnaround != int(row.Turnaround) {
+					t.Errorf("%s: completed %v, waiting %d, turnaround %d, want true, %d, %d",
+						p.ProcessID, p.Completed, p.Waiting, p.Turnaround, row.Waiting, row.Turnaround)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package main
+
+import "time"
+
+// Clock is the source of wall-clock time for the features that measure or pace themselves in
+// real time: decision overhead and the animated GANTT chart. Tests substitute a fake Clock so
+// that their output is deterministic.
+type Clock interface {
+	Now() time.Time
+	Sleep(d time.Duration)
+}
+
+// wallClock is the real Clock.
+type wallClock struct{}
+
+func (wallClock) Now() time.Time        { return time.Now() }
+func (wallClock) Sleep(d time.Duration) { time.Sleep(d) }
+
+// clockOrWall returns c, or the real clock if c is nil.
+func clockOrWall(c Clock) Clock {
+	if c == nil {
+		return wallClock{}
+	}
+	return c
+}
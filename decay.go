@@ -0,0 +1,146 @@
+package main
+
+import (
+	"io"
+	"slices"
+)
+
+// Decay schedules processes by an effective priority that, as in classic Unix scheduling,
+// worsens while a process uses the CPU and recovers while it waits. A process's effective
+// priority is its Priority, taken as its nice value, plus a penalty that starts at 0, grows by
+// Usage for every tick it runs and shrinks by Recovery for every tick it waits, never below 0.
+// A lower effective priority is more urgent, and ties go to the process that has been ready
+// longest.
+//
+// Every Quantum ticks, or when the running process completes, the CPU goes to the ready process
+// with the lowest effective priority, which may be the one that just ran. A Quantum <= 0 runs
+// each process to completion.
+type Decay struct {
+	Quantum  int64
+	Usage    float64
+	Recovery float64
+}
+
+func (Decay) Name() string { return "decay" }
+
+// decayed is a penalty after running for ran ticks, having waited for waited ticks beforehand.
+func (s Decay) decayed(penalty float64, waited, ran int64) float64 {
+	penalty -= s.Recovery * float64(waited)
+	if penalty < 0 {
+		penalty = 0
+	}
+	return penalty + s.Usage*float64(ran)
+}
+
+func (s Decay) Schedule(processes []Process) ScheduleResult {
+	return s.scheduleClocked(processes, nil)
+}
+
+func (s Decay) scheduleClocked(processes []Process, clock *decisionClock) ScheduleResult {
+	arr := newArrivals(processes)
+	remaining := make([]int64, len(processes))
+	since := make([]int64, len(processes)) // when each process last left the CPU, or arrived.
+	penalty := make([]float64, len(processes))
+	for i := range processes {
+		remaining[i] = processes[i].BurstDuration
+		since[i] = processes[i].ArrivalTime
+	}
+
+	var (
+		now       int64
+		ready     []int // in the order the processes became ready.
+		queueArea int64 // integral of the ready queue length over time.
+		timings   = make([]processTiming, len(processes))
+		gantt     = make([]TimeSlice, 0, len(processes))
+		dog       watchdog
+		stuck     = unfinished(processes, remaining)
+	)
+	effective := func(i int) float64 {
+		return float64(processes[i].Priority) + s.decayed(penalty[i], now-since[i], 0)
+	}
+	admit := func() {
+		arr.release(now, func(i int) {
+			// Processes that arrived during the last run have been waiting since arrival.
+			queueArea += now - processes[i].ArrivalTime
+			ready = append(ready, i)
+		})
+	}
+	for done := 0; done < len(processes); {
+		admit()
+		if len(ready) == 0 {
+			// CPU is idle until the next arrival.
+			now = arr.idleUntil(now)
+			continue
+		}
+		dog.observe(now, done, stuck)
+		t0 := clock.start()
+		candidates := eligible(ready, processes, -1)
+		i := candidates[0]
+		for _, j := range candidates {
+			if effective(j) < effective(i) {
+				i = j
+			}
+		}
+		k := slices.Index(ready, i)
+		ready = append(ready[:k], ready[k+1:]...)
+		clock.stop(t0)
+
+		run := remaining[i]
+		if s.Quantum > 0 && s.Quantum < run {
+			run = s.Quantum
+		}
+		if n := len(gantt); n > 0 && gantt[n-1].PID == processes[i].ProcessID && gantt[n-1].Stop == now {
+			gantt[n-1].Stop += run
+		} else {
+			gantt = append(gantt, TimeSlice{PID: processes[i].ProcessID, Start: now, Stop: now + run})
+		}
+		queueArea += int64(len(ready)) * run
+		penalty[i] = s.decayed(penalty[i], now-since[i], run)
+		now += run
+		since[i] = now
+		remaining[i] -= run
+
+		if remaining[i] == 0 {
+			arr.complete(i)
+		}
+		admit()
+		if remaining[i] > 0 {
+			ready = append(ready, i)
+			continue
+		}
+		done++
+		timings[i].Completion = now
+		timings[i].Turnaround = now - processes[i].ArrivalTime
+		timings[i].Waiting = timings[i].Turnaround - processes[i].BurstDuration
+	}
+
+	return newScheduleResult(processes, gantt, timings).withQueueArea(queueArea)
+}
+
+// Priorities returns the effective priority each of r's processes had when it completed, in
+// the order the processes were given, replaying the runs and waits in r's Gantt under s.
+func (s Decay) Priorities(r ScheduleResult) []float64 {
+	penalty := make(map[string]float64, len(r.Rows))
+	since := make(map[string]int64, len(r.Rows))
+	for _, row := range r.Rows {
+		since[row.Process.ProcessID] = row.Process.ArrivalTime
+	}
+	for _, slice := range r.Gantt {
+		pid := slice.PID
+		penalty[pid] = s.decayed(penalty[pid], slice.Start-since[pid], slice.Stop-slice.Start)
+		since[pid] = slice.Stop
+	}
+	priorities := make([]float64, len(r.Rows))
+	for i, row := range r.Rows {
+		priorities[i] = float64(row.Process.Priority) + penalty[row.Process.ProcessID]
+	}
+	return priorities
+}
+
+// DecaySchedule outputs a Decay schedule of processes in a GANTT chart and a table of timing,
+// followed by each process's effective priority when it completed.
+func DecaySchedule(w io.Writer, title string, processes []Process, s Decay) {
+	r := s.Schedule(processes)
+	outputResult(w, title, r, RenderOptions{})
+	outputAlgorithmSummary(w, s, r)
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDecay_Schedule(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: "P0", ArrivalTime: 0, BurstDuration: 3, Priority: 0},
+		{ProcessID: "P1", ArrivalTime: 0, BurstDuration: 2, Priority: 1},
+	}
+	tests := []struct {
+		name           string
+		decay          Decay
+		wantGantt      []TimeSlice
+		wantPriorities []float64
+	}{
+		{
+			name:  "no decay",
+			decay: Decay{Quantum: 1},
+			// P0 always has the better priority, so it keeps the CPU.
+			wantGantt: []TimeSlice{
+				{PID: "P0", Start: 0, Stop: 3},
+				{PID: "P1", Start: 3, Stop: 5},
+			},
+			wantPriorities: []float64{0, 1},
+		},
+		{
+			name:  "decay and recovery",
+			decay: Decay{Quantum: 1, Usage: 1, Recovery: 0.5},
+			// At 1 both are at 1 and P1 has waited longer; at 2 P0 has recovered to 0.5; at 3
+			// both are at 1.5 again.
+			wantGantt: []TimeSlice{
+				{PID: "P0", Start: 0, Stop: 1},
+				{PID: "P1", Start: 1, Stop: 2},
+				{PID: "P0", Start: 2, Stop: 3},
+				{PID: "P1", Start: 3, Stop: 4},
+				{PID: "P0", Start: 4, Stop: 5},
+			},
+			wantPriorities: []float64{2, 2.5},
+		},
+		{
+			name:  "run to completion",
+			decay: Decay{Usage: 1, Recovery: 0.5},
+			wantGantt: []TimeSlice{
+				{PID: "P0", Start: 0, Stop: 3},
+				{PID: "P1", Start: 3, Stop: 5},
+			},
+			wantPriorities: []float64{3, 3},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := tt.decay.Schedule(processes)
+			if diff := cmp.Diff(got.Gantt, tt.wantGantt); diff != "" {
+				t.Errorf(diff)
+			}
+			if diff := cmp.Diff(tt.decay.Priorities(got), tt.wantPriorities); diff != "" {
+				t.Errorf("Priorities: %s", diff)
+			}
+		})
+	}
+
+	var w bytes.Buffer
+	DecaySchedule(&w, "Decay", processes, tests[1].decay)
+	if !strings.HasSuffix(w.String(), "Final effective priority: P0 2.00 P1 2.50\n") {
+		t.Errorf("unexpected summary:\n%s", w.String())
+	}
+}
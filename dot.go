@@ -0,0 +1,109 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// outputDOT writes r as a Graphviz DOT digraph: one node per process, labeled with its ID and
+// the times it started and finished, and an edge from each prerequisite to the process that
+// depends on it. The processes and edges on the critical path are drawn bold. Nodes are listed
+// in the order the processes started, so rendering left to right follows the timeline.
+func outputDOT(w io.Writer, r ScheduleResult) error {
+	start := make(map[string]int64, len(r.Rows))
+	for _, slice := range r.Gantt {
+		pid := strings.TrimPrefix(slice.PID, warmupPrefix)
+		if t, ok := start[pid]; !ok || slice.Start < t {
+			start[pid] = slice.Start
+		}
+	}
+	processes := make([]Process, len(r.Rows))
+	for i, row := range r.Rows {
+		processes[i] = row.Process
+		if _, ok := start[row.Process.ProcessID]; !ok {
+			start[row.Process.ProcessID] = row.Completion // never ran, so it had no work.
+		}
+	}
+	_, path := CriticalPath(processes)
+	critical := make(map[string]bool, len(path))
+	for _, pid := range path {
+		critical[pid] = true
+	}
+	known := make(map[string]bool, len(r.Rows))
+	for _, p := range processes {
+		known[p.ProcessID] = true
+	}
+
+	b := bufio.NewWriter(w)
+	_, _ = fmt.Fprintln(b, "digraph schedule {")
+	_, _ = fmt.Fprintln(b, "\trankdir=LR;")
+	_, _ = fmt.Fprintln(b, "\tnode [shape=box];")
+	for _, n := range startOrder(r, start) {
+		row := r.Rows[n]
+		pid := row.Process.ProcessID
+		label := fmt.Sprintf("%s\nstart %s, finish %s", pid, formatTime(start[pid], r.Resolution), formatTime(row.Completion, r.Resolution))
+		style := ""
+		if critical[pid] {
+			style = ", style=bold"
+		}
+		_, _ = fmt.Fprintf(b, "\t%s [label=%s%s];\n", dotQuote(pid), dotQuote(label), style)
+	}
+	for _, p := range processes {
+		for _, dep := range p.DependsOn {
+			if !known[dep] {
+				continue
+			}
+			style := ""
+			if onPath(path, dep, p.ProcessID) {
+				style = " [style=bold]"
+			}
+			_, _ = fmt.Fprintf(b, "\t%s -> %s%s;\n", dotQuote(dep), dotQuote(p.ProcessID), style)
+		}
+	}
+	_, _ = fmt.Fprintln(b, "}")
+	if err := b.Flush(); err != nil {
+		return fmt.Errorf("%w: writing DOT", err)
+	}
+	return nil
+}
+
+// startOrder returns the indices of r's rows stably sorted by start time.
+func startOrder(r ScheduleResult, start map[string]int64) []int {
+	processes := make([]Process, len(r.Rows))
+	for i, row := range r.Rows {
+		processes[i] = row.Process
+	}
+	return sortedIndices(processes, func(a, b Process) bool {
+		return start[a.ProcessID] < start[b.ProcessID]
+	})
+}
+
+// onPath reports whether to immediately follows from in path.
+func onPath(path []string, from, to string) bool {
+	for i := 1; i < len(path); i++ {
+		if path[i-1] == from && path[i] == to {
+			return true
+		}
+	}
+	return false
+}
+
+// dotQuote quotes s as a DOT string, in which a newline is a line break in a label.
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
+
+func writeDOT(name string, r ScheduleResult) error {
+	f, err := os.Create(name)
+	if err != nil {
+		return fmt.Errorf("%w: error creating DOT file", err)
+	}
+	if err := outputDOT(f, r); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
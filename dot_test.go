@@ -0,0 +1,38 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_outputDOT(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: "A", ArrivalTime: 0, BurstDuration: 3},
+		{ProcessID: "B", ArrivalTime: 0, BurstDuration: 2, DependsOn: []string{"A"}},
+		{ProcessID: "C", ArrivalTime: 0, BurstDuration: 4},
+		{ProcessID: `D "final"`, ArrivalTime: 0, BurstDuration: 1, DependsOn: []string{"B", "C"}},
+	}
+	// B waits for A, so C runs first; the critical path is A, B, D at 3 + 2 + 1.
+	var w bytes.Buffer
+	if err := outputDOT(&w, FCFS{}.Schedule(processes)); err != nil {
+		t.Fatal(err)
+	}
+	want := `digraph schedule {
+	rankdir=LR;
+	node [shape=box];
+	"A" [label="A\nstart 0, finish 3", style=bold];
+	"C" [label="C\nstart 3, finish 7"];
+	"B" [label="B\nstart 7, finish 9", style=bold];
+	"D \"final\"" [label="D \"final\"\nstart 9, finish 10", style=bold];
+	"A" -> "B" [style=bold];
+	"B" -> "D \"final\"" [style=bold];
+	"C" -> "D \"final\"";
+}
+`
+	if diff := cmp.Diff(w.String(), want); diff != "" {
+		t.Errorf(diff)
+	}
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"strings"
+)
+
+// Dropout is a Scheduler for crash-prone jobs: it picks Fraction of the processes with work,
+// rounded to the nearest process, to fail at a random point in their execution, and Scheduler
+// runs each of those only until it fails. The failures are drawn from Seed, so a seed fails the
+// same processes at the same points under every Scheduler. A failed process's row holds the CPU
+// time it consumed as its burst and the time it failed as its completion; schedulers that know
+// bursts in advance, such as SJF, see that shorter burst. A Fraction outside [0, 1] is clamped.
+type Dropout struct {
+	Scheduler Scheduler
+	Fraction  float64
+	Seed      int64
+}
+
+// Failure is a process that failed after consuming Consumed of its Burst, in time units.
+type Failure struct {
+	PID      string
+	Consumed int64
+	Burst    int64
+}
+
+func (d Dropout) Name() string { return d.Scheduler.Name() }
+
+func (d Dropout) Schedule(processes []Process) ScheduleResult {
+	return d.scheduleClocked(processes, nil)
+}
+
+func (d Dropout) scheduleClocked(processes []Process, clock *decisionClock) ScheduleResult {
+	failing := append([]Process(nil), processes...)
+	for i, f := range d.failures(processes) {
+		failing[i].BurstDuration = f.Consumed
+	}
+	return scheduleWith(d.Scheduler, failing, clock)
+}
+
+// failures returns the processes that fail, by index, each consuming a whole number of time
+// units in [0, its burst).
+func (d Dropout) failures(processes []Process) map[int]Failure {
+	var candidates []int
+	for i, p := range processes {
+		if p.BurstDuration > 0 {
+			candidates = append(candidates, i)
+		}
+	}
+	fraction := math.Max(0, math.Min(1, d.Fraction))
+	n := int(math.Round(fraction * float64(len(candidates))))
+	rng := rand.New(rand.NewSource(d.Seed))
+	failures := make(map[int]Failure, n)
+	for _, k := range rng.Perm(len(candidates))[:n] {
+		p := processes[candidates[k]]
+		failures[candidates[k]] = Failure{PID: p.ProcessID, Consumed: rng.Int63n(p.BurstDuration), Burst: p.BurstDuration}
+	}
+	return failures
+}
+
+// DropoutReport is the outcome of a Dropout schedule: the Failed processes in input order, the
+// Useful CPU time spent on processes that completed and the Wasted time on those that failed,
+// in time units, and the Goodput, useful work completed per time unit of the makespan.
+type DropoutReport struct {
+	Failed  []Failure
+	Useful  float64
+	Wasted  float64
+	Goodput float64
+}
+
+// MeasureDropout schedules processes with d and reports how much of the CPU time did useful
+// work.
+func MeasureDropout(d Dropout, processes []Process) DropoutReport {
+	r := d.Schedule(processes)
+	failures := d.failures(processes)
+	var report DropoutReport
+	for i, p := range processes {
+		if f, ok := failures[i]; ok {
+			report.Failed = append(report.Failed, f)
+			report.Wasted += float64(f.Consumed)
+			continue
+		}
+		report.Useful += float64(p.BurstDuration)
+	}
+	if r.Makespan > 0 {
+		report.Goodput = report.Useful / (float64(r.Makespan) / float64(max(1, r.Resolution)))
+	}
+	return report
+}
+
+// outputDropout writes which processes failed and how much work the rest got done.
+func outputDropout(w io.Writer, d DropoutReport) {
+	parts := make([]string, len(d.Failed))
+	for i, f := range d.Failed {
+		parts[i] = fmt.Sprintf("%s after %d of %d", f.PID, f.Consumed, f.Burst)
+	}
+	failed := "none"
+	if len(parts) > 0 {
+		failed = strings.Join(parts, ", ")
+	}
+	_, _ = fmt.Fprintf(w, "Failures: %s; goodput %.2f useful work per time unit, %.2f useful and %.2f wasted\n",
+		failed, d.Goodput, d.Useful, d.Wasted)
+}
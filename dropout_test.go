@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDropout(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: "P0", ArrivalTime: 0, BurstDuration: 4},
+		{ProcessID: "P1", ArrivalTime: 1, BurstDuration: 3},
+		{ProcessID: "P2", ArrivalTime: 2, BurstDuration: 0},
+		{ProcessID: "P3", ArrivalTime: 3, BurstDuration: 2},
+		{ProcessID: "P4", ArrivalTime: 4, BurstDuration: 5},
+	}
+	input := append([]Process(nil), processes...)
+
+	t.Run("no failures", func(t *testing.T) {
+		t.Parallel()
+		d := Dropout{Scheduler: RR{Quantum: 2}, Fraction: 0, Seed: 1}
+		if diff := cmp.Diff(RR{Quantum: 2}.Schedule(processes), d.Schedule(processes)); diff != "" {
+			t.Errorf(diff)
+		}
+		want := DropoutReport{Useful: 14, Goodput: 1}
+		if diff := cmp.Diff(want, MeasureDropout(d, processes)); diff != "" {
+			t.Errorf(diff)
+		}
+	})
+
+	t.Run("same failures under every scheduler", func(t *testing.T) {
+		t.Parallel()
+		fcfs := MeasureDropout(Dropout{Scheduler: FCFS{}, Fraction: 0.5, Seed: 7}, processes)
+		rr := MeasureDropout(Dropout{Scheduler: RR{Quantum: 1}, Fraction: 0.5, Seed: 7}, processes)
+		if len(fcfs.Failed) != 2 {
+			t.Errorf("%d failures, want 2 of the 4 processes with work", len(fcfs.Failed))
+		}
+		if diff := cmp.Diff(fcfs.Failed, rr.Failed); diff != "" {
+			t.Errorf(diff)
+		}
+		for _, f := range fcfs.Failed {
+			if f.Consumed < 0 || f.Consumed >= f.Burst {
+				t.Errorf("%s consumed %d of %d", f.PID, f.Consumed, f.Burst)
+			}
+		}
+	})
+
+	t.Run("every process fails", func(t *testing.T) {
+		t.Parallel()
+		d := Dropout{Scheduler: FCFS{}, Fraction: 2, Seed: 3}
+		report := MeasureDropout(d, processes)
+		if len(report.Failed) != 4 || report.Useful != 0 || report.Goodput != 0 {
+			t.Errorf("report = %+v, want the 4 processes with work failed and no useful work", report)
+		}
+		bursts := make(map[string]int64)
+		for _, row := range d.Schedule(processes).Rows {
+			bursts[row.Process.ProcessID] = row.Process.BurstDuration
+		}
+		for _, f := range report.Failed {
+			if bursts[f.PID] != f.Consumed {
+				t.Errorf("%s: burst %d in its row, want the %d it consumed", f.PID, bursts[f.PID], f.Consumed)
+			}
+		}
+	})
+
+	if diff := cmp.Diff(input, processes); diff != "" {
+		t.Errorf("input modified: %s", diff)
+	}
+
+	var w bytes.Buffer
+	outputDropout(&w, DropoutReport{Failed: []Failure{{PID: "P1", Consumed: 2, Burst: 3}}, Useful: 11, Wasted: 2, Goodput: 0.85})
+	want := "Failures: P1 after 2 of 3; goodput 0.85 useful work per time unit, 11.00 useful and 2.00 wasted\n"
+	if diff := cmp.Diff(want, w.String()); diff != "" {
+		t.Errorf(diff)
+	}
+}
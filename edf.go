@@ -0,0 +1,169 @@
+package main
+
+import (
+	"math"
+	"slices"
+	"sort"
+)
+
+// EDF schedules processes preemptively by earliest deadline first on Cores identical CPUs, or
+// one if Cores < 1. Whenever a process arrives or completes, the Cores ready processes with the
+// earliest Deadlines run, ties going to the earlier arrival and then to the process given
+// first, and processes without a deadline run only on cores no deadline needs, in arrival
+// order. A process that stays among those running keeps its core; one that resumes after
+// being preempted takes any free core.
+type EDF struct {
+	Cores int
+}
+
+func (EDF) Name() string { return "edf" }
+
+func (s EDF) Schedule(processes []Process) ScheduleResult {
+	return s.scheduleClocked(processes, nil)
+}
+
+func (s EDF) scheduleClocked(processes []Process, clock *decisionClock) ScheduleResult {
+	cores := s.Cores
+	if cores < 1 {
+		cores = 1
+	}
+	arr := newArrivals(processes)
+	remaining := make([]int64, len(processes))
+	for i := range processes {
+		remaining[i] = processes[i].BurstDuration
+	}
+	earlier := func(a, b int) bool {
+		da, db := processes[a].Deadline, processes[b].Deadline
+		switch {
+		case (da == 0) != (db == 0):
+			return db == 0
+		case da != db:
+			return da < db
+		case processes[a].ArrivalTime != processes[b].ArrivalTime:
+			return processes[a].ArrivalTime < processes[b].ArrivalTime
+		}
+		return a < b
+	}
+
+	// Waiting processes are kept on a heap, pinned ones first, so a decision only pops the few
+	// it dispatches rather than sorting every ready process.
+	waiting := newReadyQueue(func(a, b int) bool {
+		if processes[a].Pinned != processes[b].Pinned {
+			return processes[a].Pinned
+		}
+		return earlier(a, b)
+	})
+
+	var (
+		now       int64
+		done      int
+		onCore    = make([]int, cores) // the process on each core, or -1.
+		last      = make([]int, cores) // the index in gantt of each core's last slice, or -1.
+		queueArea int64                // integral of the ready queue length over time.
+		timings   = make([]processTiming, len(processes))
+		gantt     = make([]TimeSlice, 0, len(processes))
+		dog       watchdog
+		stuck     = unfinished(processes, remaining)
+	)
+	for c := range onCore {
+		onCore[c], last[c] = -1, -1
+	}
+	for done < len(processes) {
+		arr.release(now, func(i int) {
+			queueArea += now - processes[i].ArrivalTime
+			waiting.enqueue(i)
+		})
+		if waiting.Len() == 0 && !slices.ContainsFunc(onCore, func(i int) bool { return i >= 0 }) {
+			// CPU is idle until the next arrival.
+			now = arr.idleUntil(now)
+			continue
+		}
+
+		dog.observe(now, done, stuck)
+		t0 := clock.start()
+		// The running processes compete with the waiting ones by deadline, except that if a
+		// waiting process is pinned, the pinned and running processes take the cores first.
+		var first []int
+		for _, i := range onCore {
+			if i >= 0 {
+				first = append(first, i)
+			}
+		}
+		pinning := waiting.Len() > 0 && processes[waiting.peek()].Pinned
+		for waiting.Len() > 0 && processes[waiting.peek()].Pinned {
+			first = append(first, waiting.dequeue())
+		}
+		sort.Slice(first, func(a, b int) bool { return earlier(first[a], first[b]) })
+		chosen := make([]int, 0, cores)
+		k := 0
+		for len(chosen) < cores && (k < len(first) || waiting.Len() > 0) {
+			if k < len(first) && (waiting.Len() == 0 || pinning || earlier(first[k], waiting.peek())) {
+				chosen = append(chosen, first[k])
+				k++
+			} else {
+				chosen = append(chosen, waiting.dequeue())
+			}
+		}
+		for _, i := range first[k:] {
+			waiting.enqueue(i)
+		}
+		for c, i := range onCore {
+			if i >= 0 && !slices.Contains(chosen, i) {
+				onCore[c] = -1
+			}
+		}
+		for _, i := range chosen {
+			if slices.Contains(onCore, i) {
+				continue
+			}
+			for c := range onCore {
+				if onCore[c] < 0 {
+					onCore[c] = i
+					break
+				}
+			}
+		}
+		clock.stop(t0)
+
+		// Run until the next arrival or completion.
+		end := int64(math.MaxInt64)
+		if t, ok := arr.nextArrival(); ok {
+			end = t
+		}
+		for _, i := range onCore {
+			if i >= 0 {
+				end = min(end, now+remaining[i])
+			}
+		}
+		for c, i := range onCore {
+			if i < 0 || end == now && remaining[i] > 0 {
+				continue
+			}
+			if n := last[c]; n >= 0 && gantt[n].PID == processes[i].ProcessID && gantt[n].Stop == now && end > now {
+				gantt[n].Stop = end
+			} else {
+				last[c] = len(gantt)
+				gantt = append(gantt, TimeSlice{PID: processes[i].ProcessID, Start: now, Stop: end, Core: c})
+			}
+			remaining[i] -= end - now
+		}
+		queueArea += int64(waiting.Len()) * (end - now)
+		now = end
+
+		for c, i := range onCore {
+			if i < 0 || remaining[i] > 0 {
+				continue
+			}
+			onCore[c] = -1
+			done++
+			arr.complete(i)
+			timings[i].Completion = now
+			timings[i].Turnaround = now - processes[i].ArrivalTime
+			timings[i].Waiting = timings[i].Turnaround - processes[i].BurstDuration
+		}
+	}
+
+	r := newScheduleResult(processes, gantt, timings).withQueueArea(queueArea)
+	r.Cores = cores
+	return r
+}
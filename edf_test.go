@@ -0,0 +1,88 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// deadlines is a workload one CPU cannot finish in time under EDF but two can.
+var deadlines = []Process{
+	{ProcessID: "A", ArrivalTime: 0, BurstDuration: 4, Deadline: 10},
+	{ProcessID: "B", ArrivalTime: 0, BurstDuration: 3, Deadline: 4},
+	{ProcessID: "C", ArrivalTime: 1, BurstDuration: 2, Deadline: 3},
+	{ProcessID: "D", ArrivalTime: 0, BurstDuration: 2},
+}
+
+func TestEDF_Schedule(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		cores     int
+		wantGantt []TimeSlice
+		wantLate  []string
+	}{
+		{
+			name:  "one core",
+			cores: 1,
+			// C's deadline preempts B, which then finishes at 5, after its deadline.
+			wantGantt: []TimeSlice{
+				{PID: "B", Start: 0, Stop: 1},
+				{PID: "C", Start: 1, Stop: 3},
+				{PID: "B", Start: 3, Stop: 5},
+				{PID: "A", Start: 5, Stop: 9},
+				{PID: "D", Start: 9, Stop: 11},
+			},
+			wantLate: []string{"B"},
+		},
+		{
+			name:  "two cores",
+			cores: 2,
+			// C preempts A, the latest deadline running, and B keeps its core.
+			wantGantt: []TimeSlice{
+				{PID: "B", Start: 0, Stop: 3, Core: 0},
+				{PID: "A", Start: 0, Stop: 1, Core: 1},
+				{PID: "C", Start: 1, Stop: 3, Core: 1},
+				{PID: "A", Start: 3, Stop: 6, Core: 0},
+				{PID: "D", Start: 3, Stop: 5, Core: 1},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			r := EDF{Cores: tt.cores}.Schedule(deadlines)
+			if diff := cmp.Diff(tt.wantGantt, r.Gantt); diff != "" {
+				t.Errorf(diff)
+			}
+			if diff := cmp.Diff(tt.wantLate, MissedDeadlines(r)); diff != "" {
+				t.Errorf("missed deadlines: %s", diff)
+			}
+		})
+	}
+
+	var w bytes.Buffer
+	outputSchedule(&w, nil, EDF{}.Schedule(deadlines), RenderOptions{})
+	if line := "Deadline misses: 1 (B)\n"; !strings.Contains(w.String(), line) {
+		t.Errorf("summary is missing %q:\n%s", line, w.String())
+	}
+}
+
+func TestMinCores(t *testing.T) {
+	t.Parallel()
+	edf := func(cores int) Scheduler { return EDF{Cores: cores} }
+	if cores, err := MinCores(deadlines, edf, 8); err != nil || cores != 2 {
+		t.Errorf("MinCores() = %d, %v, want 2", cores, err)
+	}
+	_, err := MinCores(deadlines, edf, 1)
+	if !errors.Is(err, ErrInfeasible) || err.Error() != "deadlines cannot be met: on 1 cores B finish late" {
+		t.Errorf("capped at 1: got %v, want ErrInfeasible", err)
+	}
+	if _, err := MinCores(deadlines, edf, 0); !errors.Is(err, ErrInvalidArgs) {
+		t.Errorf("capped at 0: got %v, want ErrInvalidArgs", err)
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+// EnergyReport is the energy a schedule used: Active while its processes ran, at each
+// process's Power, and Idle while cores sat idle at a lower static power, both in power times
+// time units.
+type EnergyReport struct {
+	Active float64
+	Idle   float64
+}
+
+// Total is the energy used running and idling.
+func (e EnergyReport) Total() float64 { return e.Active + e.Idle }
+
+// Energy returns the energy r used with cores drawing idlePower whenever they were idle over
+// [0, Makespan]. Warmup slices count as running the process they warm up.
+func Energy(r ScheduleResult, idlePower float64) EnergyReport {
+	power := make(map[string]float64, len(r.Rows))
+	for _, row := range r.Rows {
+		power[row.Process.ProcessID] = row.Process.Power
+		if row.Process.Power == 0 {
+			power[row.Process.ProcessID] = 1
+		}
+	}
+	scale := float64(max(1, r.Resolution))
+	var e EnergyReport
+	for _, slice := range r.Gantt {
+		e.Active += power[strings.TrimPrefix(slice.PID, warmupPrefix)] * float64(slice.Stop-slice.Start) / scale
+	}
+	_, total := CoreUtilization(r)
+	e.Idle = idlePower * float64(total.Idle) / scale
+	return e
+}
+
+// ScaleFrequency returns processes run at frequency, a fraction of full speed in (0, 1]: each
+// burst stretches to BurstDuration/frequency, rounded up, while its Power falls with the cube
+// of the frequency, as it does under dynamic voltage and frequency scaling. A job's energy so
+// falls roughly with the square of the frequency, at the cost of running longer.
+func ScaleFrequency(processes []Process, frequency float64) ([]Process, error) {
+	if !(frequency > 0 && frequency <= 1) {
+		return nil, fmt.Errorf("%w: frequency %v is not in (0, 1]", ErrInvalidArgs, frequency)
+	}
+	scaled := append([]Process(nil), processes...)
+	for i := range scaled {
+		p := &scaled[i]
+		if p.Power == 0 {
+			p.Power = 1
+		}
+		p.Power *= frequency * frequency * frequency
+		burst := math.Ceil(float64(p.BurstDuration) / frequency)
+		if burst >= math.MaxInt64 {
+			return nil, fmt.Errorf("%w: %s: burst at frequency %v", ErrOverflow, p.ProcessID, frequency)
+		}
+		p.BurstDuration = int64(burst)
+	}
+	return scaled, ValidateProcesses(scaled)
+}
+
+// outputEnergy writes the energy e used, split into running and idle.
+func outputEnergy(w io.Writer, e EnergyReport) {
+	_, _ = fmt.Fprintf(w, "Energy: %.2f (active %.2f, idle %.2f)\n", e.Total(), e.Active, e.Idle)
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestEnergy(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: "P0", ArrivalTime: 0, BurstDuration: 3, Power: 2},
+		{ProcessID: "P1", ArrivalTime: 5, BurstDuration: 2},
+	}
+	tests := []struct {
+		name string
+		s    Scheduler
+		want EnergyReport
+	}{
+		// P0 runs 3 at power 2 and P1 2 at the default 1, with the CPU idle over [3, 5).
+		{name: "fcfs", s: FCFS{}, want: EnergyReport{Active: 8, Idle: 1}},
+		{name: "rr in ticks", s: RR{Quantum: 5, Resolution: 10}, want: EnergyReport{Active: 8, Idle: 1}},
+		// Each core idles once its process is done: core 0 over [3, 7), core 1 over [0, 5).
+		{name: "two cores", s: MultiCore{Cores: 2, Dispatch: RoundRobinDispatch}, want: EnergyReport{Active: 8, Idle: 4.5}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if diff := cmp.Diff(tt.want, Energy(tt.s.Schedule(processes), 0.5)); diff != "" {
+				t.Errorf(diff)
+			}
+		})
+	}
+}
+
+func TestScaleFrequency(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: "P0", BurstDuration: 3, Power: 8},
+		{ProcessID: "P1", BurstDuration: 4},
+	}
+	scaled, err := ScaleFrequency(processes, 0.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Process{
+		{ProcessID: "P0", BurstDuration: 6, Power: 1},
+		{ProcessID: "P1", BurstDuration: 8, Power: 0.125},
+	}
+	if diff := cmp.Diff(want, scaled); diff != "" {
+		t.Errorf(diff)
+	}
+	if processes[0].BurstDuration != 3 {
+		t.Errorf("ScaleFrequency modified its input")
+	}
+	// Half speed takes twice as long at an eighth of the power, a quarter of the energy.
+	full, half := Energy(FCFS{}.Schedule(processes), 0), Energy(FCFS{}.Schedule(scaled), 0)
+	if math.Abs(half.Total()-full.Total()/4) > 1e-9 {
+		t.Errorf("energy at half speed %v, want a quarter of %v", half.Total(), full.Total())
+	}
+
+	for _, frequency := range []float64{0, -1, 1.5, math.NaN()} {
+		if _, err := ScaleFrequency(processes, frequency); !errors.Is(err, ErrInvalidArgs) {
+			t.Errorf("frequency %v: got %v, want %v", frequency, err, ErrInvalidArgs)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// EventType is the kind of a simulation Event.
+type EventType string
+
+const (
+	EventArrive   EventType = "arrive"
+	EventDispatch EventType = "dispatch"
+	EventPreempt  EventType = "preempt"
+	EventIOStart  EventType = "io-start"
+	EventIOEnd    EventType = "io-end"
+	EventComplete EventType = "complete"
+)
+
+// eventRank orders events that happen at the same time: the running process stops before new
+// arrivals join the ready queue, and the next process is dispatched last.
+var eventRank = map[EventType]int{
+	EventComplete: 0,
+	EventPreempt:  1,
+	EventIOStart:  2,
+	EventIOEnd:    3,
+	EventArrive:   4,
+	EventDispatch: 5,
+}
+
+// Event is one step of a simulation trace. Time is in the result's ticks.
+type Event struct {
+	Time int64     `json:"time"`
+	Type EventType `json:"type"`
+	PID  string    `json:"pid"`
+}
+
+// Events returns the trace of r in time order. Consecutive slices of the same process are one
+// dispatch, and a process that leaves the CPU before completing is preempted, unless it has run
+// up to one of its IOBursts, when it starts I/O instead and ends it Duration later.
+func Events(r ScheduleResult) []Event {
+	scale := max(1, r.Resolution)
+	completion := make(map[string]int64, len(r.Rows))
+	arrival := make(map[string]int64, len(r.Rows))
+	bursts := make(map[string][]IOBurst, len(r.Rows))
+	ran := make(map[string]int64, len(r.Rows)) // the CPU time each process has had so far.
+	var events []Event
+	for _, row := range r.Rows {
+		completion[row.Process.ProcessID] = row.Completion
+		bursts[row.Process.ProcessID] = row.Process.IO
+		arrival[row.Process.ProcessID] = row.Process.ArrivalTime * scale
+		events = append(events,
+			Event{Time: arrival[row.Process.ProcessID], Type: EventArrive, PID: row.Process.ProcessID},
+			Event{Time: row.Completion, Type: EventComplete, PID: row.Process.ProcessID},
+		)
+	}
+
+	var running *TimeSlice
+	stop := func() {
+		if running == nil || running.Stop >= completion[running.PID] {
+			running = nil
+			return
+		}
+		for _, b := range bursts[running.PID] {
+			if b.At*scale == ran[running.PID] {
+				events = append(events,
+					Event{Time: running.Stop, Type: EventIOStart, PID: running.PID},
+					Event{Time: running.Stop + b.Duration*scale, Type: EventIOEnd, PID: running.PID},
+				)
+				running = nil
+				return
+			}
+		}
+		events = append(events, Event{Time: running.Stop, Type: EventPreempt, PID: running.PID})
+		running = nil
+	}
+	for i := range r.Gantt {
+		slice := r.Gantt[i]
+		if slice.Start == slice.Stop {
+			continue // checkpoint markers never hold the CPU.
+		}
+		// The process is dispatched when its warmup starts.
+		var cpu int64
+		if !strings.HasPrefix(slice.PID, warmupPrefix) {
+			cpu = slice.Stop - slice.Start
+		}
+		slice.PID = strings.TrimPrefix(slice.PID, warmupPrefix)
+		if running != nil && running.PID == slice.PID && running.Stop == slice.Start {
+			running.Stop = slice.Stop
+			ran[slice.PID] += cpu
+			continue
+		}
+		stop()
+		ran[slice.PID] += cpu
+		events = append(events, Event{Time: slice.Start, Type: EventDispatch, PID: slice.PID})
+		running = &slice
+	}
+	stop()
+
+	rank := func(e Event) int {
+		if e.Type == EventComplete && arrival[e.PID] == e.Time {
+			// A process that completes as it arrives, such as a checkpoint, arrives first.
+			return eventRank[EventArrive]
+		}
+		return eventRank[e.Type]
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		if events[i].Time != events[j].Time {
+			return events[i].Time < events[j].Time
+		}
+		return rank(events[i]) < rank(events[j])
+	})
+	return events
+}
+
+// CompletionQueue is the number of processes Waiting, arrived but neither running nor complete,
+// at the moment PID completed at Time.
+type CompletionQueue struct {
+	Time    int64
+	PID     string
+	Waiting int
+}
+
+// WaitingAtCompletions returns, for each completion in r in completion order, how many other
+// processes were waiting at that moment. A process arriving as another completes is not yet
+// waiting, while one dispatched then still is, and a process held back by a prerequisite
+// counts as waiting.
+func WaitingAtCompletions(r ScheduleResult) []CompletionQueue {
+	scale := max(1, r.Resolution)
+	var series []CompletionQueue
+	for _, n := range completionOrder(r) {
+		t := r.Rows[n].Completion
+		c := CompletionQueue{Time: t, PID: r.Rows[n].Process.ProcessID}
+		running := make(map[string]bool)
+		for _, slice := range r.Gantt {
+			if slice.Start < t && t < slice.Stop {
+				running[strings.TrimPrefix(slice.PID, warmupPrefix)] = true
+			}
+		}
+		for _, row := range r.Rows {
+			if row.Process.ArrivalTime*scale < t && row.Completion > t && !running[row.Process.ProcessID] {
+				c.Waiting++
+			}
+		}
+		series = append(series, c)
+	}
+	return series
+}
+
+// outputEvents writes the event trace of r one per line, with times in whole units. Each
+// completion also gives the number of processes left waiting.
+func outputEvents(w io.Writer, r ScheduleResult) {
+	waiting := make(map[string]int, len(r.Rows))
+	for _, c := range WaitingAtCompletions(r) {
+		waiting[c.PID] = c.Waiting
+	}
+	_, _ = fmt.Fprintln(w, "Event trace")
+	for _, e := range Events(r) {
+		_, _ = fmt.Fprintf(w, "%8s  %-8s  %s", formatTime(e.Time, r.Resolution), e.Type, e.PID)
+		if e.Type == EventComplete {
+			_, _ = fmt.Fprintf(w, "  (%d waiting)", waiting[e.PID])
+		}
+		_, _ = fmt.Fprintln(w)
+	}
+}
+
+// encodeEvents writes events as a JSON array.
+func encodeEvents(w io.Writer, events []Event) error {
+	if events == nil {
+		events = []Event{}
+	}
+	if err := json.NewEncoder(w).Encode(events); err != nil {
+		return fmt.Errorf("%w: encoding events", err)
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestEvents(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: "P0", ArrivalTime: 0, BurstDuration: 3},
+		{ProcessID: "P1", ArrivalTime: 2, BurstDuration: 1},
+		{ProcessID: "C2", ArrivalTime: 5, BurstDuration: 0},
+	}
+	tests := []struct {
+		name      string
+		s         Scheduler
+		processes []Process
+		want      []Event
+	}{
+		{
+			name:      "round-robin",
+			s:         RR{Quantum: 2},
+			processes: processes,
+			want: []Event{
+				{Time: 0, Type: EventArrive, PID: "P0"},
+				{Time: 0, Type: EventDispatch, PID: "P0"},
+				{Time: 2, Type: EventPreempt, PID: "P0"},
+				{Time: 2, Type: EventArrive, PID: "P1"},
+				{Time: 2, Type: EventDispatch, PID: "P1"},
+				{Time: 3, Type: EventComplete, PID: "P1"},
+				{Time: 3, Type: EventDispatch, PID: "P0"},
+				{Time: 4, Type: EventComplete, PID: "P0"},
+				{Time: 5, Type: EventArrive, PID: "C2"},
+				{Time: 5, Type: EventComplete, PID: "C2"},
+			},
+		},
+		{
+			name: "checkpoint splits a slice",
+			s:    checkpointing{FCFS{}},
+			processes: []Process{
+				{ProcessID: "P0", ArrivalTime: 0, BurstDuration: 3},
+				{ProcessID: "C1", ArrivalTime: 1, BurstDuration: 0},
+			},
+			want: []Event{
+				{Time: 0, Type: EventArrive, PID: "P0"},
+				{Time: 0, Type: EventDispatch, PID: "P0"},
+				{Time: 1, Type: EventArrive, PID: "C1"},
+				{Time: 1, Type: EventComplete, PID: "C1"},
+				{Time: 3, Type: EventComplete, PID: "P0"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if diff := cmp.Diff(Events(tt.s.Schedule(tt.processes)), tt.want); diff != "" {
+				t.Errorf(diff)
+			}
+		})
+	}
+}
+
+func Test_encodeEvents(t *testing.T) {
+	t.Parallel()
+	var w bytes.Buffer
+	if err := encodeEvents(&w, []Event{{Time: 2, Type: EventPreempt, PID: "P0"}}); err != nil {
+		t.Fatal(err)
+	}
+	want := `[{"time":2,"type":"preempt","pid":"P0"}]` + "\n"
+	if diff := cmp.Diff(w.String(), want); diff != "" {
+		t.Errorf(diff)
+	}
+
+	w.Reset()
+	if err := encodeEvents(&w, nil); err != nil {
+		t.Fatal(err)
+	}
+	if w.String() != "[]\n" {
+		t.Errorf("empty trace = %q", w.String())
+	}
+}
+
+func TestWaitingAtCompletions(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: "P0", ArrivalTime: 0, BurstDuration: 3},
+		{ProcessID: "P1", ArrivalTime: 1, BurstDuration: 2},
+		{ProcessID: "P2", ArrivalTime: 1, BurstDuration: 1},
+		{ProcessID: "P3", ArrivalTime: 3, BurstDuration: 1},
+	}
+	tests := []struct {
+		name string
+		s    Scheduler
+		want []CompletionQueue
+	}{
+		{
+			// P3 arrives as P0 completes, so it is not yet waiting.
+			name: "fcfs",
+			s:    FCFS{},
+			want: []CompletionQueue{
+				{Time: 3, PID: "P0", Waiting: 2},
+				{Time: 5, PID: "P1", Waiting: 2},
+				{Time: 6, PID: "P2", Waiting: 1},
+				{Time: 7, PID: "P3", Waiting: 0},
+			},
+		},
+		{
+			// P2 waits behind P0 on core 0, and P3 runs on core 1 as P2 completes.
+			name: "two cores",
+			s:    MultiCore{Cores: 2},
+			want: []CompletionQueue{
+				{Time: 3, PID: "P0", Waiting: 1},
+				{Time: 3, PID: "P1", Waiting: 1},
+				{Time: 4, PID: "P2", Waiting: 0},
+				{Time: 4, PID: "P3", Waiting: 0},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if diff := cmp.Diff(WaitingAtCompletions(tt.s.Schedule(processes)), tt.want); diff != "" {
+				t.Errorf(diff)
+			}
+		})
+	}
+
+	var w bytes.Buffer
+	outputEvents(&w, FCFS{}.Schedule(processes))
+	if !bytes.Contains(w.Bytes(), []byte("       5  complete  P1  (2 waiting)\n")) {
+		t.Errorf("unexpected trace:\n%s", w.String())
+	}
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"io"
+)
+
+// outputMetricNotes writes a footnote defining each metric in the summary of r, a schedule by
+// s, and how it was computed for this run: over which processes the averages were taken, what
+// the waits include given the quantum and any preemption, and that throughput counts the idle
+// time up to the makespan.
+func outputMetricNotes(w io.Writer, s Scheduler, r ScheduleResult) {
+	var checkpoints, powerDown bool
+	base := s
+	for unwrapped := false; !unwrapped; {
+		switch v := base.(type) {
+		case checkpointing:
+			checkpoints, base = true, v.Scheduler
+		case notifying:
+			base = v.Scheduler
+		case Sampled:
+			base = v.Scheduler
+		case PowerDown:
+			powerDown, base = true, v.Scheduler
+		default:
+			unwrapped = true
+		}
+	}
+
+	processes := len(r.Rows)
+	counted := "processes"
+	if checkpoints {
+		counted = "processes with work; checkpoints are left out"
+		for _, row := range r.Rows {
+			if row.Process.BurstDuration == 0 {
+				processes--
+			}
+		}
+	}
+	var preemptions int
+	for _, e := range Events(r) {
+		if e.Type == EventPreempt {
+			preemptions++
+		}
+	}
+	cpu := "1 CPU"
+	if r.Cores > 1 {
+		cpu = fmt.Sprintf("%d cores", r.Cores)
+	}
+
+	_, _ = fmt.Fprintln(w, "Notes on the metrics")
+	schedule := fmt.Sprintf("%s on %s", s.Name(), cpu)
+	if q, ok := quantumOf(base); ok && q > 0 {
+		schedule += fmt.Sprintf(" with a quantum of %s", formatTime(q, r.Resolution))
+	}
+	if preemptions == 0 {
+		_, _ = fmt.Fprintf(w, "  Schedule: %s; no process was preempted, so each waited only before it first ran.\n", schedule)
+	} else {
+		_, _ = fmt.Fprintf(w, "  Schedule: %s; processes were preempted %d time(s), and a wait adds up every spell in the ready queue.\n", schedule, preemptions)
+	}
+	offCPU := "not spent running"
+	turnaround := "the wait plus the burst"
+	if blocksOnIO(r) {
+		offCPU = "not spent running or blocked for I/O"
+		turnaround = "the wait plus the burst plus the time blocked"
+	}
+	_, _ = fmt.Fprintf(w, "  Wait: the time from arrival to completion %s, averaged over the %d %s: %.2f.\n", offCPU, processes, counted, r.AvgWait)
+	if rr, ok := base.(RR); ok && rr.SwitchPenalty != nil {
+		_, _ = fmt.Fprintln(w, "    It includes each process's cache warmups after a context switch.")
+	}
+	if powerDown {
+		_, _ = fmt.Fprintln(w, "    It includes the delay while the CPU woke from powering down.")
+	}
+	_, _ = fmt.Fprintf(w, "  Turnaround: completion minus arrival, %s: %.2f.\n", turnaround, r.AvgTurnaround)
+	if preemptions == 0 && !blocksOnIO(r) {
+		_, _ = fmt.Fprintln(w, "  Response: the time from arrival until first reaching the CPU, the same as the wait here.")
+	} else {
+		_, _ = fmt.Fprintln(w, "  Response: the time from arrival until first reaching the CPU, less than the wait for a process that waited again later.")
+	}
+	_, _ = fmt.Fprintf(w, "  Throughput: %d processes over the makespan of %s from time 0, including %s idle: %.2f per time unit.\n",
+		processes, formatTime(r.Makespan, r.Resolution), formatTime(r.IdleTime, r.Resolution), r.Throughput)
+}
+
+// quantumOf returns the time slice s preempts after, in ticks, if it has one.
+func quantumOf(s Scheduler) (int64, bool) {
+	switch v := s.(type) {
+	case RR:
+		return v.Quantum, true
+	case RRIO:
+		return v.Quantum, true
+	case Decay:
+		return v.Quantum, true
+	case FairShare:
+		return v.Quantum, true
+	case GangRR:
+		return v.Quantum, true
+	}
+	return 0, false
+}
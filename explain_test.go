@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_outputMetricNotes(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: "P0", ArrivalTime: 0, BurstDuration: 3},
+		{ProcessID: "P1", ArrivalTime: 1, BurstDuration: 1},
+		{ProcessID: "P2", ArrivalTime: 6, BurstDuration: 1},
+	}
+	withCheckpoint := append(append([]Process(nil), processes[:2]...), Process{ProcessID: "C", ArrivalTime: 2})
+	tests := []struct {
+		name      string
+		s         Scheduler
+		processes []Process
+		want      []string
+	}{
+		{
+			name:      "fcfs",
+			s:         FCFS{},
+			processes: processes,
+			want: []string{
+				"Notes on the metrics",
+				"  Schedule: fcfs on 1 CPU; no process was preempted, so each waited only before it first ran.",
+				"  Wait: the time from arrival to completion not spent running, averaged over the 3 processes: 0.67.",
+				"  Turnaround: completion minus arrival, the wait plus the burst: 2.33.",
+				"  Response: the time from arrival until first reaching the CPU, the same as the wait here.",
+				"  Throughput: 3 processes over the makespan of 7 from time 0, including 2 idle: 0.43 per time unit.",
+			},
+		},
+		{
+			name:      "rr in ticks",
+			s:         RR{Quantum: 10, Resolution: 10, SwitchPenalty: func(Process, int64) int64 { return 0 }},
+			processes: processes,
+			want: []string{
+				"Notes on the metrics",
+				"  Schedule: rr on 1 CPU with a quantum of 1; processes were preempted 1 time(s), and a wait adds up every spell in the ready queue.",
+				"  Wait: the time from arrival to completion not spent running, averaged over the 3 processes: 0.33.",
+				"    It includes each process's cache warmups after a context switch.",
+				"  Turnaround: completion minus arrival, the wait plus the burst: 2.00.",
+				"  Response: the time from arrival until first reaching the CPU, less than the wait for a process that waited again later.",
+				"  Throughput: 3 processes over the makespan of 7 from time 0, including 2 idle: 0.43 per time unit.",
+			},
+		},
+		{
+			name:      "checkpoints",
+			s:         schedulerFor(fcfs, SchedulerConfig{Checkpoints: true}),
+			processes: withCheckpoint,
+			want: []string{
+				"Notes on the metrics",
+				"  Schedule: fcfs on 1 CPU; no process was preempted, so each waited only before it first ran.",
+				"  Wait: the time from arrival to completion not spent running, averaged over the 2 processes with work; checkpoints are left out: 1.00.",
+				"  Turnaround: completion minus arrival, the wait plus the burst: 3.00.",
+				"  Response: the time from arrival until first reaching the CPU, the same as the wait here.",
+				"  Throughput: 2 processes over the makespan of 4 from time 0, including 0 idle: 0.50 per time unit.",
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var w bytes.Buffer
+			outputMetricNotes(&w, tt.s, tt.s.Schedule(tt.processes))
+			if diff := cmp.Diff(strings.Join(tt.want, "\n")+"\n", w.String()); diff != "" {
+				t.Errorf(diff)
+			}
+		})
+	}
+}
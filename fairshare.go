@@ -0,0 +1,175 @@
+package main
+
+import (
+	"sort"
+	"strings"
+)
+
+// FairShare schedules processes in two levels: the CPU is divided among the groups with ready
+// processes in proportion to their Weights, keyed by GroupID, and each group's share is divided
+// round-robin among its own processes. Groups missing from Weights, or with a weight <= 0, have
+// weight 1.
+//
+// Every Quantum ticks, or when the running process completes, the CPU goes to the group that
+// has used the least CPU time per unit of weight, ties going to the group named first. A group
+// that becomes ready again has its usage raised to that of the least-served ready group, so it
+// cannot claim the CPU time it was not asking for. A Quantum <= 0 runs each process to
+// completion.
+type FairShare struct {
+	Quantum int64
+	Weights map[string]int64
+}
+
+func (FairShare) Name() string { return "fair" }
+
+func (s FairShare) weight(group string) float64 {
+	if w := s.Weights[group]; w > 0 {
+		return float64(w)
+	}
+	return 1
+}
+
+func (s FairShare) Schedule(processes []Process) ScheduleResult {
+	return s.scheduleClocked(processes, nil)
+}
+
+func (s FairShare) scheduleClocked(processes []Process, clock *decisionClock) ScheduleResult {
+	arr := newArrivals(processes)
+	remaining := make([]int64, len(processes))
+	for i := range processes {
+		remaining[i] = processes[i].BurstDuration
+	}
+
+	var (
+		now       int64
+		waiting   int
+		active    = make(map[string]int)     // the number of ready or running processes per group.
+		ready     = make(map[string][]int)   // each group's round-robin queue.
+		usage     = make(map[string]float64) // CPU time per unit of weight.
+		queueArea int64                      // integral of the ready queue length over time.
+		timings   = make([]processTiming, len(processes))
+		gantt     = make([]TimeSlice, 0, len(processes))
+		dog       watchdog
+		stuck     = unfinished(processes, remaining)
+	)
+	// least returns the group with the lowest usage among those for which include is true.
+	least := func(include func(g string) bool) (group string, ok bool) {
+		for g := range active {
+			if include(g) && (!ok || usage[g] < usage[group] || usage[g] == usage[group] && g < group) {
+				group, ok = g, true
+			}
+		}
+		return group, ok
+	}
+	admit := func() {
+		arr.release(now, func(i int) {
+			// Processes that arrived during the last run have been waiting since arrival.
+			queueArea += now - processes[i].ArrivalTime
+			g := processes[i].GroupID
+			if active[g] == 0 {
+				if floor, ok := least(func(g string) bool { return active[g] > 0 }); ok && usage[floor] > usage[g] {
+					usage[g] = usage[floor]
+				}
+			}
+			active[g]++
+			ready[g] = append(ready[g], i)
+			waiting++
+		})
+	}
+	for done := 0; done < len(processes); {
+		admit()
+		if waiting == 0 {
+			// CPU is idle until the next arrival.
+			now = arr.idleUntil(now)
+			continue
+		}
+		dog.observe(now, done, stuck)
+		t0 := clock.start()
+		g, ok := least(func(g string) bool { return hasPinned(ready[g], processes) })
+		if !ok {
+			g, _ = least(func(g string) bool { return len(ready[g]) > 0 })
+		}
+		k := firstPinned(ready[g], processes)
+		i := ready[g][k]
+		ready[g] = append(ready[g][:k], ready[g][k+1:]...)
+		waiting--
+		clock.stop(t0)
+
+		run := remaining[i]
+		if s.Quantum > 0 && s.Quantum < run {
+			run = s.Quantum
+		}
+		gantt = append(gantt, TimeSlice{PID: processes[i].ProcessID, Start: now, Stop: now + run})
+		queueArea += int64(waiting) * run
+		now += run
+		remaining[i] -= run
+		usage[g] += float64(run) / s.weight(g)
+
+		if remaining[i] == 0 {
+			arr.complete(i)
+			active[g]--
+		}
+		admit()
+		if remaining[i] > 0 {
+			ready[g] = append(ready[g], i)
+			waiting++
+			continue
+		}
+		done++
+		timings[i].Completion = now
+		timings[i].Turnaround = now - processes[i].ArrivalTime
+		timings[i].Waiting = timings[i].Turnaround - processes[i].BurstDuration
+	}
+
+	return newScheduleResult(processes, gantt, timings).withQueueArea(queueArea)
+}
+
+// Share is the fraction of a schedule's busy CPU time that went to one process or group.
+type Share struct {
+	Name  string
+	Share float64
+}
+
+// CPUShares returns the share of r's busy CPU time each group, in name order, and each process,
+// in the order the processes were given, received. Warmup slices count towards their process.
+func CPUShares(r ScheduleResult) (groups, processes []Share) {
+	used := make(map[string]int64, len(r.Rows))
+	var busy int64
+	for _, slice := range r.Gantt {
+		used[strings.TrimPrefix(slice.PID, warmupPrefix)] += slice.Stop - slice.Start
+		busy += slice.Stop - slice.Start
+	}
+	if busy == 0 {
+		busy = 1
+	}
+
+	groupIndex := make(map[string]int)
+	for _, row := range r.Rows {
+		share := float64(used[row.Process.ProcessID]) / float64(busy)
+		processes = append(processes, Share{Name: row.Process.ProcessID, Share: share})
+		g := row.Process.GroupID
+		if _, ok := groupIndex[g]; !ok {
+			groupIndex[g] = len(groups)
+			groups = append(groups, Share{Name: g})
+		}
+		groups[groupIndex[g]].Share += share
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		return groups[i].Name < groups[j].Name
+	})
+	return groups, processes
+}
+
+// JainIndex is Jain's fairness index of shares, (sum x)^2 / (n * sum x^2): 1 when every share is
+// equal, falling towards 1/n as one takes everything. It is 0 for no shares or no CPU time.
+func JainIndex(shares []Share) float64 {
+	var sum, squares float64
+	for _, share := range shares {
+		sum += share.Share
+		squares += share.Share * share.Share
+	}
+	if squares == 0 {
+		return 0
+	}
+	return sum * sum / (float64(len(shares)) * squares)
+}
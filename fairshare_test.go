@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFairShare_Schedule(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: "A1", ArrivalTime: 0, BurstDuration: 6, GroupID: "A"},
+		{ProcessID: "B1", ArrivalTime: 0, BurstDuration: 3, GroupID: "B"},
+		{ProcessID: "B2", ArrivalTime: 0, BurstDuration: 3, GroupID: "B"},
+	}
+	r := FairShare{Quantum: 1, Weights: map[string]int64{"A": 2}}.Schedule(processes)
+
+	// While both groups are ready, A gets twice B's CPU, and B's processes take turns.
+	want := []TimeSlice{
+		{PID: "A1", Start: 0, Stop: 1},
+		{PID: "B1", Start: 1, Stop: 2},
+		{PID: "A1", Start: 2, Stop: 3},
+		{PID: "A1", Start: 3, Stop: 4},
+		{PID: "B2", Start: 4, Stop: 5},
+		{PID: "A1", Start: 5, Stop: 6},
+		{PID: "A1", Start: 6, Stop: 7},
+		{PID: "B1", Start: 7, Stop: 8},
+		{PID: "A1", Start: 8, Stop: 9},
+		{PID: "B2", Start: 9, Stop: 10},
+		{PID: "B1", Start: 10, Stop: 11},
+		{PID: "B2", Start: 11, Stop: 12},
+	}
+	if diff := cmp.Diff(r.Gantt, want); diff != "" {
+		t.Errorf(diff)
+	}
+
+	groups, shares := CPUShares(r)
+	if diff := cmp.Diff(groups, []Share{{"A", 0.5}, {"B", 0.5}}); diff != "" {
+		t.Errorf("group shares: %s", diff)
+	}
+	if diff := cmp.Diff(shares, []Share{{"A1", 0.5}, {"B1", 0.25}, {"B2", 0.25}}); diff != "" {
+		t.Errorf("process shares: %s", diff)
+	}
+
+	var w bytes.Buffer
+	outputSchedule(&w, nil, r, RenderOptions{})
+	for _, line := range []string{
+		"CPU share by group: A 50.00% B 50.00%\n",
+		"CPU share by process: A1 50.00% B1 25.00% B2 25.00%\n",
+	} {
+		if !strings.Contains(w.String(), line) {
+			t.Errorf("summary is missing %q:\n%s", line, w.String())
+		}
+	}
+}
+
+func TestFairShare_LateGroup(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: "A1", ArrivalTime: 0, BurstDuration: 10, GroupID: "A"},
+		{ProcessID: "B1", ArrivalTime: 4, BurstDuration: 6, GroupID: "B"},
+	}
+	r := FairShare{Quantum: 1}.Schedule(processes)
+
+	// B does not get back the time it was not ready for: it shares from its arrival on.
+	var got []string
+	for _, slice := range r.Gantt[4:8] {
+		got = append(got, slice.PID)
+	}
+	if diff := cmp.Diff(got, []string{"A1", "B1", "A1", "B1"}); diff != "" {
+		t.Errorf(diff)
+	}
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"slices"
+	"sort"
+)
+
+// GangRR time-slices Cores CPUs among groups of processes, keyed by GroupID. The groups with
+// ready processes take turns in the order they first became ready, and during its turn a group
+// has every core to itself: up to Cores of its ready processes run at once, one per core, for
+// a Quantum, or until they complete. Cores the group cannot fill sit idle, which is the price
+// of gang scheduling a group together. A group with more ready processes than cores runs them
+// round-robin across its turns. A Quantum <= 0 runs each turn until every process in it
+// completes.
+//
+// Use CPUShares for the share of the CPU each group received and JainIndex for how evenly.
+type GangRR struct {
+	Cores   int
+	Quantum int64
+}
+
+func (GangRR) Name() string { return "gang-rr" }
+
+func (s GangRR) Schedule(processes []Process) ScheduleResult {
+	return s.scheduleClocked(processes, nil)
+}
+
+func (s GangRR) scheduleClocked(processes []Process, clock *decisionClock) ScheduleResult {
+	cores := s.Cores
+	if cores < 1 {
+		cores = 1
+	}
+	arr := newArrivals(processes)
+	remaining := make([]int64, len(processes))
+	for i := range processes {
+		remaining[i] = processes[i].BurstDuration
+	}
+
+	var (
+		now       int64
+		waiting   int
+		turns     []string                 // the groups with ready processes, in turn order.
+		queued    = make(map[string]bool)  // the groups in turns or taking their turn.
+		ready     = make(map[string][]int) // each group's round-robin queue.
+		queueArea int64                    // integral of the ready queue length over time.
+		timings   = make([]processTiming, len(processes))
+		gantt     = make([]TimeSlice, 0, len(processes))
+		dog       watchdog
+		stuck     = unfinished(processes, remaining)
+	)
+	admit := func() {
+		arr.release(now, func(i int) {
+			// Processes that arrived during the last turn have been waiting since arrival.
+			queueArea += now - processes[i].ArrivalTime
+			g := processes[i].GroupID
+			if !queued[g] {
+				turns = append(turns, g)
+				queued[g] = true
+			}
+			ready[g] = append(ready[g], i)
+			waiting++
+		})
+	}
+	for done := 0; done < len(processes); {
+		admit()
+		if waiting == 0 {
+			// CPU is idle until the next arrival.
+			now = arr.idleUntil(now)
+			continue
+		}
+		dog.observe(now, done, stuck)
+		t0 := clock.start()
+		t := slices.IndexFunc(turns, func(g string) bool { return hasPinned(ready[g], processes) })
+		if t < 0 {
+			t = 0
+		}
+		g := turns[t]
+		turns = append(turns[:t], turns[t+1:]...)
+		sort.SliceStable(ready[g], func(a, b int) bool {
+			return processes[ready[g][a]].Pinned && !processes[ready[g][b]].Pinned
+		})
+		gang := ready[g][:min(cores, len(ready[g]))]
+		ready[g] = ready[g][len(gang):]
+		waiting -= len(gang)
+		clock.stop(t0)
+
+		var turn int64
+		for c, i := range gang {
+			run := remaining[i]
+			if s.Quantum > 0 && s.Quantum < run {
+				run = s.Quantum
+			}
+			gantt = append(gantt, TimeSlice{PID: processes[i].ProcessID, Start: now, Stop: now + run, Core: c})
+			remaining[i] -= run
+			turn = max(turn, run)
+			if remaining[i] == 0 {
+				done++
+				timings[i].Completion = now + run
+				timings[i].Turnaround = now + run - processes[i].ArrivalTime
+				timings[i].Waiting = timings[i].Turnaround - processes[i].BurstDuration
+			}
+		}
+		// A process that ran for less than the turn completed, so only the processes that were
+		// not in the gang wait through it.
+		queueArea += int64(waiting) * turn
+		now += turn
+
+		for _, i := range gang {
+			if remaining[i] == 0 {
+				arr.complete(i)
+			}
+		}
+		admit()
+		for _, i := range gang {
+			if remaining[i] > 0 {
+				ready[g] = append(ready[g], i)
+				waiting++
+			}
+		}
+		queued[g] = len(ready[g]) > 0
+		if queued[g] {
+			turns = append(turns, g)
+		}
+	}
+
+	r := newScheduleResult(processes, gantt, timings).withQueueArea(queueArea)
+	r.Cores = cores
+	return r
+}
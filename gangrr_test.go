@@ -0,0 +1,73 @@
+package main
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestGangRR_Schedule(t *testing.T) {
+	t.Parallel()
+	// On two cores, web's two processes run together in its turns, batch's one alone in its.
+	// web's late third process waits for web's next turn and runs with the other two
+	// round-robin.
+	processes := []Process{
+		{ProcessID: "W1", ArrivalTime: 0, BurstDuration: 4, GroupID: "web"},
+		{ProcessID: "W2", ArrivalTime: 0, BurstDuration: 1, GroupID: "web"},
+		{ProcessID: "B1", ArrivalTime: 0, BurstDuration: 3, GroupID: "batch"},
+		{ProcessID: "W3", ArrivalTime: 1, BurstDuration: 2, GroupID: "web"},
+	}
+	r := GangRR{Cores: 2, Quantum: 2}.Schedule(processes)
+	want := []TimeSlice{
+		{PID: "W1", Start: 0, Stop: 2, Core: 0},
+		{PID: "W2", Start: 0, Stop: 1, Core: 1},
+		{PID: "B1", Start: 2, Stop: 4, Core: 0},
+		{PID: "W3", Start: 4, Stop: 6, Core: 0},
+		{PID: "W1", Start: 4, Stop: 6, Core: 1},
+		{PID: "B1", Start: 6, Stop: 7, Core: 0},
+	}
+	if diff := cmp.Diff(want, r.Gantt); diff != "" {
+		t.Errorf(diff)
+	}
+	if r.Cores != 2 || r.Makespan != 7 {
+		t.Errorf("cores %d, makespan %d, want 2 and 7", r.Cores, r.Makespan)
+	}
+
+	groups, _ := CPUShares(r)
+	if diff := cmp.Diff([]Share{{"batch", 0.3}, {"web", 0.7}}, groups, cmp.Comparer(func(a, b float64) bool {
+		return math.Abs(a-b) < 1e-9
+	})); diff != "" {
+		t.Errorf("group shares: %s", diff)
+	}
+	var w bytes.Buffer
+	outputSchedule(&w, nil, r, RenderOptions{})
+	if line := "Group fairness: 0.86 (Jain's index, 1 is equal)\n"; !strings.Contains(w.String(), line) {
+		t.Errorf("summary is missing %q:\n%s", line, w.String())
+	}
+}
+
+func TestJainIndex(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name   string
+		shares []Share
+		want   float64
+	}{
+		{name: "none", want: 0},
+		{name: "equal", shares: []Share{{"A", 0.5}, {"B", 0.5}}, want: 1},
+		{name: "one takes all", shares: []Share{{"A", 1}, {"B", 0}, {"C", 0}, {"D", 0}}, want: 0.25},
+		{name: "uneven", shares: []Share{{"A", 0.75}, {"B", 0.25}}, want: 0.8},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := JainIndex(tt.shares); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("JainIndex() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
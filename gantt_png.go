@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"strings"
+)
+
+var (
+	idleColor  = color.Gray{Y: 0xc0}
+	labelColor = color.Black
+	// ganttPalette colors PIDs missing from the caller's color map, in order of first appearance.
+	ganttPalette = []color.Color{
+		color.RGBA{R: 0x4e, G: 0x79, B: 0xa7, A: 0xff},
+		color.RGBA{R: 0xf2, G: 0x8e, B: 0x2b, A: 0xff},
+		color.RGBA{R: 0x59, G: 0xa1, B: 0x4f, A: 0xff},
+		color.RGBA{R: 0xe1, G: 0x57, B: 0x59, A: 0xff},
+		color.RGBA{R: 0x76, G: 0xb7, B: 0xb2, A: 0xff},
+		color.RGBA{R: 0xed, G: 0xc9, B: 0x48, A: 0xff},
+		color.RGBA{R: 0xb0, G: 0x7a, B: 0xa1, A: 0xff},
+		color.RGBA{R: 0xff, G: 0x9d, B: 0xa7, A: 0xff},
+	}
+)
+
+// outputGanttPNG draws gantt as a width by height PNG: one bar per slice in its PID's color from
+// colors, labeled with the PID where it fits, and gray for idle time between slices. PIDs
+// without a color take one from a fixed palette.
+func outputGanttPNG(w io.Writer, gantt []TimeSlice, width, height int, colors map[string]color.Color) error {
+	if width <= 0 || height <= 0 {
+		return fmt.Errorf("%w: image size %dx%d", ErrInvalidArgs, width, height)
+	}
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), &image.Uniform{C: idleColor}, image.Point{}, draw.Src)
+	if len(gantt) == 0 {
+		return png.Encode(w, img)
+	}
+
+	start, stop := gantt[0].Start, gantt[0].Stop
+	for _, slice := range gantt {
+		start = min(start, slice.Start)
+		stop = max(stop, slice.Stop)
+	}
+	span := max(1, stop-start)
+	x := func(t int64) int {
+		return int((t - start) * int64(width) / span)
+	}
+
+	assigned := make(map[string]color.Color, len(colors))
+	for pid, c := range colors {
+		assigned[pid] = c
+	}
+	scale := height / 20
+	if scale < 1 {
+		scale = 1
+	}
+	for _, slice := range gantt {
+		c, ok := assigned[slice.PID]
+		if !ok {
+			c = ganttPalette[len(assigned)%len(ganttPalette)]
+			assigned[slice.PID] = c
+		}
+		bar := image.Rect(x(slice.Start), 0, x(slice.Stop), height)
+		draw.Draw(img, bar, &image.Uniform{C: c}, image.Point{}, draw.Src)
+		if labelWidth(slice.PID, scale)+4*scale <= bar.Dx() {
+			drawLabel(img, bar.Min.X+2*scale, 2*scale, slice.PID, scale)
+		}
+	}
+
+	return png.Encode(w, img)
+}
+
+// glyphs is a 3x5 pixel font for labels; each row holds 3 bits, most significant on the left.
+var glyphs = map[rune][5]uint8{
+	'0': {7, 5, 5, 5, 7}, '1': {2, 6, 2, 2, 7}, '2': {7, 1, 7, 4, 7}, '3': {7, 1, 7, 1, 7},
+	'4': {5, 5, 7, 1, 1}, '5': {7, 4, 7, 1, 7}, '6': {7, 4, 7, 5, 7}, '7': {7, 1, 1, 1, 1},
+	'8': {7, 5, 7, 5, 7}, '9': {7, 5, 7, 1, 7}, 'A': {2, 5, 7, 5, 5}, 'B': {6, 5, 6, 5, 6},
+	'C': {3, 4, 4, 4, 3}, 'D': {6, 5, 5, 5, 6}, 'E': {7, 4, 6, 4, 7}, 'F': {7, 4, 6, 4, 4},
+	'G': {3, 4, 5, 5, 3}, 'H': {5, 5, 7, 5, 5}, 'I': {7, 2, 2, 2, 7}, 'J': {1, 1, 1, 5, 2},
+	'K': {5, 5, 6, 5, 5}, 'L': {4, 4, 4, 4, 7}, 'M': {5, 7, 7, 5, 5}, 'N': {6, 5, 5, 5, 5},
+	'O': {2, 5, 5, 5, 2}, 'P': {6, 5, 6, 4, 4}, 'Q': {2, 5, 5, 6, 3}, 'R': {6, 5, 6, 5, 5},
+	'S': {3, 4, 2, 1, 6}, 'T': {7, 2, 2, 2, 2}, 'U': {5, 5, 5, 5, 7}, 'V': {5, 5, 5, 5, 2},
+	'W': {5, 5, 7, 7, 5}, 'X': {5, 5, 2, 5, 5}, 'Y': {5, 5, 2, 2, 2}, 'Z': {7, 1, 2, 4, 7},
+	'-': {0, 0, 7, 0, 0}, '_': {0, 0, 0, 0, 7},
+}
+
+// labelWidth is the width in pixels of label drawn at scale.
+func labelWidth(label string, scale int) int {
+	return len([]rune(label))*4*scale - scale
+}
+
+// drawLabel draws label with its top-left corner at x, y. Lower case is drawn as upper case and
+// characters without a glyph leave a gap.
+func drawLabel(img *image.RGBA, x, y int, label string, scale int) {
+	for _, r := range strings.ToUpper(label) {
+		for row, bits := range glyphs[r] {
+			for col := 0; col < 3; col++ {
+				if bits&(4>>col) == 0 {
+					continue
+				}
+				px := image.Rect(x+col*scale, y+row*scale, x+(col+1)*scale, y+(row+1)*scale)
+				draw.Draw(img, px, &image.Uniform{C: labelColor}, image.Point{}, draw.Src)
+			}
+		}
+		x += 4 * scale
+	}
+}
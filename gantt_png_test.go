@@ -0,0 +1,49 @@
+package main
+
+import (
+	"bytes"
+	"image/color"
+	"image/png"
+	"testing"
+)
+
+func Test_outputGanttPNG(t *testing.T) {
+	t.Parallel()
+	gantt := []TimeSlice{
+		{PID: "P0", Start: 0, Stop: 5},
+		{PID: "P1", Start: 10, Stop: 20},
+	}
+	red := color.RGBA{R: 0xff, A: 0xff}
+	var w bytes.Buffer
+	if err := outputGanttPNG(&w, gantt, 200, 40, map[string]color.Color{"P0": red}); err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(&w)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := img.Bounds(); b.Dx() != 200 || b.Dy() != 40 {
+		t.Fatalf("size = %v, want 200x40", b)
+	}
+	tests := []struct {
+		name string
+		x, y int
+		want color.Color
+	}{
+		{name: "mapped color", x: 25, y: 35, want: red},
+		{name: "idle", x: 75, y: 35, want: idleColor},
+		{name: "palette color", x: 150, y: 35, want: ganttPalette[1]},
+		{name: "label", x: 104, y: 4, want: labelColor},
+	}
+	for _, tt := range tests {
+		r1, g1, b1, a1 := img.At(tt.x, tt.y).RGBA()
+		r2, g2, b2, a2 := tt.want.RGBA()
+		if r1 != r2 || g1 != g2 || b1 != b2 || a1 != a2 {
+			t.Errorf("%s: pixel (%d, %d) = %v, want %v", tt.name, tt.x, tt.y, img.At(tt.x, tt.y), tt.want)
+		}
+	}
+
+	if err := outputGanttPNG(&w, gantt, 0, 40, nil); err == nil {
+		t.Error("expected an error for an empty image")
+	}
+}
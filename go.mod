@@ -4,7 +4,6 @@ go 1.22.1
 
 require (
 	github.com/google/go-cmp v0.6.0
+	github.com/mattn/go-runewidth v0.0.9
 	github.com/olekukonko/tablewriter v0.0.5
 )
-
-require github.com/mattn/go-runewidth v0.0.9 // indirect
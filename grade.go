@@ -0,0 +1,109 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// ErrInvalidGantt is wrapped by the errors LoadGantt returns for a malformed reference Gantt.
+var ErrInvalidGantt = errors.New("invalid gantt")
+
+// ganttRecord is a slice of a reference Gantt in JSON.
+type ganttRecord struct {
+	PID   string `json:"pid"`
+	Start int64  `json:"start"`
+	Stop  int64  `json:"stop"`
+	Core  int    `json:"core"`
+}
+
+// LoadGantt reads a reference Gantt from r, in the schedule's ticks: either a JSON array of
+// {"pid", "start", "stop"} objects, with an optional "core", or CSV with a header row and then
+// one PID,Start,Stop[,Core] row per slice, in order. JSON is told apart by its leading [.
+func LoadGantt(r io.Reader) ([]TimeSlice, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: reading the reference Gantt", err)
+	}
+	if bytes.HasPrefix(bytes.TrimSpace(b), []byte("[")) {
+		var records []ganttRecord
+		if err := json.Unmarshal(b, &records); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidGantt, err)
+		}
+		gantt := make([]TimeSlice, len(records))
+		for i, rec := range records {
+			gantt[i] = TimeSlice{PID: rec.PID, Start: rec.Start, Stop: rec.Stop, Core: rec.Core}
+		}
+		return gantt, nil
+	}
+
+	reader := csv.NewReader(bytes.NewReader(b))
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+	var gantt []TimeSlice
+	for header := true; ; header = false {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidGantt, err)
+		}
+		if header {
+			continue
+		}
+		line, _ := reader.FieldPos(0)
+		if len(row) != 3 && len(row) != 4 {
+			return nil, fmt.Errorf("%w: line %d: want 3 or 4 columns, got %d", ErrInvalidGantt, line, len(row))
+		}
+		slice := TimeSlice{PID: row[0]}
+		if slice.Start, err = strToInt(row[1]); err != nil {
+			return nil, fmt.Errorf("%w: line %d: start", ErrInvalidGantt, line)
+		}
+		if slice.Stop, err = strToInt(row[2]); err != nil {
+			return nil, fmt.Errorf("%w: line %d: stop", ErrInvalidGantt, line)
+		}
+		if len(row) == 4 {
+			core, err := strToInt(row[3])
+			if err != nil {
+				return nil, fmt.Errorf("%w: line %d: core", ErrInvalidGantt, line)
+			}
+			slice.Core = int(core)
+		}
+		gantt = append(gantt, slice)
+	}
+	return gantt, nil
+}
+
+// GradeAgainstReference schedules processes with sched and checks the Gantt against the
+// reference one in the file at refPath, as LoadGantt reads it, for grading a student's
+// schedule. It reports whether the two are identical and, if not, the first slice where they
+// diverge. The error is only for a reference that cannot be read.
+func GradeAgainstReference(refPath string, sched Scheduler, processes []Process) (bool, string, error) {
+	f, err := os.Open(refPath)
+	if err != nil {
+		return false, "", fmt.Errorf("%w: error opening the reference Gantt", err)
+	}
+	defer f.Close()
+	expected, err := LoadGantt(f)
+	if err != nil {
+		return false, "", fmt.Errorf("%w: %s", err, refPath)
+	}
+	if err := AssertGantt(expected, sched, processes); err != nil {
+		return false, err.Error(), nil
+	}
+	return true, "", nil
+}
+
+// outputGrade writes whether a schedule passed grading and, if not, where it diverged.
+func outputGrade(w io.Writer, pass bool, divergence string) {
+	if pass {
+		_, _ = fmt.Fprintln(w, "Grade: pass, the Gantt matches the reference")
+		return
+	}
+	_, _ = fmt.Fprintf(w, "Grade: fail, %s\n", divergence)
+}
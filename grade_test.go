@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLoadGantt(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		input   string
+		want    []TimeSlice
+		wantErr string
+	}{
+		{
+			name:  "csv",
+			input: "PID,Start,Stop\nP0,0,3\n# P1 is next\nP1,3,4\n",
+			want:  []TimeSlice{{PID: "P0", Start: 0, Stop: 3}, {PID: "P1", Start: 3, Stop: 4}},
+		},
+		{
+			name:  "csv with cores",
+			input: "PID,Start,Stop,Core\nP0,0,3,0\nP1,0,4,1\n",
+			want:  []TimeSlice{{PID: "P0", Start: 0, Stop: 3}, {PID: "P1", Start: 0, Stop: 4, Core: 1}},
+		},
+		{
+			name:  "json",
+			input: ` [{"pid": "P0", "start": 0, "stop": 3}, {"pid": "P1", "start": 0, "stop": 4, "core": 1}]`,
+			want:  []TimeSlice{{PID: "P0", Start: 0, Stop: 3}, {PID: "P1", Start: 0, Stop: 4, Core: 1}},
+		},
+		{name: "bad start", input: "PID,Start,Stop\nP0,zero,3\n", wantErr: "invalid gantt: line 2: start"},
+		{name: "columns", input: "PID,Start,Stop\nP0,0\n", wantErr: "invalid gantt: line 2: want 3 or 4 columns, got 2"},
+		{name: "bad json", input: `[{"pid": "P0"`, wantErr: "invalid gantt: unexpected end of JSON input"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := LoadGantt(strings.NewReader(tt.input))
+			if tt.wantErr != "" {
+				if !errors.Is(err, ErrInvalidGantt) {
+					t.Fatalf("error %v is not ErrInvalidGantt", err)
+				}
+				if diff := cmp.Diff(tt.wantErr, err.Error()); diff != "" {
+					t.Errorf(diff)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf(diff)
+			}
+		})
+	}
+}
+
+func TestGradeAgainstReference(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: "P0", ArrivalTime: 0, BurstDuration: 3},
+		{ProcessID: "P1", ArrivalTime: 1, BurstDuration: 1},
+	}
+	dir := t.TempDir()
+	ref := filepath.Join(dir, "reference.csv")
+	if err := os.WriteFile(ref, []byte("PID,Start,Stop\nP0,0,2\nP1,2,3\nP0,3,4\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		s          Scheduler
+		wantPass   bool
+		divergence string
+	}{
+		{s: RR{Quantum: 2}, wantPass: true},
+		{s: FCFS{}, divergence: "gantt mismatch: fcfs: slice 0: want {PID:P0 Start:0 Stop:2}, got {PID:P0 Start:0 Stop:3}"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.s.Name(), func(t *testing.T) {
+			t.Parallel()
+			pass, divergence, err := GradeAgainstReference(ref, tt.s, processes)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if pass != tt.wantPass {
+				t.Errorf("pass = %v, want %v", pass, tt.wantPass)
+			}
+			if diff := cmp.Diff(tt.divergence, divergence); diff != "" {
+				t.Errorf(diff)
+			}
+		})
+	}
+
+	if _, _, err := GradeAgainstReference(filepath.Join(dir, "missing.csv"), FCFS{}, processes); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("missing reference: error %v, want %v", err, os.ErrNotExist)
+	}
+
+	var w bytes.Buffer
+	outputGrade(&w, false, "gantt mismatch: fcfs: slice 0")
+	if diff := cmp.Diff("Grade: fail, gantt mismatch: fcfs: slice 0\n", w.String()); diff != "" {
+		t.Errorf(diff)
+	}
+}
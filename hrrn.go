@@ -0,0 +1,102 @@
+package main
+
+import "math"
+
+// HRRN schedules processes highest-response-ratio-next. A process's response ratio is
+// (waiting + remaining) / remaining, where waiting is the time it has spent ready but not
+// running and remaining is its burst left to run, so a job's priority rises the longer it waits
+// and short jobs still go early. Ties go to the earlier arrival.
+//
+// By default each process runs to completion, and ratios are compared whenever the CPU becomes
+// free. With Preemptive set, ratios are also recomputed at every arrival, and the running
+// process is preempted if a ready process then has a strictly higher ratio; between arrivals
+// and completions the running process keeps the CPU even though the waiting ratios keep rising.
+type HRRN struct {
+	Preemptive bool
+}
+
+func (HRRN) Name() string { return "hrrn" }
+
+func (s HRRN) Schedule(processes []Process) ScheduleResult { return s.scheduleClocked(processes, nil) }
+
+func (s HRRN) scheduleClocked(processes []Process, clock *decisionClock) ScheduleResult {
+	arr := newArrivals(processes)
+	remaining := make([]int64, len(processes))
+	for i := range processes {
+		remaining[i] = processes[i].BurstDuration
+	}
+
+	var (
+		now       int64
+		running   = -1
+		ready     []int // arrived and not complete, in arrival order, including running.
+		queueArea int64 // integral of the ready queue length over time.
+		timings   = make([]processTiming, len(processes))
+		gantt     = make([]TimeSlice, 0, len(processes))
+	)
+	ratio := func(i int) float64 {
+		if remaining[i] == 0 {
+			return math.Inf(1)
+		}
+		waiting := now - processes[i].ArrivalTime - (processes[i].BurstDuration - remaining[i])
+		return float64(waiting+remaining[i]) / float64(remaining[i])
+	}
+	for done := 0; done < len(processes); {
+		arr.release(now, func(i int) {
+			// Processes that arrived during the last run have been waiting since arrival.
+			queueArea += now - processes[i].ArrivalTime
+			ready = append(ready, i)
+		})
+		if len(ready) == 0 {
+			// CPU is idle until the next arrival.
+			now = arr.idleUntil(now)
+			continue
+		}
+
+		if running < 0 || s.Preemptive {
+			t0 := clock.start()
+			best := running
+			for _, i := range eligible(ready, processes, running) {
+				if best < 0 || ratio(i) > ratio(best) {
+					best = i
+				}
+			}
+			running = best
+			clock.stop(t0)
+		}
+
+		run := remaining[running]
+		if t, ok := arr.nextArrival(); s.Preemptive && ok {
+			run = min(run, t-now)
+		}
+		if n := len(gantt); n > 0 && gantt[n-1].PID == processes[running].ProcessID && gantt[n-1].Stop == now {
+			gantt[n-1].Stop += run
+		} else {
+			gantt = append(gantt, TimeSlice{PID: processes[running].ProcessID, Start: now, Stop: now + run})
+		}
+		queueArea += int64(len(ready)-1) * run
+		now += run
+		remaining[running] -= run
+		if remaining[running] > 0 {
+			continue
+		}
+
+		done++
+		arr.complete(running)
+		p := processes[running]
+		timings[running] = processTiming{
+			Waiting:    now - p.ArrivalTime - p.BurstDuration,
+			Turnaround: now - p.ArrivalTime,
+			Completion: now,
+		}
+		for n, i := range ready {
+			if i == running {
+				ready = append(ready[:n], ready[n+1:]...)
+				break
+			}
+		}
+		running = -1
+	}
+
+	return newScheduleResult(processes, gantt, timings).withQueueArea(queueArea)
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestHRRN_Schedule(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: "P0", ArrivalTime: 0, BurstDuration: 10},
+		{ProcessID: "P1", ArrivalTime: 1, BurstDuration: 2},
+		{ProcessID: "P2", ArrivalTime: 5, BurstDuration: 1},
+	}
+	tests := []struct {
+		name         string
+		hrrn         HRRN
+		wantGantt    []TimeSlice
+		wantWait     float64
+		wantSwitches int
+	}{
+		{
+			name: "non-preemptive",
+			// At 10, P2's ratio (5+1)/1 beats P1's (9+2)/2.
+			wantGantt: []TimeSlice{
+				{PID: "P0", Start: 0, Stop: 10},
+				{PID: "P2", Start: 10, Stop: 11},
+				{PID: "P1", Start: 11, Stop: 13},
+			},
+			wantWait:     5,
+			wantSwitches: 2,
+		},
+		{
+			name: "preemptive",
+			hrrn: HRRN{Preemptive: true},
+			// At P2's arrival P1's ratio (4+2)/2 beats the running P0's (0+5)/5, and at P1's
+			// completion P2's (2+1)/1 beats P0's (2+5)/5.
+			wantGantt: []TimeSlice{
+				{PID: "P0", Start: 0, Stop: 5},
+				{PID: "P1", Start: 5, Stop: 7},
+				{PID: "P2", Start: 7, Stop: 8},
+				{PID: "P0", Start: 8, Stop: 13},
+			},
+			wantWait:     3,
+			wantSwitches: 3,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := tt.hrrn.Schedule(processes)
+			if diff := cmp.Diff(got.Gantt, tt.wantGantt); diff != "" {
+				t.Errorf(diff)
+			}
+			if got.AvgWait != tt.wantWait {
+				t.Errorf("AvgWait = %v, want %v", got.AvgWait, tt.wantWait)
+			}
+			if got.ContextSwitches != tt.wantSwitches {
+				t.Errorf("ContextSwitches = %d, want %d", got.ContextSwitches, tt.wantSwitches)
+			}
+		})
+	}
+}
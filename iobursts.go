@@ -0,0 +1,175 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// IOBurst is a wait for I/O in the middle of a process's CPU burst: once the process has run
+// for At time units it leaves the CPU, blocked for Duration time units, before it can run the
+// rest of its burst.
+type IOBurst struct {
+	At       int64
+	Duration int64
+}
+
+// RRIO is round-robin over processes that block for I/O. A process runs for a Quantum, until
+// it completes, or until it reaches its next IOBurst, when it is blocked for the burst's
+// Duration while the CPU runs something else; every process does its own I/O, so blocked
+// processes never wait for each other. Processes that arrive and that return from I/O during a
+// slice join the ready queue in that order, ahead of the preempted process. A Quantum <= 0
+// never preempts, so processes only leave the CPU to complete or block.
+//
+// A process's Waiting is only its time in the ready queue; its time blocked for I/O is
+// Blocked, so the turnaround is the waiting plus the burst plus the time blocked.
+type RRIO struct {
+	Quantum int64
+}
+
+func (RRIO) Name() string { return "rr-io" }
+
+func (s RRIO) Schedule(processes []Process) ScheduleResult {
+	return s.scheduleClocked(processes, nil)
+}
+
+// wake is a process returning from I/O at a time.
+type wake struct {
+	at int64
+	i  int
+}
+
+func (s RRIO) scheduleClocked(processes []Process, clock *decisionClock) ScheduleResult {
+	arr := newArrivals(processes)
+	remaining := make([]int64, len(processes))
+	nextIO := make([]int, len(processes)) // the index of each process's next IOBurst.
+	for i := range processes {
+		remaining[i] = processes[i].BurstDuration
+	}
+
+	var (
+		now       int64
+		ready     []int
+		blocked   []wake // in the order they return.
+		queueArea int64  // integral of the ready queue length over time.
+		timings   = make([]processTiming, len(processes))
+		gantt     = make([]TimeSlice, 0, len(processes))
+		dog       watchdog
+		stuck     = unfinished(processes, remaining)
+	)
+	admit := func() {
+		arr.release(now, func(i int) {
+			// Processes that arrived during the last slice have been waiting since arrival.
+			queueArea += now - processes[i].ArrivalTime
+			ready = append(ready, i)
+		})
+		for len(blocked) > 0 && blocked[0].at <= now {
+			queueArea += now - blocked[0].at
+			ready = append(ready, blocked[0].i)
+			blocked = blocked[1:]
+		}
+	}
+	complete := func(i int) {
+		arr.complete(i)
+		timings[i].Completion = now
+		timings[i].Turnaround = now - processes[i].ArrivalTime
+		timings[i].Waiting = timings[i].Turnaround - processes[i].BurstDuration - timings[i].Blocked
+	}
+	for done := 0; done < len(processes); {
+		admit()
+		if len(ready) == 0 {
+			// CPU is idle until the next arrival or return from I/O, whichever is first.
+			next, ok := arr.nextArrival()
+			switch {
+			case len(blocked) > 0 && (!ok || blocked[0].at < next):
+				now = blocked[0].at
+			default:
+				now = arr.idleUntil(now)
+			}
+			continue
+		}
+		dog.observe(now, done, stuck)
+		t0 := clock.start()
+		k := firstPinned(ready, processes)
+		i := ready[k]
+		ready = append(ready[:k], ready[k+1:]...)
+		clock.stop(t0)
+
+		p := processes[i]
+		run := remaining[i]
+		if s.Quantum > 0 && s.Quantum < run {
+			run = s.Quantum
+		}
+		if k := nextIO[i]; k < len(p.IO) {
+			run = min(run, p.IO[k].At-(p.BurstDuration-remaining[i]))
+		}
+		gantt = append(gantt, TimeSlice{PID: p.ProcessID, Start: now, Stop: now + run})
+		queueArea += int64(len(ready)) * run
+		now += run
+		remaining[i] -= run
+
+		if remaining[i] == 0 {
+			done++
+			complete(i)
+			admit()
+			continue
+		}
+		if k := nextIO[i]; k < len(p.IO) && p.IO[k].At == p.BurstDuration-remaining[i] {
+			nextIO[i]++
+			timings[i].Blocked += p.IO[k].Duration
+			blocked = insertWake(blocked, wake{at: now + p.IO[k].Duration, i: i})
+			admit()
+			continue
+		}
+		admit()
+		ready = append(ready, i)
+	}
+
+	return newScheduleResult(processes, gantt, timings).withQueueArea(queueArea)
+}
+
+// insertWake adds w to blocked, which is ordered by return time, after any process returning
+// at the same time.
+func insertWake(blocked []wake, w wake) []wake {
+	n := len(blocked)
+	for n > 0 && blocked[n-1].at > w.at {
+		n--
+	}
+	blocked = append(blocked, wake{})
+	copy(blocked[n+1:], blocked[n:])
+	blocked[n] = w
+	return blocked
+}
+
+// validateIO checks that each of p's IOBursts comes after the last, or after the process has
+// started, and before the end of its CPU burst, and takes no negative time.
+func validateIO(p Process) error {
+	var last int64
+	for _, b := range p.IO {
+		if b.At <= last || b.At >= p.BurstDuration || b.Duration < 0 {
+			return fmt.Errorf("%w: %s: I/O at %d for %d is not within its burst, in order", ErrInvalidProcess, p.ProcessID, b.At, b.Duration)
+		}
+		last = b.At
+	}
+	return nil
+}
+
+// blocksOnIO reports whether any process in r spent time blocked for I/O.
+func blocksOnIO(r ScheduleResult) bool {
+	for _, row := range r.Rows {
+		if row.Blocked > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// outputIdleBreakdown writes how long each process spent off the CPU waiting in the ready queue
+// and blocked for I/O, which tells CPU contention apart from I/O-bound processes.
+func outputIdleBreakdown(w io.Writer, r ScheduleResult) {
+	parts := make([]string, len(r.Rows))
+	for i, row := range r.Rows {
+		parts[i] = fmt.Sprintf("%s %s/%s", row.Process.ProcessID, formatTime(row.Waiting, r.Resolution), formatTime(row.Blocked, r.Resolution))
+	}
+	_, _ = fmt.Fprintf(w, "Ready wait/blocked on I/O: %s\n", strings.Join(parts, ", "))
+}
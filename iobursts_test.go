@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRRIO_Schedule(t *testing.T) {
+	t.Parallel()
+	// A blocks for I/O after its first time unit, so B and C share the CPU until A returns at 4
+	// and queues behind B.
+	processes := []Process{
+		{ProcessID: "A", ArrivalTime: 0, BurstDuration: 4, IO: []IOBurst{{At: 1, Duration: 3}}},
+		{ProcessID: "B", ArrivalTime: 0, BurstDuration: 3},
+		{ProcessID: "C", ArrivalTime: 2, BurstDuration: 1},
+	}
+	r := RRIO{Quantum: 2}.Schedule(processes)
+	wantGantt := []TimeSlice{
+		{PID: "A", Start: 0, Stop: 1},
+		{PID: "B", Start: 1, Stop: 3},
+		{PID: "C", Start: 3, Stop: 4},
+		{PID: "B", Start: 4, Stop: 5},
+		{PID: "A", Start: 5, Stop: 7},
+		{PID: "A", Start: 7, Stop: 8},
+	}
+	if diff := cmp.Diff(wantGantt, r.Gantt); diff != "" {
+		t.Errorf(diff)
+	}
+	var got [][3]int64
+	for _, row := range r.Rows {
+		got = append(got, [3]int64{row.Waiting, row.Blocked, row.Turnaround})
+	}
+	if diff := cmp.Diff([][3]int64{{1, 3, 8}, {2, 0, 5}, {1, 0, 2}}, got); diff != "" {
+		t.Errorf("waiting, blocked, turnaround: %s", diff)
+	}
+	if err := VerifyTurnaround(r); err != nil {
+		t.Error(err)
+	}
+	if littles := r.Throughput * r.AvgWait; math.Abs(r.AvgQueueLength-littles) > 1e-9 {
+		t.Errorf("measured L = %v, Little's Law L = %v", r.AvgQueueLength, littles)
+	}
+
+	wantEvents := []Event{
+		{Time: 0, Type: EventArrive, PID: "A"},
+		{Time: 0, Type: EventArrive, PID: "B"},
+		{Time: 0, Type: EventDispatch, PID: "A"},
+		{Time: 1, Type: EventIOStart, PID: "A"},
+		{Time: 1, Type: EventDispatch, PID: "B"},
+		{Time: 2, Type: EventArrive, PID: "C"},
+		{Time: 3, Type: EventPreempt, PID: "B"},
+		{Time: 3, Type: EventDispatch, PID: "C"},
+		{Time: 4, Type: EventComplete, PID: "C"},
+		{Time: 4, Type: EventIOEnd, PID: "A"},
+		{Time: 4, Type: EventDispatch, PID: "B"},
+		{Time: 5, Type: EventComplete, PID: "B"},
+		{Time: 5, Type: EventDispatch, PID: "A"},
+		{Time: 8, Type: EventComplete, PID: "A"},
+	}
+	if diff := cmp.Diff(wantEvents, Events(r)); diff != "" {
+		t.Errorf("events: %s", diff)
+	}
+
+	var w bytes.Buffer
+	outputSchedule(&w, nil, r, RenderOptions{})
+	if line := "Ready wait/blocked on I/O: A 1/3, B 2/0, C 1/0\n"; !strings.Contains(w.String(), line) {
+		t.Errorf("summary is missing %q:\n%s", line, w.String())
+	}
+	w.Reset()
+	outputSchedule(&w, nil, RR{Quantum: 2}.Schedule(processes), RenderOptions{})
+	if strings.Contains(w.String(), "blocked on I/O") {
+		t.Errorf("summary without I/O has the breakdown:\n%s", w.String())
+	}
+}
+
+func TestValidateProcesses_IO(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		io   []IOBurst
+		ok   bool
+	}{
+		{name: "none", ok: true},
+		{name: "in order", io: []IOBurst{{At: 1, Duration: 2}, {At: 3, Duration: 0}}, ok: true},
+		{name: "at start", io: []IOBurst{{At: 0, Duration: 1}}},
+		{name: "at end", io: []IOBurst{{At: 4, Duration: 1}}},
+		{name: "out of order", io: []IOBurst{{At: 2, Duration: 1}, {At: 1, Duration: 1}}},
+		{name: "negative", io: []IOBurst{{At: 1, Duration: -1}}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := ValidateProcesses([]Process{{ProcessID: "P0", BurstDuration: 4, IO: tt.io}})
+			if tt.ok && err != nil || !tt.ok && !errors.Is(err, ErrInvalidProcess) {
+				t.Errorf("ValidateProcesses() = %v", err)
+			}
+		})
+	}
+}
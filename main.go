@@ -1,22 +1,98 @@
 package main
 
 import (
+	"bufio"
+	"bytes"
 	"encoding/csv"
 	"errors"
 	"flag"
 	"fmt"
 	"io"
 	"log"
+	"math"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/mattn/go-runewidth"
 	"github.com/olekukonko/tablewriter"
 )
 
 func main() {
 	// parse args.
 	flagSet := flag.NewFlagSet(os.Args[0], flag.ExitOnError)
+	quantum := flagSet.Int64("q", 2, "Round-robin time quantum")
+	explain := flagSet.Bool("explain", false, "Also print a footnote defining each metric and how it was computed for this run")
+	compact := flagSet.Bool("compact", false, "Print a single key=value summary line")
+	ndjson := flagSet.Bool("ndjson", false, "Print one JSON object per process and then a summary object, one per line")
+	prometheus := flagSet.Bool("prometheus", false, "Print the summary metrics in Prometheus text exposition format")
+	metricPrefix := flagSet.String("metric-prefix", DefaultMetricPrefix, "With -prometheus, the prefix of every metric name")
+	resolution := flagSet.Int64("resolution", 1, "Round-robin ticks per time unit; -q is given in ticks")
+	checkpoints := flagSet.Bool("checkpoints", false, "Treat zero-burst processes as zero-cost checkpoints")
+	pngPath := flagSet.String("png", "", "Also write the GANTT chart as a PNG image to this file")
+	gradePath := flagSet.String("grade", "", "Also grade the Gantt against the reference one in this CSV or JSON file")
+	dotPath := flagSet.String("dot", "", "Also write the dependencies and timing as a Graphviz DOT graph to this file")
+	exportPath := flagSet.String("export", "", "Also write the processes, configuration, result and event trace as a JSON bundle to this file")
+	teePath := flagSet.String("tee", "", "Also write the output to this file")
+	trace := flagSet.Bool("trace", false, "Also print the event trace")
+	traceJSON := flagSet.Bool("trace-json", false, "Also print the event trace as JSON")
+	higherFirst := flagSet.Bool("higher-priority-first", false, "Treat larger priority values as more urgent")
+	decisionTime := flagSet.Bool("decision-time", false, "Also print the wall-clock time spent on scheduling decisions")
+	decisionFirst := flagSet.Bool("decision-first", false, "Choose the next process before admitting one that arrives at that instant")
+	quantumTies := flagSet.Bool("quantum-ties", false, "Also list the instants a process was preempted as another arrived, which -decision-first orders differently")
+	sectioned := flagSet.Bool("sections", false, "Read several workloads from the file, each after a --- name --- line, and run each in name order")
+	perProcess := flagSet.Bool("per-process", false, "With -compare, also print each process's wait and turnaround under every algorithm")
+	compare := flagSet.Bool("compare", false, "Compare every algorithm on the workload instead of running one")
+	repl := flagSet.Bool("repl", false, "Read commands to add processes, pick an algorithm and run from standard input instead of a workload")
+	animate := flagSet.Duration("animate", 0, "Draw the GANTT chart a slice at a time with this delay, e.g. 300ms")
+	byCompletion := flagSet.Bool("by-completion", false, "List the schedule table in completion order instead of input order")
+	cores := flagSet.Int("cores", 1, "Run first-come, first-serve on this many CPUs and report each one's utilization")
+	var dispatch DispatchPolicy
+	flagSet.Var(&dispatch, "dispatch", "With -cores, queue each process on the first-free, round-robin or least-loaded core")
+	backfill := flagSet.Bool("backfill", false, "With -cores, share one queue and backfill gaps, reporting the makespan saved")
+	var priorities *PriorityRange
+	flagSet.Func("priority-range", "Reject processes whose priority is outside min:max, e.g. 1:10", func(s string) error {
+		r, err := ParsePriorityRange(s)
+		priorities = &r
+		return err
+	})
+	var priorityNames map[string]int64
+	flagSet.Func("priority-names", "Read these names as priorities instead of high=1,normal=2,low=3, e.g. urgent=0,high=1", func(s string) error {
+		var err error
+		priorityNames, err = ParsePriorityNames(s)
+		return err
+	})
+	sharing := flagSet.Bool("processor-sharing", false, "Also print the completions under ideal processor sharing, the limit of round-robin as the quantum goes to 0")
+	load := flagSet.Bool("load", false, "Also print the offered load ρ against the throughput achieved, flagging ρ ≥ 1")
+	convoy := flagSet.Bool("convoy", false, "Also print how much longer the short processes waited than under SJF")
+	tieRuns := flagSet.Int("check-ties", 0, "Also rerun the workload this many times with ties resolved at random and report any metric that changes")
+	tieSeed := flagSet.Int64("tie-seed", 1, "With -check-ties, the seed for resolving ties")
+	var objective Objective
+	flagSet.Var(&objective, "check-optimal", "Also check the schedule against the best non-preemptive one by wait or makespan, for up to 10 processes")
+	coach := flagSet.Bool("coach", false, "Also review each dispatch of a non-preemptive schedule against the best choice for average wait, for up to 10 processes")
+	var percentiles []float64
+	flagSet.Func("percentiles", "Also print these percentiles of the wait and turnaround, e.g. 50,95,99", func(s string) error {
+		var err error
+		percentiles, err = parsePercentiles(s)
+		return err
+	})
+	axis := flagSet.Bool("axis", false, "Also draw a time axis to scale under the GANTT chart")
+	tick := flagSet.Int64("tick", 0, "With -axis, the time between ticks; 0 picks one from the makespan")
+	arrivalMarks := flagSet.Bool("arrivals", false, "Also draw the time axis with a marker at each process's arrival")
+	energy := flagSet.Bool("energy", false, "Also print the energy used, running at each process's power and idling at -idle-power")
+	idlePower := flagSet.Float64("idle-power", 0.1, "With -energy, the power an idle core draws")
+	dropout := flagSet.Float64("dropout", 0, "Fail this fraction of the processes at a random point in their run, and report the goodput")
+	dropoutSeed := flagSet.Int64("dropout-seed", 1, "With -dropout, the seed for picking the failures")
+	powerDown := flagSet.Int64("power-down", -1, "Power the CPU down once it idles for longer than this, and report the energy saved against the wake delays; -1 never does")
+	wakeLatency := flagSet.Int64("wake-latency", 1, "With -power-down, the time the CPU takes to wake before the arriving process can run")
+	frequency := flagSet.Float64("frequency", 1, "Run every process at this fraction of full speed, stretching bursts and cutting power cubically")
+	noGantt := flagSet.Bool("no-gantt", false, "Leave out the GANTT chart and print only the table")
+	noTable := flagSet.Bool("no-table", false, "Leave out the schedule table and summary and print only the GANTT chart")
+	slowdownAlert := flagSet.Float64("slowdown-alert", 0, "Add each process's slowdown, turnaround over burst, to the table and flag those above this as badly delayed")
+	var rounding Rounding
+	flagSet.Var(&rounding, "round", "Print average wait and turnaround as integers rounded by floor, ceil or nearest")
 	scheduler, data, err := parseCLI(flagSet, os.Args[1:])
 	if err != nil {
 		_, _ = fmt.Fprintln(os.Stdout, err)
@@ -24,36 +100,373 @@ func main() {
 		os.Exit(1)
 	}
 
-	// Load and parse processes.
-	processes, err := loadProcesses(data)
+	if *repl {
+		cfg := SchedulerConfig{Quantum: *quantum, Resolution: *resolution, HigherPriorityFirst: *higherFirst, Priorities: priorities, PriorityNames: priorityNames}
+		if err := RunREPL(os.Stdin, os.Stdout, scheduler.String(), cfg); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	// Load and parse processes, or with -sections, workloads.
+	var (
+		processes []Process
+		workloads map[string][]Process
+	)
+	if *sectioned {
+		workloads, err = loadSections(data, priorityNames)
+	} else {
+		processes, err = loadProcesses(data, priorityNames)
+	}
 	if err != nil {
 		log.Fatal(err)
 	}
 
+	if *prometheus {
+		if err := ValidateMetricPrefix(*metricPrefix); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if *frequency != 1 {
+		if processes, err = ScaleFrequency(processes, *frequency); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	var out io.Writer = os.Stdout
+	if *teePath != "" {
+		f, err := os.Create(*teePath)
+		if err != nil {
+			log.Fatal(fmt.Errorf("%w: error creating output file", err))
+		}
+		defer func() {
+			if err := f.Close(); err != nil {
+				log.Fatal(err)
+			}
+		}()
+		out = Tee(os.Stdout, f)
+	}
+
 	// Run the given scheduler.
-	switch scheduler {
-	case fcfs:
-		FCFSSchedule(os.Stdout, "First-come, first-serve", processes)
-	case sjf:
-		SJFSchedule(os.Stdout, "Shortest-job-first", processes)
-	case sjfp:
-		SJFPrioritySchedule(os.Stdout, "Priority", processes)
-	case rr:
-		RRSchedule(os.Stdout, "Round-robin", processes)
+	cfg := SchedulerConfig{Quantum: *quantum, Resolution: *resolution, HigherPriorityFirst: *higherFirst, Checkpoints: *checkpoints, Cores: *cores, Dispatch: dispatch, Backfill: *backfill, Priorities: priorities, PriorityNames: priorityNames}
+	if err := cfg.ValidatePriorities(processes); err != nil {
+		log.Fatal(err)
+	}
+	if err := ValidateResolution(processes, cfg.Resolution); err != nil {
+		log.Fatal(err)
+	}
+	if *decisionFirst {
+		cfg.Boundary = DecisionFirst
+	}
+	opts := RenderOptions{Rounding: rounding, ByCompletion: *byCompletion, Animate: *animate, Percentiles: percentiles, TimeAxis: *axis || *arrivalMarks, TickInterval: *tick, ArrivalMarkers: *arrivalMarks, SlowdownAlert: *slowdownAlert, NoGantt: *noGantt, NoTable: *noTable}
+	if workloads != nil {
+		if err := outputSections(out, schedulerFor(scheduler, cfg), scheduler.title(), workloads, cfg, opts); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	if *compare {
+		// The algorithms are compared on one CPU, and the dispatch policies on -cores.
+		single := cfg
+		single.Cores = 0
+		comparisons := CompareAll(processes, single)
+		outputComparison(out, comparisons, opts)
+		if *perProcess {
+			outputProcessComparison(out, comparisons)
+		}
+		if *cores > 1 {
+			outputDispatchComparison(out, CompareDispatch(processes, *cores), opts)
+		}
+		return
+	}
+	s := schedulerFor(scheduler, cfg)
+	var failing Dropout
+	if *dropout != 0 {
+		if *dropout < 0 || *dropout > 1 {
+			log.Fatal(fmt.Errorf("%w: dropout %v is not in [0, 1]", ErrInvalidArgs, *dropout))
+		}
+		failing = Dropout{Scheduler: s, Fraction: *dropout, Seed: *dropoutSeed}
+		s = failing
+	}
+	if *powerDown >= 0 {
+		if *wakeLatency < 0 {
+			log.Fatal(fmt.Errorf("%w: wake latency %d is negative", ErrInvalidArgs, *wakeLatency))
+		}
+		s = PowerDown{Scheduler: s, Threshold: *powerDown, WakeLatency: *wakeLatency}
+	}
+	r, decisions, _ := MeasureDecisions(s, processes)
+	if *pngPath != "" {
+		if err := writeGanttPNG(*pngPath, r.Gantt); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if *dotPath != "" {
+		if err := writeDOT(*dotPath, r); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if *exportPath != "" {
+		if err := writeRun(*exportPath, s.Name(), processes, cfg, r); err != nil {
+			log.Fatal(err)
+		}
+	}
+	switch {
+	case *compact:
+		outputCompact(out, s.Name(), r, opts)
+	case *ndjson:
+		if err := encodeNDJSON(out, s.Name(), r); err != nil {
+			log.Fatal(err)
+		}
+	case *prometheus:
+		outputPrometheus(out, *metricPrefix, s.Name(), r)
+	case scheduler == sjfp:
+		outputSJFPriority(out, scheduler.title(), r, opts)
+		if *explain {
+			outputMetricNotes(out, s, r)
+		}
+	default:
+		outputResult(out, scheduler.title(), r, opts)
+		if !opts.NoTable {
+			outputAlgorithmSummary(out, s, r)
+		}
+		if *explain {
+			outputMetricNotes(out, s, r)
+		}
+	}
+	if *decisionTime {
+		_, _ = fmt.Fprintf(out, "Decision time: %v\n", decisions)
+	}
+	if *energy {
+		outputEnergy(out, Energy(r, *idlePower))
+	}
+	if failing.Scheduler != nil {
+		outputDropout(out, MeasureDropout(failing, processes))
+	}
+	if p, ok := s.(PowerDown); ok {
+		outputPowerDown(out, PowerDownSavings(p, processes, *idlePower))
+	}
+	if objective != 0 {
+		o, err := CheckOptimal(r, processes, objective)
+		if err != nil {
+			log.Fatal(err)
+		}
+		outputOptimality(out, o)
+	}
+	if *quantumTies {
+		outputQuantumTies(out, QuantumTies(r), r.Resolution, cfg.Boundary)
+	}
+	if *gradePath != "" {
+		pass, divergence, err := GradeAgainstReference(*gradePath, s, processes)
+		if err != nil {
+			log.Fatal(err)
+		}
+		outputGrade(out, pass, divergence)
+	}
+	if *coach {
+		review, err := Coach(r)
+		if err != nil {
+			log.Fatal(err)
+		}
+		outputCoaching(out, review)
+	}
+	if *backfill && *cores > 1 {
+		outputBackfill(out, CompareBackfill(processes, *cores))
+	}
+	if *sharing {
+		outputProcessorSharing(out, ProcessorSharing(processes), r)
+	}
+	if *load {
+		outputLoad(out, MeasureLoad(r))
+	}
+	if *convoy {
+		outputConvoy(out, s.Name(), MeasureConvoy(r))
+	}
+	if *tieRuns > 0 {
+		outputTies(out, *tieRuns, CheckTies(s, processes, *tieRuns, *tieSeed))
+	}
+	if *trace {
+		outputEvents(out, r)
+	}
+	if *traceJSON {
+		if err := encodeEvents(out, Events(r)); err != nil {
+			log.Fatal(err)
+		}
 	}
 }
 
-//go:generate stringer -type=Scheduler
-type Scheduler uint
+//go:generate stringer -type=Algorithm
+type Algorithm uint
 
 const (
-	fcfs Scheduler = iota + 1
+	fcfs Algorithm = iota + 1
 	sjf
 	sjfp
 	rr
 )
 
-func parseCLI(flagSet *flag.FlagSet, args []string) (cmd Scheduler, data io.Reader, err error) {
+// title is the heading printed above the output of a.
+func (a Algorithm) title() string {
+	switch a {
+	case sjf:
+		return "Shortest-job-first"
+	case sjfp:
+		return "Priority"
+	case rr:
+		return "Round-robin"
+	default:
+		return "First-come, first-serve"
+	}
+}
+
+// schedulerFor returns the Scheduler implementing a with cfg.
+func schedulerFor(a Algorithm, cfg SchedulerConfig) Scheduler {
+	var s Scheduler
+	switch a {
+	case sjf:
+		s = SJF{Boundary: cfg.Boundary}
+	case sjfp:
+		s = SJFPriority{cfg}
+	case rr:
+		s = RR{Quantum: cfg.Quantum, Resolution: cfg.Resolution, SwitchPenalty: cfg.SwitchPenalty, Boundary: cfg.Boundary}
+	default:
+		if cfg.Cores > 1 {
+			s = MultiCore{Cores: cfg.Cores, Dispatch: cfg.Dispatch, Backfill: cfg.Backfill}
+		} else {
+			s = FCFS{Boundary: cfg.Boundary}
+		}
+	}
+	if cfg.Checkpoints {
+		s = checkpointing{s}
+	}
+	return s
+}
+
+// schedulerNamed returns the Scheduler named algo, and the title to print above its output.
+// The command-line algorithms fcfs, sjf, sjfp and rr are configured by cfg; any other name is a
+// ParseScheduler spec, which carries its own parameters.
+func schedulerNamed(algo string, cfg SchedulerConfig) (Scheduler, string, error) {
+	for a := fcfs; a <= rr; a++ {
+		if algo == a.String() {
+			return schedulerFor(a, cfg), a.title(), nil
+		}
+	}
+	s, err := ParseScheduler(algo)
+	if err != nil {
+		return nil, "", err
+	}
+	return s, s.Name(), nil
+}
+
+// RunFile loads the workload in the file at path, validates it, schedules it with the
+// algorithm named algo under cfg, and writes the GANTT chart and table of timing to out. algo
+// is fcfs, sjf, sjfp or rr, configured by cfg as on the command line, or otherwise a
+// ParseScheduler spec such as hrrn:preemptive.
+func RunFile(path, algo string, cfg SchedulerConfig, out io.Writer) error {
+	s, title, err := schedulerNamed(algo, cfg)
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("%w: error opening scheduling file", err)
+	}
+	defer func() { _ = f.Close() }()
+	processes, err := loadProcesses(f, cfg.PriorityNames)
+	if err != nil {
+		return fmt.Errorf("%w: loading %s", err, path)
+	}
+	if err := cfg.ValidatePriorities(processes); err != nil {
+		return err
+	}
+	resolution := cfg.Resolution
+	if rr, ok := s.(RR); ok {
+		resolution = rr.Resolution
+	}
+	if err := ValidateResolution(processes, resolution); err != nil {
+		return err
+	}
+
+	r := s.Schedule(processes)
+	if algo == sjfp.String() {
+		outputSJFPriority(out, title, r, RenderOptions{})
+	} else {
+		outputResult(out, title, r, RenderOptions{})
+		outputAlgorithmSummary(out, s, r)
+	}
+	return nil
+}
+
+// outputSections schedules each of workloads with s, in name order, and writes each result under
+// its name and title. Each workload must pass cfg.ValidatePriorities.
+func outputSections(w io.Writer, s Scheduler, title string, workloads map[string][]Process, cfg SchedulerConfig, opts RenderOptions) error {
+	names := make([]string, 0, len(workloads))
+	for name := range workloads {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for n, name := range names {
+		if err := cfg.ValidatePriorities(workloads[name]); err != nil {
+			return fmt.Errorf("%w: section %q", err, name)
+		}
+		if err := ValidateResolution(workloads[name], cfg.Resolution); err != nil {
+			return fmt.Errorf("%w: section %q", err, name)
+		}
+		if n > 0 {
+			_, _ = fmt.Fprintln(w)
+		}
+		_, _ = fmt.Fprintf(w, "=== %s ===\n", name)
+		r := s.Schedule(workloads[name])
+		outputResult(w, title, r, opts)
+		if !opts.NoTable {
+			outputAlgorithmSummary(w, s, r)
+		}
+	}
+	return nil
+}
+
+// ErrSchedulerPanic is wrapped by the error SafeSchedule returns for a recovered panic.
+var ErrSchedulerPanic = errors.New("scheduler panicked")
+
+// SafeSchedule schedules processes with s and writes the GANTT chart and table of timing to w
+// under title, like FCFSSchedule and the others, but recovers from a panic in s or the
+// rendering and returns it as an ErrSchedulerPanic error that describes the input. Nothing is
+// written to w unless the whole output was rendered.
+func SafeSchedule(s Scheduler, w io.Writer, title string, processes []Process) (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			// A watchdog's ErrNoProgress stays inspectable with errors.Is.
+			if e, ok := v.(error); ok {
+				err = fmt.Errorf("%w: %s: %w (%s)", ErrSchedulerPanic, s.Name(), e, summarizeInput(processes))
+				return
+			}
+			err = fmt.Errorf("%w: %s: %v (%s)", ErrSchedulerPanic, s.Name(), v, summarizeInput(processes))
+		}
+	}()
+	var b bytes.Buffer
+	r := s.Schedule(processes)
+	outputResult(&b, title, r, RenderOptions{})
+	outputAlgorithmSummary(&b, s, r)
+	if _, err := b.WriteTo(w); err != nil {
+		return fmt.Errorf("%w: writing schedule", err)
+	}
+	return nil
+}
+
+// summarizeInput describes processes by their count and the range of their arrivals and bursts.
+func summarizeInput(processes []Process) string {
+	if len(processes) == 0 {
+		return "no processes"
+	}
+	first, last := processes[0].ArrivalTime, processes[0].ArrivalTime
+	shortest, longest := processes[0].BurstDuration, processes[0].BurstDuration
+	for _, p := range processes[1:] {
+		first, last = min(first, p.ArrivalTime), max(last, p.ArrivalTime)
+		shortest, longest = min(shortest, p.BurstDuration), max(longest, p.BurstDuration)
+	}
+	return fmt.Sprintf("%d processes, arrivals %d to %d, bursts %d to %d", len(processes), first, last, shortest, longest)
+}
+
+func parseCLI(flagSet *flag.FlagSet, args []string) (cmd Algorithm, data io.Reader, err error) {
 	fcfsFlag := flagSet.Bool(fcfs.String(), false, "First-come, first-serve scheduling")
 	sjfFlag := flagSet.Bool(sjf.String(), false, "Shortest-job-first scheduling")
 	sjfpFlag := flagSet.Bool(sjfp.String(), false, "Shortest-job-first with priority scheduling")
@@ -79,12 +492,23 @@ func parseCLI(flagSet *flag.FlagSet, args []string) (cmd Scheduler, data io.Read
 		count++
 		cmd = rr
 	}
+	if f := flagSet.Lookup("compare"); count == 0 && f != nil && f.Value.String() == "true" {
+		// Comparing runs every scheduler.
+		count = 1
+	}
+	if f := flagSet.Lookup("repl"); count <= 1 && f != nil && f.Value.String() == "true" {
+		// The REPL reads its workload and algorithm as commands, starting from fcfs.
+		if count == 0 {
+			cmd = fcfs
+		}
+		return cmd, nil, nil
+	}
 	switch count {
 	case 0:
 		return 0, nil, fmt.Errorf("one scheduler flag must be set")
 	case 1:
 		// validate that data file is piped in.
-		if data, err := readData(os.Args[:2]); err != nil {
+		if data, err := readData(flagSet.Args()); err != nil {
 			return 0, nil, err
 		} else {
 			return cmd, data, nil
@@ -98,10 +522,10 @@ func readData(args []string) (io.Reader, error) {
 	fi, _ := os.Stdin.Stat()
 	if (fi.Mode() & os.ModeCharDevice) == 0 {
 		return os.Stdin, nil
-	} else if len(args) == 1 {
+	} else if len(args) == 0 {
 		return nil, fmt.Errorf("scheduler data must be passed in or file given as last argument")
 	}
-	r, err := os.Open(os.Args[2])
+	r, err := os.Open(args[len(args)-1])
 	if err != nil {
 		return nil, fmt.Errorf("%w: error opening data file", err)
 	}
@@ -109,6 +533,18 @@ func readData(args []string) (io.Reader, error) {
 	return r, nil
 }
 
+func writeGanttPNG(name string, gantt []TimeSlice) error {
+	f, err := os.Create(name)
+	if err != nil {
+		return fmt.Errorf("%w: error creating PNG file", err)
+	}
+	if err := outputGanttPNG(f, gantt, 800, 80, nil); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("%w: error drawing PNG", err)
+	}
+	return f.Close()
+}
+
 func openProcessingFile(args ...string) (*os.File, func(), error) {
 	if len(args) != 2 {
 		return nil, nil, fmt.Errorf("%w: must give a scheduling file to process", ErrInvalidArgs)
@@ -130,91 +566,1043 @@ func openProcessingFile(args ...string) (*os.File, func(), error) {
 //region Output helpers
 
 func outputTitle(w io.Writer, title string) {
-	_, _ = fmt.Fprintln(w, strings.Repeat("-", len(title)*2))
-	_, _ = fmt.Fprintln(w, strings.Repeat(" ", len(title)/2), title)
-	_, _ = fmt.Fprintln(w, strings.Repeat("-", len(title)*2))
+	width := runewidth.StringWidth(title)
+	_, _ = fmt.Fprintln(w, strings.Repeat("-", width*2))
+	_, _ = fmt.Fprintln(w, strings.Repeat(" ", width/2), title)
+	_, _ = fmt.Fprintln(w, strings.Repeat("-", width*2))
+}
+
+// formatTime formats t, measured in ticks of 1/resolution time units, in whole time units.
+func formatTime(t, resolution int64) string {
+	if resolution <= 1 {
+		return fmt.Sprint(t)
+	}
+	return strconv.FormatFloat(float64(t)/float64(resolution), 'f', -1, 64)
+}
+
+// ganttLabel is the label of slice in the GANTT chart. Zero-width checkpoint markers are shown
+// as a tick before their PID.
+func ganttLabel(slice TimeSlice) string {
+	if slice.Start == slice.Stop {
+		return "^" + slice.PID
+	}
+	return slice.PID
+}
+
+func outputGantt(w io.Writer, gantt []TimeSlice, resolution int64) {
+	_, _ = fmt.Fprintln(w, "Gantt schedule")
+	bars, ticks := ganttLines(gantt, len(gantt), resolution)
+	_, _ = fmt.Fprintf(w, "%s\n%s\n\n", bars, ticks)
 }
 
-func outputGantt(w io.Writer, gantt []TimeSlice) {
+// outputCoreGantts writes a GANTT chart for each of r's cores.
+func outputCoreGantts(w io.Writer, r ScheduleResult) {
 	_, _ = fmt.Fprintln(w, "Gantt schedule")
+	cores, _ := CoreUtilization(r)
+	for _, u := range cores {
+		var gantt []TimeSlice
+		for _, slice := range r.Gantt {
+			if slice.Core == u.Core {
+				gantt = append(gantt, slice)
+			}
+		}
+		_, _ = fmt.Fprintf(w, "Core %d\n", u.Core)
+		if len(gantt) == 0 {
+			_, _ = fmt.Fprint(w, "(idle)\n\n")
+			continue
+		}
+		bars, ticks := ganttLines(gantt, len(gantt), r.Resolution)
+		_, _ = fmt.Fprintf(w, "%s\n%s\n\n", bars, ticks)
+	}
+}
+
+// animateGantt draws the GANTT chart one slice at a time, waiting delay between frames. Each
+// frame moves the cursor back up to the bar line and redraws both lines over the last frame.
+// Writers that are not a terminal get the static chart. clock, or the real clock if nil, does
+// the waiting.
+func animateGantt(w io.Writer, gantt []TimeSlice, resolution int64, delay time.Duration, clock Clock) {
+	if !isTerminal(w) || len(gantt) == 0 {
+		outputGantt(w, gantt, resolution)
+		return
+	}
+	drawFrames(w, gantt, resolution, delay, clockOrWall(clock))
+}
+
+// drawFrames writes the frames of animateGantt to w, whether or not it is a terminal.
+func drawFrames(w io.Writer, gantt []TimeSlice, resolution int64, delay time.Duration, clock Clock) {
+	_, _ = fmt.Fprintln(w, "Gantt schedule")
+	for shown := 1; shown <= len(gantt); shown++ {
+		if shown > 1 {
+			clock.Sleep(delay)
+			_, _ = fmt.Fprint(w, "\x1b[1A\r")
+		}
+		bars, ticks := ganttLines(gantt, shown, resolution)
+		_, _ = fmt.Fprintf(w, "%s\n\r%s", bars, ticks)
+	}
+	_, _ = fmt.Fprint(w, "\n\n")
+}
 
+// Tee returns a writer that copies every write to each of writers, like tee(1), so that any of
+// the rendering functions can print to the terminal and a file at once:
+//
+//	FCFSSchedule(Tee(os.Stdout, f), "First-come, first-serve", processes)
+//
+// A write stops at the first writer that fails. Tee output is never a terminal, so an animated
+// chart is drawn static.
+func Tee(writers ...io.Writer) io.Writer {
+	return io.MultiWriter(writers...)
+}
+
+// isTerminal reports whether w is a character device such as a terminal.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+// ganttLines renders the first shown slices of gantt as the chart's bar and time lines. Cells
+// are sized for the whole of gantt, so a partial chart lines up with the complete one. Labels
+// are measured in terminal columns, so wide characters such as CJK take two. An empty gantt
+// renders as empty lines.
+func ganttLines(gantt []TimeSlice, shown int, resolution int64) (bars, ticks string) {
+	if len(gantt) == 0 {
+		return "", ""
+	}
 	buffer := 2
 	widest := 0
 	for _, slice := range gantt {
-		if len(slice.PID) > widest {
-			widest = len(slice.PID)
+		if n := runewidth.StringWidth(ganttLabel(slice)); n > widest {
+			widest = n
+		}
+		// Each cell also has to fit the time printed under its left edge, plus a space. An idle
+		// cell starts at the Stop of the slice before it.
+		for _, t := range []int64{slice.Start, slice.Stop} {
+			if n := len(formatTime(t, resolution)) - 2*buffer; n > widest {
+				widest = n
+			}
 		}
 	}
 
-	_, _ = fmt.Fprintf(w, "|")
+	var b strings.Builder
+	b.WriteString("|")
 	last := gantt[0].Start
-	for _, slice := range gantt {
-		_, _ = fmt.Fprint(w, strings.Repeat(" ", buffer))
+	for _, slice := range gantt[:shown] {
 		if slice.Start > last {
-			_, _ = fmt.Fprint(w, strings.Repeat(" ", widest))
-		} else {
-			_, _ = fmt.Fprint(w, slice.PID)
+			// idle gap before this slice.
+			b.WriteString(strings.Repeat(" ", buffer+widest+buffer) + "|")
 		}
-		_, _ = fmt.Fprint(w, strings.Repeat(" ", buffer)+"|")
+		b.WriteString(strings.Repeat(" ", buffer))
+		b.WriteString(runewidth.FillRight(ganttLabel(slice), widest))
+		b.WriteString(strings.Repeat(" ", buffer) + "|")
 		last = slice.Stop
 	}
-	_, _ = fmt.Fprintf(w, "\n")
+	bars = b.String()
+
+	b.Reset()
 	width := buffer + widest + buffer + 1
-	for i := range gantt {
-		t := fmt.Sprint(gantt[i].Start)
-		_, _ = fmt.Fprint(w, t)
-		_, _ = fmt.Fprint(w, strings.Repeat(" ", width-len(t)))
-		if i == len(gantt)-1 {
-			_, _ = fmt.Fprint(w, gantt[i].Stop)
+	tick := func(t int64) {
+		s := formatTime(t, resolution)
+		b.WriteString(s)
+		b.WriteString(strings.Repeat(" ", width-len(s)))
+	}
+	last = gantt[0].Start
+	for i := range gantt[:shown] {
+		if gantt[i].Start > last {
+			tick(last)
+		}
+		tick(gantt[i].Start)
+		last = gantt[i].Stop
+		if i == shown-1 {
+			b.WriteString(formatTime(gantt[i].Stop, resolution))
 		}
 	}
+	ticks = b.String()
 
-	_, _ = fmt.Fprintf(w, "\n\n")
+	return bars, ticks
 }
 
-func outputSchedule(w io.Writer, rows [][]string, wait, turnaround, throughput float64) {
+// outputTimeAxis writes a time ruler for r's GANTT chart under it, with a tick every interval
+// time units, and with arrivals the arrival markers of r's processes; see timeAxis and
+// arrivalMarkers.
+func outputTimeAxis(w io.Writer, r ScheduleResult, interval int64, arrivals bool) {
+	_, _ = fmt.Fprintln(w, "Time axis")
+	scale := newAxisScale(r.Gantt, r.Resolution, interval)
+	axis, labels := timeAxis(r.Gantt, scale)
+	_, _ = fmt.Fprintf(w, "%s\n%s\n", axis, labels)
+	if arrivals {
+		processes := make([]Process, len(r.Rows))
+		for i, row := range r.Rows {
+			processes[i] = row.Process
+		}
+		for _, line := range arrivalMarkers(processes, scale) {
+			_, _ = fmt.Fprintln(w, line)
+		}
+	}
+	_, _ = fmt.Fprintln(w)
+}
+
+// axisScale maps times in ticks of 1/resolution time units to the columns of a time axis that
+// starts at 0 and has n intervals of step ticks, each width columns wide.
+type axisScale struct {
+	resolution int64
+	step       int64
+	n          int64
+	width      int
+}
+
+// newAxisScale returns the scale of a time axis for gantt with a tick every interval time
+// units. An interval of 0 is chosen for about ten ticks over the schedule by tickInterval.
+func newAxisScale(gantt []TimeSlice, resolution, interval int64) axisScale {
+	var end int64
+	for _, slice := range gantt {
+		end = max(end, slice.Stop)
+	}
+	if interval <= 0 {
+		interval = tickInterval(end, max(1, resolution))
+	}
+	s := axisScale{resolution: resolution, step: interval * max(1, resolution)}
+	s.n = max(1, (end+s.step-1)/s.step)
+	// Each interval is wide enough for the widest time and a space after it.
+	s.width = int(max(4, int64(len(formatTime(s.n*s.step, resolution))+1)))
+	return s
+}
+
+// column is the column of the axis that time t, in ticks, falls on.
+func (s axisScale) column(t int64) int {
+	return int(math.Round(float64(t) / float64(s.step) * float64(s.width)))
+}
+
+// timeAxis renders a time ruler for gantt drawn to scale: a line with a + at every tick and a |
+// at each slice boundary, which takes the place of a + it falls on, and a line of the times
+// under the + marks.
+func timeAxis(gantt []TimeSlice, scale axisScale) (axis, labels string) {
+	line := []byte(strings.Repeat("-", int(scale.n)*scale.width+1))
+	times := []byte(strings.Repeat(" ", len(line)+scale.width))
+	for k := 0; k <= int(scale.n); k++ {
+		line[k*scale.width] = '+'
+		copy(times[k*scale.width:], formatTime(int64(k)*scale.step, scale.resolution))
+	}
+	for _, slice := range gantt {
+		line[scale.column(slice.Start)] = '|'
+		line[scale.column(slice.Stop)] = '|'
+	}
+	return string(line), strings.TrimRight(string(times), " ")
+}
+
+// arrivalMarkers renders a ^ under a time axis drawn to scale at the arrival of each of
+// processes, followed by lines of their IDs, each starting under its ^. An ID goes on the first
+// line where it leaves a space after the ID before it, so processes arriving close together
+// are stacked, in arrival order.
+func arrivalMarkers(processes []Process, scale axisScale) []string {
+	var carets []byte
+	var lines [][]byte
+	for _, i := range sortedIndices(processes, func(a, b Process) bool {
+		return a.ArrivalTime < b.ArrivalTime
+	}) {
+		c := scale.column(processes[i].ArrivalTime * max(1, scale.resolution))
+		for len(carets) <= c {
+			carets = append(carets, ' ')
+		}
+		carets[c] = '^'
+		n := 0
+		for n < len(lines) && len(lines[n]) >= c {
+			n++
+		}
+		if n == len(lines) {
+			lines = append(lines, nil)
+		}
+		for len(lines[n]) < c {
+			lines[n] = append(lines[n], ' ')
+		}
+		lines[n] = append(lines[n], processes[i].ProcessID+" "...)
+	}
+	if carets == nil {
+		return nil
+	}
+	markers := []string{string(carets)}
+	for _, line := range lines {
+		markers = append(markers, strings.TrimRight(string(line), " "))
+	}
+	return markers
+}
+
+// tickInterval is the interval, in time units, between the ticks of a time axis for a schedule
+// ending at end ticks of 1/scale units: the smallest 1, 2 or 5 times a power of ten that gives
+// at most ten intervals.
+func tickInterval(end, scale int64) int64 {
+	target := float64(end) / float64(scale) / 10
+	for pow := int64(1); ; pow *= 10 {
+		for _, m := range []int64{1, 2, 5} {
+			if float64(m*pow) >= target {
+				return m * pow
+			}
+		}
+	}
+}
+
+// Rounding selects how average wait and turnaround, which are averages of whole times, are
+// printed. The zero value prints them to two decimal places.
+type Rounding int
+
+const (
+	RoundNone Rounding = iota
+	RoundFloor
+	RoundCeil
+	RoundNearest
+)
+
+var roundingNames = map[Rounding]string{RoundNone: "none", RoundFloor: "floor", RoundCeil: "ceil", RoundNearest: "nearest"}
+
+func (m Rounding) String() string { return roundingNames[m] }
+
+// Set parses one of none, floor, ceil or nearest, so a Rounding can be a flag.Value.
+func (m *Rounding) Set(s string) error {
+	for mode, name := range roundingNames {
+		if name == s {
+			*m = mode
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: unknown rounding %q", ErrInvalidArgs, s)
+}
+
+// format formats an average under m. Nearest rounds halves away from zero.
+func (m Rounding) format(v float64) string {
+	switch m {
+	case RoundFloor:
+		return fmt.Sprint(int64(math.Floor(v)))
+	case RoundCeil:
+		return fmt.Sprint(int64(math.Ceil(v)))
+	case RoundNearest:
+		return fmt.Sprint(int64(math.Round(v)))
+	default:
+		return fmt.Sprintf("%.2f", v)
+	}
+}
+
+// RenderOptions controls how results are printed.
+type RenderOptions struct {
+	Rounding Rounding
+	// ByCompletion lists the schedule table in completion order instead of input order.
+	ByCompletion bool
+	// Animate, if positive, draws the GANTT chart a slice at a time with this delay between
+	// slices when printing to a terminal.
+	Animate time.Duration
+	// Clock paces the animation; nil is the real clock.
+	Clock Clock
+	// TimeAxis draws a time ruler under the GANTT chart with a tick every TickInterval time
+	// units, or an interval chosen from the makespan if TickInterval is 0.
+	TimeAxis     bool
+	TickInterval int64
+	// ArrivalMarkers, with TimeAxis, marks the arrival of each process under the axis.
+	ArrivalMarkers bool
+	// Percentiles, if set, are the percentiles of the wait and turnaround to print after the
+	// averages, such as 50, 95 and 99.
+	Percentiles []float64
+	// SlowdownAlert, if positive, adds each process's slowdown to the schedule table and flags
+	// the processes whose slowdown is above it as badly delayed.
+	SlowdownAlert float64
+	// NoGantt leaves out the GANTT chart and its time axis, and NoTable the schedule table and
+	// the summary lines after it, so a caller can ask for just one.
+	NoGantt bool
+	NoTable bool
+}
+
+func outputSchedule(w io.Writer, rows [][]string, r ScheduleResult, opts RenderOptions) {
 	_, _ = fmt.Fprintln(w, "Schedule table")
 	table := tablewriter.NewWriter(w)
-	table.SetHeader([]string{"ID", "Priority", "Burst", "Arrival", "Wait", "Turnaround", "Exit"})
+	header := []string{"ID", "Priority", "Burst", "Arrival", "Wait", "Turnaround", "Exit"}
+	if opts.SlowdownAlert > 0 {
+		header = append(header, "Slowdown", "Alert")
+	}
+	table.SetHeader(header)
+	// Columns are as wide as their widest cell; wrapping long IDs would break rows over lines.
+	table.SetAutoWrapText(false)
 	table.AppendBulk(rows)
 	table.Render()
 	_, _ = fmt.Fprintln(w)
-	_, _ = fmt.Fprintf(w, "Average wait: %.2f\n", wait)
-	_, _ = fmt.Fprintf(w, "Average turnaround: %.2f\n", turnaround)
-	_, _ = fmt.Fprintf(w, "Throughput: %.2f\n", throughput)
+	_, _ = fmt.Fprintf(w, "Average wait: %s\n", opts.Rounding.format(r.AvgWait))
+	_, _ = fmt.Fprintf(w, "Average turnaround: %s\n", opts.Rounding.format(r.AvgTurnaround))
+	if len(opts.Percentiles) > 0 {
+		waits, turnarounds := TimingPercentiles(r, opts.Percentiles)
+		outputPercentiles(w, "Wait percentiles", opts.Percentiles, waits)
+		outputPercentiles(w, "Turnaround percentiles", opts.Percentiles, turnarounds)
+	}
+	_, _ = fmt.Fprintf(w, "Throughput: %.2f\n", r.Throughput)
+	for _, row := range r.Rows {
+		if row.Process.Value != 0 {
+			_, _ = fmt.Fprintf(w, "Value throughput: %.2f\n", r.ValueThroughput())
+			break
+		}
+	}
+	_, _ = fmt.Fprintf(w, "Average queue length: %.2f (Little's Law: %.2f)\n", r.AvgQueueLength, r.Throughput*r.AvgWait)
+	_, _ = fmt.Fprintf(w, "Idle time: %s (average gap: %.2f)\n", formatTime(r.IdleTime, r.Resolution), r.AvgIdleGap)
+	_, _ = fmt.Fprintf(w, "Stretch: %.2f (makespan / total burst)\n", r.Stretch())
+	_, _ = fmt.Fprintf(w, "Completion order: %s\n", strings.Join(CompletionOrder(r), ", "))
+	for _, row := range r.Rows {
+		if row.Process.Deadline > 0 {
+			outputDeadlineMisses(w, "Deadline misses", MissedDeadlines(r))
+			break
+		}
+	}
+	if hasResponseDeadlines(r.Rows) {
+		outputDeadlineMisses(w, "Response deadline misses", MissedResponseDeadlines(r))
+	}
+	if groups, shares := CPUShares(r); len(groups) > 1 || len(groups) == 1 && groups[0].Name != "" {
+		outputShares(w, "CPU share by group", groups)
+		_, _ = fmt.Fprintf(w, "Group fairness: %.2f (Jain's index, 1 is equal)\n", JainIndex(groups))
+		outputShares(w, "CPU share by process", shares)
+	}
+	if blocksOnIO(r) {
+		outputIdleBreakdown(w, r)
+	}
+	outputRealizedBursts(w, r)
+	if r.Cores > 1 {
+		outputCores(w, r)
+	}
+	outputCriticalPath(w, r)
+}
+
+// outputDeadlineMisses writes, after label, the processes that missed their deadlines, if any.
+func outputDeadlineMisses(w io.Writer, label string, late []string) {
+	if len(late) == 0 {
+		_, _ = fmt.Fprintf(w, "%s: none\n", label)
+		return
+	}
+	_, _ = fmt.Fprintf(w, "%s: %d (%s)\n", label, len(late), strings.Join(late, ", "))
+}
+
+// outputQuantumTies writes each of ties, timed in ticks of 1/resolution units, and which side of
+// it boundary queued first.
+func outputQuantumTies(w io.Writer, ties []QuantumTie, resolution int64, boundary Boundary) {
+	if len(ties) == 0 {
+		_, _ = fmt.Fprintln(w, "Quantum boundary ties: none")
+		return
+	}
+	first := "arrivals"
+	if boundary == DecisionFirst {
+		first = "preempted process"
+	}
+	_, _ = fmt.Fprintf(w, "Quantum boundary ties (%s queued first)\n", first)
+	for _, tie := range ties {
+		_, _ = fmt.Fprintf(w, "  At %s: %s arrived as %s was preempted\n",
+			formatTime(tie.Time, resolution), strings.Join(tie.Arrived, ", "), tie.Preempted)
+	}
+}
+
+// hasResponseDeadlines reports whether any of rows' processes has a ResponseDeadline.
+func hasResponseDeadlines(rows []ProcessResult) bool {
+	for _, row := range rows {
+		if row.Process.ResponseDeadline > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// outputConvoy writes how much longer c's short processes waited under algo than under SJF.
+func outputConvoy(w io.Writer, algo string, c Convoy) {
+	if len(c.Short) == 0 {
+		_, _ = fmt.Fprintln(w, "Convoy effect: none, no burst is below the average")
+		return
+	}
+	_, _ = fmt.Fprintf(w, "Convoy effect: short processes (%s) wait %.2f under %s and %.2f under sjf, %.2f more\n",
+		strings.Join(c.Short, ", "), c.Wait, algo, c.SJFWait, c.Magnitude())
+}
+
+// outputLoad writes l's offered load and whether the cores can keep up with it.
+func outputLoad(w io.Writer, l Load) {
+	if l.ServiceRate == 0 {
+		_, _ = fmt.Fprintln(w, "Offered load: none, no process has work")
+		return
+	}
+	if l.Span == 0 {
+		_, _ = fmt.Fprintf(w, "Offered load: every process arrives at once, so ρ is unbounded; throughput %.2f of at most %.2f\n",
+			l.Throughput, l.ServiceRate*float64(l.Cores))
+		return
+	}
+	verdict := "under-loaded"
+	if !l.Stable() {
+		verdict = "overloaded, ρ ≥ 1 is unstable and waits grow with every arrival"
+	}
+	_, _ = fmt.Fprintf(w, "Offered load: ρ = %.2f (λ %.2f arrivals, μ %.2f completions per time unit on %d core(s)); throughput %.2f of at most %.2f, %s\n",
+		l.Rho, l.ArrivalRate, l.ServiceRate, l.Cores, l.Throughput, l.ServiceRate*float64(l.Cores), verdict)
+}
+
+// outputTies writes whether the metrics held over runs shuffled tie orders, and if not, each
+// that changed.
+func outputTies(w io.Writer, runs int, variations []TieVariation) {
+	if len(variations) == 0 {
+		_, _ = fmt.Fprintf(w, "Ties: metrics unchanged over %d shuffled tie orders\n", runs)
+		return
+	}
+	for _, v := range variations {
+		_, _ = fmt.Fprintf(w, "Ties: %s %.2f in input order but %.2f in order %v\n", v.Metric, v.Want, v.Got, v.Order)
+	}
+}
+
+// outputCriticalPath writes the critical path of r's processes next to its makespan, if any of
+// them depend on another.
+func outputCriticalPath(w io.Writer, r ScheduleResult) {
+	processes := make([]Process, len(r.Rows))
+	dependent := false
+	for i, row := range r.Rows {
+		processes[i] = row.Process
+		dependent = dependent || len(row.Process.DependsOn) > 0
+	}
+	if !dependent {
+		return
+	}
+	length, path := CriticalPath(processes)
+	_, _ = fmt.Fprintf(w, "Critical path: %s (%s), makespan %s\n",
+		formatTime(length*max(1, r.Resolution), r.Resolution), strings.Join(path, " -> "), formatTime(r.Makespan, r.Resolution))
+}
+
+// outputPercentiles writes the value at each of percentiles on one line.
+func outputPercentiles(w io.Writer, title string, percentiles, values []float64) {
+	_, _ = fmt.Fprintf(w, "%s:", title)
+	for i, p := range percentiles {
+		_, _ = fmt.Fprintf(w, " p%s %s", strconv.FormatFloat(p, 'f', -1, 64), strconv.FormatFloat(values[i], 'f', -1, 64))
+	}
+	_, _ = fmt.Fprintln(w)
+}
+
+// outputShares writes shares on one line as percentages.
+func outputShares(w io.Writer, title string, shares []Share) {
+	_, _ = fmt.Fprintf(w, "%s:", title)
+	for _, share := range shares {
+		_, _ = fmt.Fprintf(w, " %s %.2f%%", share.Name, 100*share.Share)
+	}
+	_, _ = fmt.Fprintln(w)
+}
+
+// outputCores writes the busy and idle time of each of r's cores, then of all of them.
+func outputCores(w io.Writer, r ScheduleResult) {
+	cores, total := CoreUtilization(r)
+	for _, u := range cores {
+		_, _ = fmt.Fprintf(w, "Core %d: busy %s, idle %s (%.2f%% utilization)\n",
+			u.Core, formatTime(u.Busy, r.Resolution), formatTime(u.Idle, r.Resolution), 100*u.Utilization())
+	}
+	_, _ = fmt.Fprintf(w, "All cores: busy %s, idle %s (%.2f%% utilization)\n",
+		formatTime(total.Busy, r.Resolution), formatTime(total.Idle, r.Resolution), 100*total.Utilization())
+}
+
+// outputBackfill writes how many processes c's backfilling started early and the makespan it saved.
+func outputBackfill(w io.Writer, c BackfillComparison) {
+	_, _ = fmt.Fprintf(w, "Backfilled: %d of %d processes, makespan %s -> %s (%.2f%% shorter)\n",
+		c.Backfilled.Backfilled, len(c.Backfilled.Rows),
+		formatTime(c.Strict.Makespan, c.Strict.Resolution), formatTime(c.Backfilled.Makespan, c.Backfilled.Resolution),
+		100*c.Improvement())
+}
+
+// outputDispatchComparison writes comparisons as a table of each policy's makespan and balance.
+func outputDispatchComparison(w io.Writer, comparisons []DispatchComparison, opts RenderOptions) {
+	_, _ = fmt.Fprintln(w, "Dispatch policies")
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Policy", "Wait", "Makespan", "Utilization", "Busy spread"})
+	for _, c := range comparisons {
+		_, total := CoreUtilization(c.Result)
+		table.Append([]string{
+			c.Policy.String(),
+			opts.Rounding.format(c.Result.AvgWait),
+			formatTime(c.Result.Makespan, c.Result.Resolution),
+			fmt.Sprintf("%.2f%%", 100*total.Utilization()),
+			formatTime(c.Spread, c.Result.Resolution),
+		})
+	}
+	table.Render()
+}
+
+// outputComparison writes comparisons as a table, with each average wait relative to FCFS.
+func outputComparison(w io.Writer, comparisons []Comparison, opts RenderOptions) {
+	_, _ = fmt.Fprintln(w, "Comparison")
+	table := tablewriter.NewWriter(w)
+	header := []string{"Algorithm", "Wait", "Turnaround", "Throughput", "Makespan", "Stretch", "Switches", "Preemptions", "Wait vs FCFS"}
+	// The response deadline misses are only worth a column if some process has one.
+	responses := len(comparisons) > 0 && hasResponseDeadlines(comparisons[0].Result.Rows)
+	if responses {
+		header = append(header, "Response misses")
+	}
+	table.SetHeader(header)
+	for _, c := range comparisons {
+		row := []string{
+			c.Algo,
+			opts.Rounding.format(c.Result.AvgWait),
+			opts.Rounding.format(c.Result.AvgTurnaround),
+			fmt.Sprintf("%.2f", c.Result.Throughput),
+			formatTime(c.Result.Makespan, c.Result.Resolution),
+			fmt.Sprintf("%.2f", c.Result.Stretch()),
+			fmt.Sprint(c.Result.ContextSwitches),
+			fmt.Sprint(c.Preemptions),
+			fmt.Sprintf("%+.2f%%", c.VsFCFS),
+		}
+		if responses {
+			row = append(row, fmt.Sprint(len(MissedResponseDeadlines(c.Result))))
+		}
+		table.Append(row)
+	}
+	table.Render()
+}
+
+// outputProcessComparison writes one row per process with its wait and turnaround under each of
+// comparisons, which must be of the same workload, in adjacent column pairs, for comparing the
+// algorithms process by process rather than on average.
+func outputProcessComparison(w io.Writer, comparisons []Comparison) {
+	_, _ = fmt.Fprintln(w, "Per-process comparison")
+	table := tablewriter.NewWriter(w)
+	header := []string{"ID"}
+	for _, c := range comparisons {
+		header = append(header, c.Algo+" wait", c.Algo+" turnaround")
+	}
+	table.SetHeader(header)
+	table.SetAutoWrapText(false)
+	if len(comparisons) > 0 {
+		for i, row := range comparisons[0].Result.Rows {
+			cells := []string{row.Process.ProcessID}
+			for _, c := range comparisons {
+				r := c.Result
+				cells = append(cells, formatTime(r.Rows[i].Waiting, r.Resolution), formatTime(r.Rows[i].Turnaround, r.Resolution))
+			}
+			table.Append(cells)
+		}
+	}
+	table.Render()
+}
+
+// outputCompact writes r as a single line of key=value pairs for scripting.
+func outputCompact(w io.Writer, algo string, r ScheduleResult, opts RenderOptions) {
+	_, _ = fmt.Fprintf(w, "algo=%s avgWait=%s avgTurn=%s throughput=%.2f makespan=%s stretch=%.2f\n",
+		algo, opts.Rounding.format(r.AvgWait), opts.Rounding.format(r.AvgTurnaround), r.Throughput,
+		formatTime(r.Makespan, r.Resolution), r.Stretch())
 }
 
 //endregion
 
 //region Loading processes.
 
-var ErrInvalidArgs = errors.New("invalid args")
+var (
+	ErrInvalidArgs    = errors.New("invalid args")
+	ErrInvalidProcess = errors.New("invalid process")
+	ErrOverflow       = errors.New("time overflows int64")
+)
+
+// DefaultPriorityNames are the names a workload file may give as a priority unless configured
+// otherwise, numbered so that high is the most urgent under the default lower-first order.
+var DefaultPriorityNames = map[string]int64{"high": 1, "normal": 2, "low": 3}
+
+// loadProcesses reads processes from r, which holds a header row followed by one process per
+// row. Columns are separated by commas, tabs, or runs of spaces; the delimiter is detected from
+// the header row. A priority is a number or one of names, in any case; nil names are
+// DefaultPriorityNames.
+func loadProcesses(r io.Reader, names map[string]int64) ([]Process, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: reading processes", err)
+	}
+
+	return loadDelimitedProcesses(bytes.NewReader(b), detectDelimiter(b), names)
+}
 
-func loadProcesses(r io.Reader) ([]Process, error) {
-	rows, err := csv.NewReader(r).ReadAll()
+// loadSections reads several workloads from r, each starting at a marker line such as
+// "--- light load ---" and laid out as for loadProcesses, header row and all, and returns them
+// by name. Errors give the line of r they were found on. Anything but blank lines and comments
+// before the first marker, an empty name or a name given twice is an error.
+func loadSections(r io.Reader, names map[string]int64) (map[string][]Process, error) {
+	b, err := io.ReadAll(r)
 	if err != nil {
-		return nil, fmt.Errorf("%w: reading CSV", err)
+		return nil, fmt.Errorf("%w: reading processes", err)
+	}
+	var (
+		sections = make(map[string][]Process)
+		name     string
+		body     strings.Builder // the section so far, blank up to its marker to keep line numbers.
+		started  bool
+	)
+	flush := func() error {
+		if !started {
+			return nil
+		}
+		processes, err := loadProcesses(strings.NewReader(body.String()), names)
+		if err != nil {
+			return fmt.Errorf("%w: section %q", err, name)
+		}
+		sections[name] = processes
+		return nil
+	}
+	for n, line := range strings.Split(string(b), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if len(trimmed) < 6 || !strings.HasPrefix(trimmed, "---") || !strings.HasSuffix(trimmed, "---") {
+			if !started && trimmed != "" && !strings.HasPrefix(trimmed, "#") {
+				return nil, fmt.Errorf("%w: line %d: processes before the first --- name --- marker", ErrInvalidProcess, n+1)
+			}
+			body.WriteString(line)
+			body.WriteByte('\n')
+			continue
+		}
+		if err := flush(); err != nil {
+			return nil, err
+		}
+		name = strings.TrimSpace(trimmed[3 : len(trimmed)-3])
+		if name == "" {
+			return nil, fmt.Errorf("%w: line %d: section has no name", ErrInvalidProcess, n+1)
+		}
+		if _, dup := sections[name]; dup {
+			return nil, fmt.Errorf("%w: line %d: section %q given twice", ErrInvalidProcess, n+1, name)
+		}
+		started = true
+		body.Reset()
+		body.WriteString(strings.Repeat("\n", n+1))
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	if len(sections) == 0 {
+		return nil, fmt.Errorf("%w: no --- name --- sections", ErrInvalidProcess)
+	}
+	return sections, nil
+}
+
+// loadDelimitedProcesses reads processes from r with columns separated by delim. A delim of
+// ' ' splits columns on any run of whitespace. Blank lines and lines starting with # are
+// skipped, and errors give the line of r they were found on.
+func loadDelimitedProcesses(r io.Reader, delim rune, names map[string]int64) ([]Process, error) {
+	var (
+		rows  [][]string
+		lines []int // the line of r each row starts on.
+	)
+	if delim == ' ' {
+		scanner := bufio.NewScanner(r)
+		for line := 1; scanner.Scan(); line++ {
+			if fields := strings.Fields(scanner.Text()); len(fields) > 0 && !strings.HasPrefix(fields[0], "#") {
+				rows = append(rows, fields)
+				lines = append(lines, line)
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("%w: reading processes", err)
+		}
+	} else {
+		reader := csv.NewReader(r)
+		reader.Comma = delim
+		reader.Comment = '#'
+		reader.FieldsPerRecord = -1
+		for {
+			row, err := reader.Read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, fmt.Errorf("%w: reading CSV", err)
+			}
+			if isBlankOrComment(row) {
+				continue
+			}
+			line, _ := reader.FieldPos(0)
+			rows = append(rows, row)
+			lines = append(lines, line)
+		}
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("%w: missing header row", ErrInvalidProcess)
+	}
+	if len(rows) == 1 {
+		return nil, fmt.Errorf("%w: no processes after the header row", ErrInvalidProcess)
+	}
+
+	return parseProcesses(rows[1:], lines[1:], names) // skip header row
+}
+
+// isBlankOrComment reports whether a CSV row is only whitespace, or is a comment indented past
+// where the CSV reader recognizes one.
+func isBlankOrComment(row []string) bool {
+	first := strings.TrimSpace(row[0])
+	return len(row) == 1 && first == "" || strings.HasPrefix(first, "#")
+}
+
+// detectDelimiter returns the column delimiter used by the first line of b that is neither
+// blank nor a comment.
+func detectDelimiter(b []byte) rune {
+	var header []byte
+	for rest := b; len(rest) > 0; {
+		header, rest, _ = bytes.Cut(rest, []byte("\n"))
+		if trimmed := bytes.TrimSpace(header); len(trimmed) > 0 && trimmed[0] != '#' {
+			break
+		}
+	}
+	switch {
+	case bytes.ContainsRune(header, ','):
+		return ','
+	case bytes.ContainsRune(header, '\t'):
+		return '\t'
+	default:
+		return ' '
+	}
+}
+
+// parseProcesses validates rows of ProcessID, burst duration, arrival time, and an optional
+// priority, a number or one of names, whatever delimiter they were read with. lines are the
+// input lines of the rows, for errors.
+func parseProcesses(rows [][]string, lines []int, names map[string]int64) ([]Process, error) {
+	if names == nil {
+		names = DefaultPriorityNames
 	}
-	rows = rows[1:] // skip header row
 	processes := make([]Process, len(rows))
-	for i := range rows {
-		processes[i].ProcessID = rows[i][0]
-		processes[i].BurstDuration = mustStrToInt(rows[i][1])
-		processes[i].ArrivalTime = mustStrToInt(rows[i][2])
-		if len(rows[i]) == 4 {
-			processes[i].Priority = mustStrToInt(rows[i][3])
+	for i, row := range rows {
+		if len(row) != 3 && len(row) != 4 {
+			return nil, fmt.Errorf("%w: line %d: want 3 or 4 columns, got %d", ErrInvalidProcess, lines[i], len(row))
+		}
+		var err error
+		processes[i].ProcessID = row[0]
+		if processes[i].BurstDuration, err = strToInt(row[1]); err != nil {
+			return nil, fmt.Errorf("%w: line %d: burst duration", err, lines[i])
+		}
+		if processes[i].ArrivalTime, err = strToInt(row[2]); err != nil {
+			return nil, fmt.Errorf("%w: line %d: arrival time", err, lines[i])
+		}
+		if len(row) == 4 {
+			var ok bool
+			if processes[i].Priority, ok = priorityValue(row[3], names); !ok {
+				return nil, fmt.Errorf("%w: line %d: priority %q is not a number or one of %s",
+					ErrInvalidProcess, lines[i], row[3], strings.Join(sortedNames(names), ", "))
+			}
 		}
 	}
 
+	if err := ValidateProcesses(processes); err != nil {
+		return nil, err
+	}
 	return processes, nil
 }
 
-func mustStrToInt(s string) int64 {
-	i, err := strconv.ParseInt(s, 10, 64)
+// priorityValue parses s as a number or, ignoring case, one of names.
+func priorityValue(s string, names map[string]int64) (int64, bool) {
+	if priority, err := strToInt(s); err == nil {
+		return priority, true
+	}
+	priority, ok := names[strings.ToLower(strings.TrimSpace(s))]
+	return priority, ok
+}
+
+// sortedNames returns the keys of names in order.
+func sortedNames(names map[string]int64) []string {
+	keys := make([]string, 0, len(names))
+	for name := range names {
+		keys = append(keys, name)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ValidateProcesses reports whether processes can be scheduled. Process IDs must be unique, since
+// the results and dependencies refer to processes by ID; arrivals and bursts must not be
+// negative; and the latest possible completion, the last arrival plus every burst and every
+// I/O wait, must fit in an int64 so that no scheduler's clock can overflow.
+func ValidateProcesses(processes []Process) error {
+	var lastArrival, work int64
+	ids := make(map[string]bool, len(processes))
+	for _, p := range processes {
+		if ids[p.ProcessID] {
+			return fmt.Errorf("%w: %s: duplicate process ID", ErrInvalidProcess, p.ProcessID)
+		}
+		ids[p.ProcessID] = true
+		if p.ArrivalTime < 0 || p.BurstDuration < 0 {
+			return fmt.Errorf("%w: %s: negative arrival or burst", ErrInvalidProcess, p.ProcessID)
+		}
+		if err := validateIO(p); err != nil {
+			return err
+		}
+		if p.BurstDist != nil {
+			if err := p.BurstDist.validate(); err != nil {
+				return fmt.Errorf("%w: %s: %v", ErrInvalidProcess, p.ProcessID, err)
+			}
+		}
+		lastArrival = max(lastArrival, p.ArrivalTime)
+		if work > math.MaxInt64-p.BurstDuration {
+			return fmt.Errorf("%w: total burst duration at %s", ErrOverflow, p.ProcessID)
+		}
+		work += p.BurstDuration
+		for _, b := range p.IO {
+			if work > math.MaxInt64-b.Duration {
+				return fmt.Errorf("%w: total burst and I/O duration at %s", ErrOverflow, p.ProcessID)
+			}
+			work += b.Duration
+		}
+	}
+	if lastArrival > math.MaxInt64-work {
+		return fmt.Errorf("%w: makespan may exceed %d", ErrOverflow, int64(math.MaxInt64))
+	}
+
+	return validateDependencies(processes)
+}
+
+// ValidateResolution reports whether processes can be simulated in ticks of 1/resolution time
+// units, as RR does with a Resolution: the latest possible completion, as ValidateProcesses
+// bounds it, must still fit in an int64 once scaled to ticks. A resolution of 1 or less is
+// whole time units, which ValidateProcesses already covers.
+func ValidateResolution(processes []Process, resolution int64) error {
+	if resolution <= 1 {
+		return nil
+	}
+	if limit := int64(math.MaxInt64) / resolution; latestCompletion(processes) > limit {
+		return fmt.Errorf("%w: makespan may exceed %d time units at a resolution of %d", ErrOverflow, limit, resolution)
+	}
+	return nil
+}
+
+// latestCompletion returns the latest time any of processes can complete, the last arrival
+// plus every burst and I/O wait, or math.MaxInt64 if that overflows.
+func latestCompletion(processes []Process) int64 {
+	var lastArrival, work int64
+	for _, p := range processes {
+		lastArrival = max(lastArrival, p.ArrivalTime)
+		work = addCapped(work, p.BurstDuration)
+		for _, b := range p.IO {
+			work = addCapped(work, b.Duration)
+		}
+	}
+	return addCapped(lastArrival, work)
+}
+
+// addCapped returns a+b, or math.MaxInt64 if a positive b would overflow it.
+func addCapped(a, b int64) int64 {
+	if b > 0 && a > math.MaxInt64-b {
+		return math.MaxInt64
+	}
+	return a + b
+}
+
+// PriorityRange is an inclusive range of valid Priority values.
+type PriorityRange struct {
+	Min, Max int64
+}
+
+// parsePercentiles parses a comma-separated list of percentiles, each in (0, 100].
+func parsePercentiles(s string) ([]float64, error) {
+	var percentiles []float64
+	for _, field := range strings.Split(s, ",") {
+		p, err := strconv.ParseFloat(strings.TrimSpace(field), 64)
+		if err != nil || p <= 0 || p > 100 {
+			return nil, fmt.Errorf("%w: percentile %q is not in (0, 100]", ErrInvalidArgs, field)
+		}
+		percentiles = append(percentiles, p)
+	}
+	return percentiles, nil
+}
+
+// ParsePriorityNames parses a comma-separated list of name=priority pairs, such as
+// urgent=0,high=1, into a map for SchedulerConfig.PriorityNames. Names are matched in any case.
+func ParsePriorityNames(s string) (map[string]int64, error) {
+	names := make(map[string]int64)
+	for _, pair := range strings.Split(s, ",") {
+		name, value, ok := strings.Cut(pair, "=")
+		name = strings.ToLower(strings.TrimSpace(name))
+		priority, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		if !ok || name == "" || err != nil {
+			return nil, fmt.Errorf("%w: priority name %q is not name=number", ErrInvalidArgs, pair)
+		}
+		names[name] = priority
+	}
+	return names, nil
+}
+
+// ParsePriorityRange parses a range written min:max, such as 1:10.
+func ParsePriorityRange(s string) (PriorityRange, error) {
+	from, to, ok := strings.Cut(s, ":")
+	if !ok {
+		return PriorityRange{}, fmt.Errorf("%w: priority range %q is not min:max", ErrInvalidArgs, s)
+	}
+	lo, errLo := strconv.ParseInt(strings.TrimSpace(from), 10, 64)
+	hi, errHi := strconv.ParseInt(strings.TrimSpace(to), 10, 64)
+	if errLo != nil || errHi != nil {
+		return PriorityRange{}, fmt.Errorf("%w: priority range %q is not min:max", ErrInvalidArgs, s)
+	}
+	if lo > hi {
+		return PriorityRange{}, fmt.Errorf("%w: priority range %q is empty", ErrInvalidArgs, s)
+	}
+	return PriorityRange{Min: lo, Max: hi}, nil
+}
+
+func (r PriorityRange) String() string { return fmt.Sprintf("%d:%d", r.Min, r.Max) }
+
+// Clamp returns priority moved into r, for callers that would rather correct a priority than
+// reject it.
+func (r PriorityRange) Clamp(priority int64) int64 {
+	return min(r.Max, max(r.Min, priority))
+}
+
+// ValidatePriorities reports the first of processes whose Priority is outside c.Priorities, if
+// it is set.
+func (c SchedulerConfig) ValidatePriorities(processes []Process) error {
+	if c.Priorities == nil {
+		return nil
+	}
+	for _, p := range processes {
+		if p.Priority < c.Priorities.Min || p.Priority > c.Priorities.Max {
+			return fmt.Errorf("%w: %s: priority %d outside %v", ErrInvalidProcess, p.ProcessID, p.Priority, *c.Priorities)
+		}
+	}
+	return nil
+}
+
+// validateDependencies checks that every DependsOn names a process in processes and that no
+// process depends on itself, directly or through others.
+func validateDependencies(processes []Process) error {
+	byID := make(map[string]Process, len(processes))
+	for _, p := range processes {
+		byID[p.ProcessID] = p
+	}
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[string]int, len(processes))
+	var path []string
+	var visit func(p Process) error
+	visit = func(p Process) error {
+		switch state[p.ProcessID] {
+		case visiting:
+			for n, pid := range path {
+				if pid == p.ProcessID {
+					cycle := append(append([]string(nil), path[n:]...), pid)
+					return fmt.Errorf("%w: dependency cycle %s", ErrInvalidProcess, strings.Join(cycle, " -> "))
+				}
+			}
+		case visited:
+			return nil
+		}
+		state[p.ProcessID] = visiting
+		path = append(path, p.ProcessID)
+		for _, pid := range p.DependsOn {
+			dep, ok := byID[pid]
+			if !ok {
+				return fmt.Errorf("%w: %s: depends on unknown process %s", ErrInvalidProcess, p.ProcessID, pid)
+			}
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[p.ProcessID] = visited
+		return nil
+	}
+	for _, p := range processes {
+		if err := visit(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func strToInt(s string) (int64, error) {
+	i, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64)
 	if err != nil {
-		log.Fatalln(err)
+		return 0, fmt.Errorf("%w: %v", ErrInvalidProcess, err)
 	}
-	return i
+	return i, nil
 }
 
 //endregion
@@ -4,13 +4,16 @@ import (
 	"bytes"
 	"errors"
 	"io"
+	"math"
 	"os"
 	"path"
 	"strings"
 	"testing"
 	"testing/iotest"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/mattn/go-runewidth"
 )
 
 func TestFCFSSchedule(t *testing.T) {
@@ -65,6 +68,431 @@ func TestFCFSSchedule(t *testing.T) {
 	}
 }
 
+func TestTee(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: "P0", ArrivalTime: 0, BurstDuration: 5, Priority: 2},
+		{ProcessID: "P1", ArrivalTime: 3, BurstDuration: 9, Priority: 1},
+		{ProcessID: "P2", ArrivalTime: 6, BurstDuration: 6, Priority: 3},
+	}
+	var stdout, file bytes.Buffer
+	FCFSSchedule(Tee(&stdout, &file), "First-come, first-serve", processes)
+	want := loadFixture(t, "fcfs_fixture.txt")
+	if diff := cmp.Diff(stdout.String(), want); diff != "" {
+		t.Errorf("first writer: %s", diff)
+	}
+	if diff := cmp.Diff(file.String(), want); diff != "" {
+		t.Errorf("second writer: %s", diff)
+	}
+}
+
+func TestRunFile(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	workload := path.Join(dir, "fcfs.csv")
+	if err := os.WriteFile(workload, []byte("ID,Burst,Arrival,Priority\nP0,5,0,2\nP1,9,3,1\nP2,6,6,3\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	invalid := path.Join(dir, "invalid.csv")
+	if err := os.WriteFile(invalid, []byte("ID,Burst,Arrival\nP0,-5,0\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	empty := path.Join(dir, "empty.csv")
+	if err := os.WriteFile(empty, []byte("ID,Burst,Arrival\n# nothing yet\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var w bytes.Buffer
+	if err := RunFile(workload, "fcfs", SchedulerConfig{}, &w); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(w.String(), loadFixture(t, "fcfs_fixture.txt")); diff != "" {
+		t.Errorf(diff)
+	}
+	w.Reset()
+	if err := RunFile(workload, "hrrn:preemptive", SchedulerConfig{}, &w); err != nil || !strings.Contains(w.String(), "   hrrn\n") {
+		t.Errorf("spec: error %v, output %q", err, w.String())
+	}
+	w.Reset()
+	if err := RunFile(workload, "ua", SchedulerConfig{}, &w); err != nil || !strings.HasSuffix(w.String(), "Total utility: 3.00\n") {
+		t.Errorf("ua: error %v, output %q", err, w.String())
+	}
+	w.Reset()
+	if err := RunFile(workload, "decay", SchedulerConfig{}, &w); err != nil || !strings.HasSuffix(w.String(), "Final effective priority: P0 4.00 P1 6.50 P2 6.50\n") {
+		t.Errorf("decay: error %v, output %q", err, w.String())
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		algo    string
+		cfg     SchedulerConfig
+		wantErr error
+	}{
+		{name: "unknown algorithm", path: workload, algo: "lottery", wantErr: ErrInvalidArgs},
+		{name: "missing file", path: path.Join(dir, "missing.csv"), algo: "fcfs", wantErr: os.ErrNotExist},
+		{name: "invalid workload", path: invalid, algo: "fcfs", wantErr: ErrInvalidProcess},
+		{name: "empty workload", path: empty, algo: "fcfs", wantErr: ErrInvalidProcess},
+		{name: "resolution overflows", path: workload, algo: "rr", cfg: SchedulerConfig{Quantum: 2, Resolution: math.MaxInt64 / 10}, wantErr: ErrOverflow},
+		{name: "spec resolution overflows", path: workload, algo: "rr:quantum=2:resolution=922337203685477580", wantErr: ErrOverflow},
+		{name: "priority range", path: workload, algo: "sjfp", cfg: SchedulerConfig{Priorities: &PriorityRange{Min: 1, Max: 2}}, wantErr: ErrInvalidProcess},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if err := RunFile(tt.path, tt.algo, tt.cfg, io.Discard); !errors.Is(err, tt.wantErr) {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// panicking is a Scheduler that panics, standing in for a scheduler bug.
+type panicking struct{ FCFS }
+
+func (panicking) Schedule(processes []Process) ScheduleResult {
+	_ = processes[len(processes)] // out of range.
+	return ScheduleResult{}
+}
+
+func TestSafeSchedule(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: "P0", ArrivalTime: 4, BurstDuration: 5},
+		{ProcessID: "P1", ArrivalTime: 0, BurstDuration: 9},
+		{ProcessID: "P2", ArrivalTime: 9, BurstDuration: 1},
+	}
+	var w bytes.Buffer
+	err := SafeSchedule(panicking{}, &w, "Broken", processes)
+	if !errors.Is(err, ErrSchedulerPanic) {
+		t.Fatalf("error = %v, want %v", err, ErrSchedulerPanic)
+	}
+	want := "scheduler panicked: fcfs: runtime error: index out of range [3] with length 3 " +
+		"(3 processes, arrivals 0 to 9, bursts 1 to 9)"
+	if err.Error() != want {
+		t.Errorf("error = %q, want %q", err, want)
+	}
+	if w.Len() != 0 {
+		t.Errorf("wrote %q before panicking", w.String())
+	}
+
+	if err := SafeSchedule(panicking{}, &w, "Broken", nil); err == nil || !strings.HasSuffix(err.Error(), "(no processes)") {
+		t.Errorf("empty input: error = %v", err)
+	}
+
+	// A scheduler that works is rendered as usual.
+	if err := SafeSchedule(FCFS{}, &w, "First-come, first-serve", []Process{
+		{ProcessID: "P0", ArrivalTime: 0, BurstDuration: 5, Priority: 2},
+		{ProcessID: "P1", ArrivalTime: 3, BurstDuration: 9, Priority: 1},
+		{ProcessID: "P2", ArrivalTime: 6, BurstDuration: 6, Priority: 3},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(w.String(), loadFixture(t, "fcfs_fixture.txt")); diff != "" {
+		t.Errorf(diff)
+	}
+}
+
+func TestRRSchedule(t *testing.T) {
+	t.Parallel()
+	type args struct {
+		processes []Process
+		quantum   int64
+	}
+	tests := []struct {
+		name string
+		args args
+	}{
+		{
+			name: "fixture",
+			args: args{
+				processes: []Process{
+					{ProcessID: "P0", ArrivalTime: 0, BurstDuration: 5, Priority: 2},
+					{ProcessID: "P1", ArrivalTime: 3, BurstDuration: 9, Priority: 1},
+					{ProcessID: "P2", ArrivalTime: 6, BurstDuration: 6, Priority: 3},
+				},
+				quantum: 10,
+			},
+		},
+		{
+			name: "idle gap",
+			args: args{
+				processes: []Process{
+					{ProcessID: "P0", ArrivalTime: 0, BurstDuration: 2},
+					{ProcessID: "P1", ArrivalTime: 1, BurstDuration: 3},
+					{ProcessID: "P2", ArrivalTime: 9, BurstDuration: 4},
+				},
+				quantum: 1 << 40,
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var rr, fcfs bytes.Buffer
+			RRSchedule(&rr, "Equivalence", tt.args.processes, tt.args.quantum)
+			FCFSSchedule(&fcfs, "Equivalence", tt.args.processes)
+			if diff := cmp.Diff(rr.String(), fcfs.String()); diff != "" {
+				t.Errorf(diff)
+			}
+		})
+	}
+}
+
+func Test_outputCompact(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: "P0", ArrivalTime: 0, BurstDuration: 5, Priority: 2},
+		{ProcessID: "P1", ArrivalTime: 3, BurstDuration: 9, Priority: 1},
+		{ProcessID: "P2", ArrivalTime: 6, BurstDuration: 6, Priority: 3},
+	}
+	tests := []struct {
+		rounding Rounding
+		want     string
+	}{
+		{rounding: RoundNone, want: "algo=fcfs avgWait=3.33 avgTurn=10.00 throughput=0.15 makespan=20 stretch=1.00\n"},
+		{rounding: RoundFloor, want: "algo=fcfs avgWait=3 avgTurn=10 throughput=0.15 makespan=20 stretch=1.00\n"},
+		{rounding: RoundCeil, want: "algo=fcfs avgWait=4 avgTurn=10 throughput=0.15 makespan=20 stretch=1.00\n"},
+		{rounding: RoundNearest, want: "algo=fcfs avgWait=3 avgTurn=10 throughput=0.15 makespan=20 stretch=1.00\n"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.rounding.String(), func(t *testing.T) {
+			t.Parallel()
+			var w bytes.Buffer
+			outputCompact(&w, "fcfs", FCFS{}.Schedule(processes), RenderOptions{Rounding: tt.rounding})
+			if diff := cmp.Diff(w.String(), tt.want); diff != "" {
+				t.Errorf(diff)
+			}
+		})
+	}
+}
+
+func Test_outputResultAlignment(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		processes []Process
+	}{
+		{
+			name: "long ID",
+			processes: []Process{
+				{ProcessID: "P0", ArrivalTime: 0, BurstDuration: 5},
+				{ProcessID: "a process with a very long and spaced out identifier", ArrivalTime: 0, BurstDuration: 3},
+				{ProcessID: "P2", ArrivalTime: 4_000_000_000_000, BurstDuration: 1_000_000_000_000},
+			},
+		},
+		{
+			name: "huge times",
+			processes: []Process{
+				{ProcessID: "P0", ArrivalTime: 0, BurstDuration: 5},
+				{ProcessID: "P1", ArrivalTime: 4_000_000_000_000, BurstDuration: 1_000_000_000_000},
+			},
+		},
+		{
+			name: "wide characters",
+			processes: []Process{
+				{ProcessID: "进程一", ArrivalTime: 0, BurstDuration: 5},
+				{ProcessID: "P1", ArrivalTime: 1, BurstDuration: 3},
+				{ProcessID: "🚀🚀", ArrivalTime: 2, BurstDuration: 4},
+				{ProcessID: "café", ArrivalTime: 20, BurstDuration: 1},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var w bytes.Buffer
+			outputResult(&w, "Alignment", FCFS{}.Schedule(tt.processes), RenderOptions{})
+			lines := strings.Split(w.String(), "\n")
+
+			// Every time in the chart starts under a cell border, counting columns as a terminal
+			// displays them.
+			var bars []rune
+			var ticks string
+			for i, line := range lines {
+				if line == "Gantt schedule" {
+					for _, r := range lines[i+1] {
+						bars = append(bars, r)
+						if runewidth.RuneWidth(r) == 2 {
+							bars = append(bars, ' ')
+						}
+					}
+					ticks = lines[i+2]
+				}
+			}
+			for col := range ticks {
+				if ticks[col] != ' ' && (col == 0 || ticks[col-1] == ' ') && (col >= len(bars) || bars[col] != '|') {
+					t.Errorf("time at column %d is not under a border:\n%s\n%s", col, string(bars), ticks)
+				}
+			}
+
+			// Every table line, borders included, has the same width, and no row wraps.
+			var table []string
+			for _, line := range lines {
+				if strings.HasPrefix(line, "+") || strings.Count(line, "|") == 8 {
+					table = append(table, line)
+				}
+			}
+			if len(table) != 4+len(tt.processes) {
+				t.Fatalf("got %d table lines, want %d:\n%s", len(table), 4+len(tt.processes), strings.Join(table, "\n"))
+			}
+			for _, line := range table {
+				if runewidth.StringWidth(line) != runewidth.StringWidth(table[0]) {
+					t.Errorf("misaligned table line:\n%s\n%s", table[0], line)
+				}
+			}
+		})
+	}
+}
+
+func Test_animateGantt(t *testing.T) {
+	t.Parallel()
+	gantt := []TimeSlice{
+		{PID: "P0", Start: 0, Stop: 3},
+		{PID: "P1", Start: 5, Stop: 12},
+		{PID: "P2", Start: 12, Stop: 14},
+	}
+
+	// Output that is not a terminal gets the static chart, without waiting.
+	var static, animated bytes.Buffer
+	outputGantt(&static, gantt, 1)
+	animateGantt(&animated, gantt, 1, time.Hour, nil)
+	if diff := cmp.Diff(animated.String(), static.String()); diff != "" {
+		t.Errorf(diff)
+	}
+
+	// Every frame lines up with the complete chart.
+	fullBars, fullTicks := ganttLines(gantt, len(gantt), 1)
+	for shown := 1; shown < len(gantt); shown++ {
+		bars, ticks := ganttLines(gantt, shown, 1)
+		if !strings.HasPrefix(fullBars, bars) || !strings.HasPrefix(fullTicks, ticks) {
+			t.Errorf("frame %d does not line up:\n%s\n%s\n%s\n%s", shown, bars, ticks, fullBars, fullTicks)
+		}
+	}
+	if bars, ticks := ganttLines(nil, 0, 1); bars != "" || ticks != "" {
+		t.Errorf("empty chart = %q, %q", bars, ticks)
+	}
+}
+
+func Test_timeAxis(t *testing.T) {
+	t.Parallel()
+	gantt := []TimeSlice{
+		{PID: "P0", Start: 0, Stop: 3},
+		{PID: "P1", Start: 3, Stop: 7},
+		{PID: "P2", Start: 9, Stop: 11},
+	}
+	tests := []struct {
+		name       string
+		gantt      []TimeSlice
+		resolution int64
+		interval   int64
+		wantAxis   string
+		wantLabels string
+	}{
+		{
+			name:       "auto interval",
+			gantt:      gantt,
+			wantAxis:   "|---+-|-+---+-|-+-|-+-|-+",
+			wantLabels: "0   2   4   6   8   10  12",
+		},
+		{
+			name:       "given interval",
+			gantt:      gantt,
+			interval:   5,
+			wantAxis:   "|-|-+-||+|--+",
+			wantLabels: "0   5   10  15",
+		},
+		{
+			name:       "ticks",
+			gantt:      []TimeSlice{{PID: "P0", Start: 0, Stop: 15}},
+			resolution: 10,
+			wantAxis:   "|---+-|-+",
+			wantLabels: "0   1   2",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			axis, labels := timeAxis(tt.gantt, newAxisScale(tt.gantt, tt.resolution, tt.interval))
+			if diff := cmp.Diff(tt.wantAxis+"\n"+tt.wantLabels, axis+"\n"+labels); diff != "" {
+				t.Errorf(diff)
+			}
+		})
+	}
+}
+
+func Test_arrivalMarkers(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: "P0", ArrivalTime: 0, BurstDuration: 3},
+		{ProcessID: "P2", ArrivalTime: 9, BurstDuration: 2},
+		{ProcessID: "P1", ArrivalTime: 1, BurstDuration: 4},
+		{ProcessID: "Long", ArrivalTime: 1, BurstDuration: 1},
+		{ProcessID: "P4", ArrivalTime: 2, BurstDuration: 1},
+	}
+	r := FCFS{}.Schedule(processes)
+	// The chart ends at 11, so a tick every 2 units is 4 columns, and each unit 2.
+	want := []string{
+		"^ ^ ^             ^",
+		"P0  P4            P2",
+		"  P1",
+		"  Long",
+	}
+	if diff := cmp.Diff(want, arrivalMarkers(processes, newAxisScale(r.Gantt, r.Resolution, 0))); diff != "" {
+		t.Errorf(diff)
+	}
+
+	var w bytes.Buffer
+	outputResult(&w, "Arrivals", r, RenderOptions{TimeAxis: true, ArrivalMarkers: true})
+	if axis := "0   2   4   6   8   10  12\n" + strings.Join(want, "\n") + "\n\n"; !strings.Contains(w.String(), axis) {
+		t.Errorf("output is missing the arrival markers:\n%s", w.String())
+	}
+}
+
+// fakeClock is a Clock that advances by tick on every reading and records the sleeps asked of it.
+type fakeClock struct {
+	now    time.Time
+	tick   time.Duration
+	sleeps []time.Duration
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.now = c.now.Add(c.tick)
+	return c.now
+}
+
+func (c *fakeClock) Sleep(d time.Duration) {
+	c.sleeps = append(c.sleeps, d)
+	c.now = c.now.Add(d)
+}
+
+func Test_drawFrames(t *testing.T) {
+	t.Parallel()
+	gantt := []TimeSlice{
+		{PID: "P0", Start: 0, Stop: 3},
+		{PID: "P1", Start: 3, Stop: 5},
+		{PID: "P2", Start: 5, Stop: 9},
+	}
+	var clock fakeClock
+	var w bytes.Buffer
+	drawFrames(&w, gantt, 1, time.Second, &clock)
+	if diff := cmp.Diff(clock.sleeps, []time.Duration{time.Second, time.Second}); diff != "" {
+		t.Errorf(diff)
+	}
+	want := "Gantt schedule\n" +
+		"|  P0  |\n\r0      3\x1b[1A\r" +
+		"|  P0  |  P1  |\n\r0      3      5\x1b[1A\r" +
+		"|  P0  |  P1  |  P2  |\n\r0      3      5      9\n\n"
+	if diff := cmp.Diff(w.String(), want); diff != "" {
+		t.Errorf(diff)
+	}
+}
+
 func Test_loadProcesses(t *testing.T) {
 	t.Parallel()
 	type args struct {
@@ -112,12 +540,84 @@ P2,6,3,3`),
 				},
 			},
 		},
+		{
+			name: "tab delimited",
+			args: args{
+				r: strings.NewReader("ProcessID\tBurst Duration\tArrival Time\tPriority\nP0\t5\t0\t2\nP1\t9\t3\t1\n"),
+			},
+			want: []Process{
+				{ProcessID: "P0", ArrivalTime: 0, BurstDuration: 5, Priority: 2},
+				{ProcessID: "P1", ArrivalTime: 3, BurstDuration: 9, Priority: 1},
+			},
+		},
+		{
+			name: "whitespace delimited",
+			args: args{
+				r: strings.NewReader("ProcessID  Burst  Arrival\nP0   5  0\n\nP1 \t 9   3\n"),
+			},
+			want: []Process{
+				{ProcessID: "P0", ArrivalTime: 0, BurstDuration: 5},
+				{ProcessID: "P1", ArrivalTime: 3, BurstDuration: 9},
+			},
+		},
+		{
+			name: "comments and blank lines",
+			args: args{
+				r: strings.NewReader("# generated workload\n\nProcessID,Burst,Arrival\n# first batch\nP0,5,0\n   \n  # indented\nP1,9,3\n"),
+			},
+			want: []Process{
+				{ProcessID: "P0", ArrivalTime: 0, BurstDuration: 5},
+				{ProcessID: "P1", ArrivalTime: 3, BurstDuration: 9},
+			},
+		},
+		{
+			name: "whitespace delimited comments",
+			args: args{
+				r: strings.NewReader("# id burst arrival\nProcessID Burst Arrival\n  # none yet\nP0 5 0\n"),
+			},
+			want: []Process{
+				{ProcessID: "P0", ArrivalTime: 0, BurstDuration: 5},
+			},
+		},
+		{
+			name: "priority names",
+			args: args{
+				r: strings.NewReader("ProcessID,Burst,Arrival,Priority\nP0,5,0,High\nP1,9,3,low\nP2,6,3, normal\nP3,1,4,7\n"),
+			},
+			want: []Process{
+				{ProcessID: "P0", ArrivalTime: 0, BurstDuration: 5, Priority: 1},
+				{ProcessID: "P1", ArrivalTime: 3, BurstDuration: 9, Priority: 3},
+				{ProcessID: "P2", ArrivalTime: 3, BurstDuration: 6, Priority: 2},
+				{ProcessID: "P3", ArrivalTime: 4, BurstDuration: 1, Priority: 7},
+			},
+		},
+		{
+			name: "bad burst",
+			args: args{
+				r: strings.NewReader("ProcessID Burst Arrival\nP0 five 0\n"),
+			},
+			wantErr: ErrInvalidProcess,
+		},
+		{
+			name: "missing column",
+			args: args{
+				r: strings.NewReader("ProcessID,Burst,Arrival\nP0,5\n"),
+			},
+			wantErr: ErrInvalidProcess,
+		},
+		{
+			name: "duplicate ID",
+			args: args{
+				r: strings.NewReader("ProcessID,Burst,Arrival\nP0,5,0\nP1,9,3\nP0,6,3\n"),
+			},
+			wantErr: ErrInvalidProcess,
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
-			got, err := loadProcesses(tt.args.r)
+			got, err := loadProcesses(tt.args.r, nil)
 			if diff := cmp.Diff(got, tt.want); diff != "" {
 				t.Errorf(diff)
 			}
@@ -128,6 +628,359 @@ P2,6,3,3`),
 	}
 }
 
+func Test_loadProcessesLineNumbers(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{
+			name:    "CSV",
+			input:   "# header\nProcessID,Burst,Arrival\n\nP0,5,0\n# P1 is broken\nP1,5\n",
+			wantErr: "invalid process: line 6: want 3 or 4 columns, got 2",
+		},
+		{
+			name:    "whitespace delimited",
+			input:   "\n# header\nProcessID Burst Arrival\nP0 5 0\n\nP1 5 0 1 2\n",
+			wantErr: "invalid process: line 6: want 3 or 4 columns, got 5",
+		},
+		{
+			name:    "unknown priority name",
+			input:   "ProcessID,Burst,Arrival,Priority\nP0,5,0,high\nP1,5,0,urgent\n",
+			wantErr: `invalid process: line 3: priority "urgent" is not a number or one of high, low, normal`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := loadProcesses(strings.NewReader(tt.input), nil)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if diff := cmp.Diff(err.Error(), tt.wantErr); diff != "" {
+				t.Errorf(diff)
+			}
+		})
+	}
+}
+
+func Test_loadSections(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		input   string
+		want    map[string][]Process
+		wantErr string
+	}{
+		{
+			name:  "two sections",
+			input: "# studies\n--- light ---\nID,Burst,Arrival\nP0,2,0\n\n---heavy load---\nID Burst Arrival Priority\nP0 5 0 high\nP1 1 1 2\n",
+			want: map[string][]Process{
+				"light":      {{ProcessID: "P0", BurstDuration: 2}},
+				"heavy load": {{ProcessID: "P0", BurstDuration: 5, Priority: 1}, {ProcessID: "P1", BurstDuration: 1, ArrivalTime: 1, Priority: 2}},
+			},
+		},
+		{
+			name:    "duplicate",
+			input:   "--- a ---\nID,Burst,Arrival\nP0,1,0\n--- a ---\nID,Burst,Arrival\nP1,1,0\n",
+			wantErr: `invalid process: line 4: section "a" given twice`,
+		},
+		{
+			name:    "processes before a marker",
+			input:   "ID,Burst,Arrival\n--- a ---\n",
+			wantErr: "invalid process: line 1: processes before the first --- name --- marker",
+		},
+		{
+			name:    "no name",
+			input:   "------\nID,Burst,Arrival\n",
+			wantErr: "invalid process: line 1: section has no name",
+		},
+		{
+			name:    "bad process",
+			input:   "--- a ---\nID,Burst,Arrival\nP0,1,0\n--- b ---\nID,Burst,Arrival\nP1,1\n",
+			wantErr: `invalid process: line 6: want 3 or 4 columns, got 2: section "b"`,
+		},
+		{
+			name:    "no sections",
+			input:   "# nothing here\n",
+			wantErr: "invalid process: no --- name --- sections",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := loadSections(strings.NewReader(tt.input), nil)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("got error %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf(diff)
+			}
+		})
+	}
+}
+
+func Test_outputSections(t *testing.T) {
+	t.Parallel()
+	workloads := map[string][]Process{
+		"b": {{ProcessID: "P0", BurstDuration: 2}},
+		"a": {{ProcessID: "P1", BurstDuration: 1, Priority: 9}},
+	}
+	var w bytes.Buffer
+	if err := outputSections(&w, FCFS{}, "FCFS", workloads, SchedulerConfig{}, RenderOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if a, b := strings.Index(w.String(), "=== a ===\n"), strings.Index(w.String(), "\n\n=== b ===\n"); a != 0 || b < 0 {
+		t.Errorf("sections out of order:\n%s", w.String())
+	}
+	err := outputSections(io.Discard, FCFS{}, "FCFS", workloads, SchedulerConfig{Priorities: &PriorityRange{Min: 1, Max: 5}}, RenderOptions{})
+	if !errors.Is(err, ErrInvalidProcess) || !strings.Contains(err.Error(), `section "a"`) {
+		t.Errorf("got %v, want an invalid priority in section a", err)
+	}
+}
+
+func TestParsePriorityNames(t *testing.T) {
+	t.Parallel()
+	names, err := ParsePriorityNames("Urgent=0, high=1,batch=-5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(map[string]int64{"urgent": 0, "high": 1, "batch": -5}, names); diff != "" {
+		t.Errorf(diff)
+	}
+	processes, err := loadProcesses(strings.NewReader("ID,Burst,Arrival,Priority\nP0,5,0,URGENT\nP1,2,0,batch\n"), names)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if processes[0].Priority != 0 || processes[1].Priority != -5 {
+		t.Errorf("priorities %d and %d, want 0 and -5", processes[0].Priority, processes[1].Priority)
+	}
+	if _, err := loadProcesses(strings.NewReader("ID,Burst,Arrival,Priority\nP0,5,0,normal\n"), names); !errors.Is(err, ErrInvalidProcess) {
+		t.Errorf("a default name with custom names: got %v, want ErrInvalidProcess", err)
+	}
+
+	for _, s := range []string{"", "high", "high=", "=1", "high=one"} {
+		if _, err := ParsePriorityNames(s); !errors.Is(err, ErrInvalidArgs) {
+			t.Errorf("%q: got %v, want ErrInvalidArgs", s, err)
+		}
+	}
+}
+
+func TestValidateProcesses(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		processes []Process
+		wantErr   error
+	}{
+		{
+			name: "fits",
+			processes: []Process{
+				{ProcessID: "P0", ArrivalTime: math.MaxInt64 - 10, BurstDuration: 5},
+				{ProcessID: "P1", ArrivalTime: 0, BurstDuration: 5},
+			},
+		},
+		{
+			name: "bursts overflow",
+			processes: []Process{
+				{ProcessID: "P0", BurstDuration: math.MaxInt64 / 2},
+				{ProcessID: "P1", BurstDuration: math.MaxInt64 / 2},
+				{ProcessID: "P2", BurstDuration: 2},
+			},
+			wantErr: ErrOverflow,
+		},
+		{
+			name: "makespan overflows",
+			processes: []Process{
+				{ProcessID: "P0", ArrivalTime: 0, BurstDuration: 5},
+				{ProcessID: "P1", ArrivalTime: math.MaxInt64 - 3, BurstDuration: 1},
+			},
+			wantErr: ErrOverflow,
+		},
+		{
+			name: "io overflows",
+			processes: []Process{
+				{ProcessID: "P0", ArrivalTime: 0, BurstDuration: 5, IO: []IOBurst{{At: 2, Duration: math.MaxInt64 - 10}}},
+				{ProcessID: "P1", ArrivalTime: 3, BurstDuration: 5},
+			},
+			wantErr: ErrOverflow,
+		},
+		{
+			name: "io fits",
+			processes: []Process{
+				{ProcessID: "P0", ArrivalTime: 0, BurstDuration: 5, IO: []IOBurst{{At: 2, Duration: math.MaxInt64 - 20}}},
+				{ProcessID: "P1", ArrivalTime: 3, BurstDuration: 5},
+			},
+		},
+		{
+			name: "negative burst",
+			processes: []Process{
+				{ProcessID: "P0", BurstDuration: -1},
+			},
+			wantErr: ErrInvalidProcess,
+		},
+		{
+			name: "duplicate ID",
+			processes: []Process{
+				{ProcessID: "P0", BurstDuration: 1},
+				{ProcessID: "P0", BurstDuration: 2},
+			},
+			wantErr: ErrInvalidProcess,
+		},
+		{
+			name: "dependencies",
+			processes: []Process{
+				{ProcessID: "P0", DependsOn: []string{"P1", "P2"}},
+				{ProcessID: "P1", DependsOn: []string{"P2"}},
+				{ProcessID: "P2"},
+			},
+		},
+		{
+			name: "unknown dependency",
+			processes: []Process{
+				{ProcessID: "P0", DependsOn: []string{"P9"}},
+			},
+			wantErr: ErrInvalidProcess,
+		},
+		{
+			name: "dependency cycle",
+			processes: []Process{
+				{ProcessID: "P0", DependsOn: []string{"P1"}},
+				{ProcessID: "P1", DependsOn: []string{"P2"}},
+				{ProcessID: "P2", DependsOn: []string{"P0"}},
+			},
+			wantErr: ErrInvalidProcess,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if err := ValidateProcesses(tt.processes); !errors.Is(err, tt.wantErr) {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateResolution(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: "P0", ArrivalTime: math.MaxInt64 / 100, BurstDuration: 5},
+		{ProcessID: "P1", ArrivalTime: 0, BurstDuration: 5},
+	}
+	tests := []struct {
+		name       string
+		resolution int64
+		wantErr    error
+	}{
+		{name: "whole units", resolution: 1},
+		{name: "unset", resolution: 0},
+		{name: "fits", resolution: 10},
+		{name: "overflows", resolution: 1000, wantErr: ErrOverflow},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if err := ValidateResolution(processes, tt.resolution); !errors.Is(err, tt.wantErr) {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	// RR refuses to wrap around rather than scheduling with the wrong times.
+	err := SafeSchedule(RR{Quantum: 2, Resolution: 1000}, io.Discard, "rr", processes)
+	if !errors.Is(err, ErrOverflow) {
+		t.Errorf("RR: error = %v, want %v", err, ErrOverflow)
+	}
+}
+
+func TestSchedulerConfig_ValidatePriorities(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: "P0", Priority: 1},
+		{ProcessID: "P1", Priority: 10},
+		{ProcessID: "P2", Priority: -3},
+	}
+	tests := []struct {
+		name    string
+		spec    string // a -priority-range, or "" for none.
+		wantErr string
+	}{
+		{name: "unbounded"},
+		{name: "covers all", spec: "-3:10"},
+		{name: "negative priority", spec: "1:10", wantErr: "invalid process: P2: priority -3 outside 1:10"},
+		{name: "too high", spec: "-5:9", wantErr: "invalid process: P1: priority 10 outside -5:9"},
+		{name: "not a range", spec: "10", wantErr: `invalid args: priority range "10" is not min:max`},
+		{name: "not numbers", spec: "low:high", wantErr: `invalid args: priority range "low:high" is not min:max`},
+		{name: "empty", spec: "10:1", wantErr: `invalid args: priority range "10:1" is empty`},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var cfg SchedulerConfig
+			var err error
+			if tt.spec != "" {
+				var r PriorityRange
+				r, err = ParsePriorityRange(tt.spec)
+				cfg.Priorities = &r
+			}
+			if err == nil {
+				err = cfg.ValidatePriorities(processes)
+			}
+			got := ""
+			if err != nil {
+				got = err.Error()
+			}
+			if got != tt.wantErr {
+				t.Errorf("error = %q, want %q", got, tt.wantErr)
+			}
+		})
+	}
+
+	r := PriorityRange{Min: 1, Max: 10}
+	if got := []int64{r.Clamp(-3), r.Clamp(5), r.Clamp(12)}; !cmp.Equal(got, []int64{1, 5, 10}) {
+		t.Errorf("Clamp got %v, want [1 5 10]", got)
+	}
+}
+
+func Test_parsePercentiles(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		s       string
+		want    []float64
+		wantErr bool
+	}{
+		{s: "50,95,99", want: []float64{50, 95, 99}},
+		{s: " 99.9 ", want: []float64{99.9}},
+		{s: "100", want: []float64{100}},
+		{s: "0", wantErr: true},
+		{s: "50,101", wantErr: true},
+		{s: "p95", wantErr: true},
+		{s: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parsePercentiles(tt.s)
+		if (err != nil) != tt.wantErr || !errors.Is(err, ErrInvalidArgs) && tt.wantErr {
+			t.Errorf("parsePercentiles(%q) error = %v, wantErr %v", tt.s, err, tt.wantErr)
+		}
+		if diff := cmp.Diff(got, tt.want); diff != "" {
+			t.Errorf("parsePercentiles(%q): %s", tt.s, diff)
+		}
+	}
+}
+
 func loadFixture(t *testing.T, p ...string) string {
 	b, err := os.ReadFile(path.Join(p...))
 	if err != nil {
@@ -0,0 +1,122 @@
+package main
+
+// MLFQ schedules processes with a multi-level feedback queue. There is one round-robin queue
+// per entry of Quanta, from the most urgent, and the CPU always goes to the first process in
+// the most urgent non-empty queue for that level's quantum. Processes arrive in the first
+// queue, and one that uses its whole quantum without completing moves down a level, so
+// CPU-bound processes sink while short and interactive ones stay near the top; the last level
+// keeps its processes. A quantum <= 0 runs the processes at its level to completion, and no
+// Quanta is a single such level.
+//
+// A process arriving in a more urgent queue does not preempt the running one: it waits for the
+// running process's quantum to expire. To keep the sunk processes from starving, every Boost
+// time units every waiting process is moved back to the first queue, in level order; a Boost
+// <= 0 never does.
+type MLFQ struct {
+	Quanta []int64
+	Boost  int64
+}
+
+func (MLFQ) Name() string { return "mlfq" }
+
+func (s MLFQ) Schedule(processes []Process) ScheduleResult {
+	return s.scheduleClocked(processes, nil)
+}
+
+func (s MLFQ) scheduleClocked(processes []Process, clock *decisionClock) ScheduleResult {
+	quanta := s.Quanta
+	if len(quanta) == 0 {
+		quanta = []int64{0}
+	}
+	arr := newArrivals(processes)
+	remaining := make([]int64, len(processes))
+	for i := range processes {
+		remaining[i] = processes[i].BurstDuration
+	}
+
+	var (
+		now       int64
+		waiting   int
+		queues    = make([][]int, len(quanta)) // each level's round-robin queue.
+		nextBoost = s.Boost
+		queueArea int64 // integral of the ready queue length over time.
+		timings   = make([]processTiming, len(processes))
+		gantt     = make([]TimeSlice, 0, len(processes))
+		dog       watchdog
+		stuck     = unfinished(processes, remaining)
+	)
+	admit := func() {
+		arr.release(now, func(i int) {
+			// Processes that arrived during the last slice have been waiting since arrival.
+			queueArea += now - processes[i].ArrivalTime
+			queues[0] = append(queues[0], i)
+			waiting++
+		})
+	}
+	for done := 0; done < len(processes); {
+		admit()
+		if waiting == 0 {
+			// CPU is idle until the next arrival.
+			now = arr.idleUntil(now)
+			continue
+		}
+		if s.Boost > 0 && now >= nextBoost {
+			for level := 1; level < len(queues); level++ {
+				queues[0] = append(queues[0], queues[level]...)
+				queues[level] = nil
+			}
+			nextBoost = (now/s.Boost + 1) * s.Boost
+		}
+
+		dog.observe(now, done, stuck)
+		t0 := clock.start()
+		level := 0
+		for level < len(queues)-1 && len(queues[level]) == 0 {
+			level++
+		}
+		for l := range queues {
+			if hasPinned(queues[l], processes) {
+				level = l
+				break
+			}
+		}
+		k := firstPinned(queues[level], processes)
+		i := queues[level][k]
+		queues[level] = append(queues[level][:k], queues[level][k+1:]...)
+		waiting--
+		clock.stop(t0)
+
+		quantum := quanta[level]
+		run := remaining[i]
+		if quantum > 0 && quantum < run {
+			run = quantum
+		}
+		if n := len(gantt); n > 0 && gantt[n-1].PID == processes[i].ProcessID && gantt[n-1].Stop == now {
+			gantt[n-1].Stop += run
+		} else {
+			gantt = append(gantt, TimeSlice{PID: processes[i].ProcessID, Start: now, Stop: now + run})
+		}
+		queueArea += int64(waiting) * run
+		now += run
+		remaining[i] -= run
+
+		if remaining[i] == 0 {
+			arr.complete(i)
+		}
+		admit()
+		if remaining[i] > 0 {
+			if level < len(queues)-1 {
+				level++
+			}
+			queues[level] = append(queues[level], i)
+			waiting++
+			continue
+		}
+		done++
+		timings[i].Completion = now
+		timings[i].Turnaround = now - processes[i].ArrivalTime
+		timings[i].Waiting = timings[i].Turnaround - processes[i].BurstDuration
+	}
+
+	return newScheduleResult(processes, gantt, timings).withQueueArea(queueArea)
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMLFQ_Schedule(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		mlfq      MLFQ
+		processes []Process
+		wantGantt []TimeSlice
+	}{
+		{
+			name: "demotion",
+			mlfq: MLFQ{Quanta: []int64{2, 4}},
+			// P0 uses its whole first quantum and drops a level, so P1, arriving at 1, runs
+			// ahead of it.
+			processes: []Process{
+				{ProcessID: "P0", ArrivalTime: 0, BurstDuration: 6},
+				{ProcessID: "P1", ArrivalTime: 1, BurstDuration: 2},
+			},
+			wantGantt: []TimeSlice{
+				{PID: "P0", Start: 0, Stop: 2},
+				{PID: "P1", Start: 2, Stop: 4},
+				{PID: "P0", Start: 4, Stop: 8},
+			},
+		},
+		{
+			name: "last level keeps its processes",
+			mlfq: MLFQ{Quanta: []int64{1, 2}},
+			processes: []Process{
+				{ProcessID: "P0", ArrivalTime: 0, BurstDuration: 4},
+				{ProcessID: "P1", ArrivalTime: 0, BurstDuration: 4},
+			},
+			wantGantt: []TimeSlice{
+				{PID: "P0", Start: 0, Stop: 1},
+				{PID: "P1", Start: 1, Stop: 2},
+				{PID: "P0", Start: 2, Stop: 4},
+				{PID: "P1", Start: 4, Stop: 6},
+				{PID: "P0", Start: 6, Stop: 7},
+				{PID: "P1", Start: 7, Stop: 8},
+			},
+		},
+		{
+			name: "boost",
+			mlfq: MLFQ{Quanta: []int64{1, 2}, Boost: 4},
+			// At 4 both processes return to the first level, P1 ahead of P0, and each gets its
+			// 1-tick quantum again.
+			processes: []Process{
+				{ProcessID: "P0", ArrivalTime: 0, BurstDuration: 4},
+				{ProcessID: "P1", ArrivalTime: 0, BurstDuration: 4},
+			},
+			wantGantt: []TimeSlice{
+				{PID: "P0", Start: 0, Stop: 1},
+				{PID: "P1", Start: 1, Stop: 2},
+				{PID: "P0", Start: 2, Stop: 4},
+				{PID: "P1", Start: 4, Stop: 5},
+				{PID: "P0", Start: 5, Stop: 6},
+				{PID: "P1", Start: 6, Stop: 8},
+			},
+		},
+		{
+			name: "run to completion",
+			mlfq: MLFQ{},
+			processes: []Process{
+				{ProcessID: "P0", ArrivalTime: 0, BurstDuration: 3},
+				{ProcessID: "P1", ArrivalTime: 1, BurstDuration: 1},
+			},
+			wantGantt: []TimeSlice{
+				{PID: "P0", Start: 0, Stop: 3},
+				{PID: "P1", Start: 3, Stop: 4},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := tt.mlfq.Schedule(tt.processes)
+			if diff := cmp.Diff(got.Gantt, tt.wantGantt); diff != "" {
+				t.Errorf(diff)
+			}
+			if err := VerifyTurnaround(got); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestMLFQ_SingleLevel(t *testing.T) {
+	t.Parallel()
+	// With one level, MLFQ times every process as round robin with that level's quantum, or
+	// FCFS without one.
+	tests := []struct {
+		name string
+		mlfq MLFQ
+		want Scheduler
+	}{
+		{name: "rr", mlfq: MLFQ{Quanta: []int64{3}, Boost: 5}, want: RR{Quantum: 3}},
+		{name: "fcfs", mlfq: MLFQ{}, want: FCFS{}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			for seed := int64(1); seed <= 20; seed++ {
+				processes := GenerateProcesses(8, seed)
+				got, want := tt.mlfq.Schedule(processes), tt.want.Schedule(processes)
+				if diff := cmp.Diff(got.Rows, want.Rows); diff != "" {
+					t.Errorf("seed %d: %s", seed, diff)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,338 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+)
+
+// MultiCore schedules processes first-come, first-serve on Cores identical CPUs, each with its
+// own ready queue. A process is dispatched to a core by Dispatch when it is released, and each
+// core runs its queue in the order processes were dispatched to it; a process never moves
+// between cores.
+//
+// With Gang set, processes instead wait in one queue in the order they were released, and each
+// holds Width cores at once, one slice per core; Dispatch is ignored. The first process in the
+// queue starts as soon as enough cores are free, and no other process overtakes it. Backfill,
+// which implies Gang, lets later processes start early in the cores left free while the first
+// waits, provided they fit and will complete, by their burst durations, before the first could
+// start, so backfilling never delays it.
+//
+// Its Gantt slices carry the core they ran on; see CoreUtilization for the load on each core.
+type MultiCore struct {
+	Cores    int
+	Dispatch DispatchPolicy
+	Gang     bool
+	Backfill bool
+}
+
+// DispatchPolicy chooses the core a MultiCore process is queued on.
+type DispatchPolicy int
+
+const (
+	// FirstFree, the default, picks the first core with nothing running or queued, or the first
+	// core if every core is busy.
+	FirstFree DispatchPolicy = iota
+	// RoundRobinDispatch picks the cores in turn, whatever their load.
+	RoundRobinDispatch
+	// LeastLoaded picks the core with the least work left: the rest of its running process
+	// plus the bursts queued on it. Ties go to the first such core.
+	LeastLoaded
+)
+
+var dispatchNames = map[DispatchPolicy]string{FirstFree: "first-free", RoundRobinDispatch: "round-robin", LeastLoaded: "least-loaded"}
+
+// DispatchPolicies lists every DispatchPolicy.
+var DispatchPolicies = []DispatchPolicy{FirstFree, RoundRobinDispatch, LeastLoaded}
+
+func (d DispatchPolicy) String() string { return dispatchNames[d] }
+
+// Set parses one of first-free, round-robin or least-loaded, so a DispatchPolicy can be a
+// flag.Value.
+func (d *DispatchPolicy) Set(s string) error {
+	for policy, name := range dispatchNames {
+		if name == s {
+			*d = policy
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: unknown dispatch policy %q", ErrInvalidArgs, s)
+}
+
+func (MultiCore) Name() string { return "multicore" }
+
+func (s MultiCore) Schedule(processes []Process) ScheduleResult {
+	return s.scheduleClocked(processes, nil)
+}
+
+func (s MultiCore) scheduleClocked(processes []Process, clock *decisionClock) ScheduleResult {
+	cores := s.Cores
+	if cores < 1 {
+		cores = 1
+	}
+	if s.Gang || s.Backfill {
+		return s.scheduleGang(processes, cores, clock)
+	}
+	arr := newArrivals(processes)
+
+	var (
+		now       int64
+		running   = make([]int, cores)   // the process on each core, or -1.
+		free      = make([]int64, cores) // when each core's running process completes.
+		queues    = make([][]int, cores)
+		turn      int   // the next core for RoundRobinDispatch.
+		queueArea int64 // integral of the ready queue lengths over time.
+		timings   = make([]processTiming, len(processes))
+		gantt     = make([]TimeSlice, 0, len(processes))
+	)
+	for c := range running {
+		running[c] = -1
+	}
+	load := func(c int) int64 {
+		var work int64
+		if running[c] >= 0 {
+			work = free[c] - now
+		}
+		for _, i := range queues[c] {
+			work += processes[i].BurstDuration
+		}
+		return work
+	}
+	dispatch := func() int {
+		switch s.Dispatch {
+		case RoundRobinDispatch:
+			c := turn
+			turn = (turn + 1) % cores
+			return c
+		case LeastLoaded:
+			best := 0
+			for c := 1; c < cores; c++ {
+				if load(c) < load(best) {
+					best = c
+				}
+			}
+			return best
+		default:
+			for c := range queues {
+				if running[c] < 0 && len(queues[c]) == 0 {
+					return c
+				}
+			}
+			return 0
+		}
+	}
+	for done := 0; done < len(processes); {
+		for c, i := range running {
+			if i >= 0 && free[c] <= now {
+				done++
+				arr.complete(i)
+				running[c] = -1
+			}
+		}
+
+		t0 := clock.start()
+		arr.release(now, func(i int) {
+			// Processes held back by a prerequisite have been waiting since arrival.
+			queueArea += now - processes[i].ArrivalTime
+			c := dispatch()
+			queues[c] = append(queues[c], i)
+		})
+		for c := range queues {
+			if running[c] >= 0 || len(queues[c]) == 0 {
+				continue
+			}
+			k := firstPinned(queues[c], processes)
+			i := queues[c][k]
+			queues[c] = append(queues[c][:k], queues[c][k+1:]...)
+			p := processes[i]
+			running[c], free[c] = i, now+p.BurstDuration
+			gantt = append(gantt, TimeSlice{PID: p.ProcessID, Start: now, Stop: free[c], Core: c})
+			timings[i] = processTiming{
+				Waiting:    now - p.ArrivalTime,
+				Turnaround: free[c] - p.ArrivalTime,
+				Completion: free[c],
+			}
+		}
+		clock.stop(t0)
+
+		next := int64(math.MaxInt64)
+		for c, i := range running {
+			if i >= 0 {
+				next = min(next, free[c])
+			}
+		}
+		if t, ok := arr.nextArrival(); ok {
+			next = min(next, t)
+		}
+		if next == math.MaxInt64 {
+			if done < len(processes) {
+				now = arr.idleUntil(now)
+			}
+			continue
+		}
+		for _, q := range queues {
+			queueArea += int64(len(q)) * (next - now)
+		}
+		now = next
+	}
+
+	r := newScheduleResult(processes, gantt, timings).withQueueArea(queueArea)
+	r.Cores = cores
+	return r
+}
+
+func (s MultiCore) scheduleGang(processes []Process, cores int, clock *decisionClock) ScheduleResult {
+	arr := newArrivals(processes)
+	width := func(i int) int {
+		return min(cores, int(max(1, int64(processes[i].Width))))
+	}
+
+	var (
+		now        int64
+		running    = make([]int, cores)   // the process on each core, or -1.
+		free       = make([]int64, cores) // when each core's running process completes.
+		idle       = cores                // the number of cores with nothing running.
+		queue      []int                  // in the order the processes were released.
+		backfilled int
+		queueArea  int64 // integral of the queue length over time.
+		timings    = make([]processTiming, len(processes))
+		gantt      = make([]TimeSlice, 0, len(processes))
+	)
+	for c := range running {
+		running[c] = -1
+	}
+	start := func(i int) {
+		p := processes[i]
+		stop := now + p.BurstDuration
+		for c, need := 0, width(i); need > 0; c++ {
+			if running[c] < 0 {
+				running[c], free[c] = i, stop
+				gantt = append(gantt, TimeSlice{PID: p.ProcessID, Start: now, Stop: stop, Core: c})
+				idle--
+				need--
+			}
+		}
+		timings[i] = processTiming{
+			Waiting:    now - p.ArrivalTime,
+			Turnaround: stop - p.ArrivalTime,
+			Completion: stop,
+		}
+	}
+	// reservation is the earliest time enough cores will be free for process i to start, if
+	// nothing else starts before then.
+	reservation := func(i int) int64 {
+		var stops []int64
+		for c, j := range running {
+			if j >= 0 {
+				stops = append(stops, free[c])
+			}
+		}
+		sort.Slice(stops, func(a, b int) bool { return stops[a] < stops[b] })
+		return stops[width(i)-idle-1]
+	}
+	for done := 0; done < len(processes); {
+		for c, i := range running {
+			if i >= 0 && free[c] <= now {
+				running[c] = -1
+				idle++
+				if !slices.Contains(running, i) {
+					done++
+					arr.complete(i)
+				}
+			}
+		}
+
+		t0 := clock.start()
+		arr.release(now, func(i int) {
+			// Processes held back by a prerequisite have been waiting since arrival.
+			queueArea += now - processes[i].ArrivalTime
+			// A pinned process goes ahead of every unpinned one, so it is the next to start.
+			n := len(queue)
+			if processes[i].Pinned {
+				n = slices.IndexFunc(queue, func(j int) bool { return !processes[j].Pinned })
+				if n < 0 {
+					n = len(queue)
+				}
+			}
+			queue = slices.Insert(queue, n, i)
+		})
+		for len(queue) > 0 && width(queue[0]) <= idle {
+			start(queue[0])
+			queue = queue[1:]
+		}
+		if s.Backfill && len(queue) > 1 {
+			shadow := reservation(queue[0])
+			kept := queue[:1]
+			for _, i := range queue[1:] {
+				if width(i) <= idle && now+processes[i].BurstDuration <= shadow {
+					start(i)
+					backfilled++
+				} else {
+					kept = append(kept, i)
+				}
+			}
+			queue = kept
+		}
+		clock.stop(t0)
+
+		next := int64(math.MaxInt64)
+		for c, i := range running {
+			if i >= 0 {
+				next = min(next, free[c])
+			}
+		}
+		if t, ok := arr.nextArrival(); ok {
+			next = min(next, t)
+		}
+		if next == math.MaxInt64 {
+			if done < len(processes) {
+				now = arr.idleUntil(now)
+			}
+			continue
+		}
+		queueArea += int64(len(queue)) * (next - now)
+		now = next
+	}
+
+	r := newScheduleResult(processes, gantt, timings).withQueueArea(queueArea)
+	r.Cores = cores
+	r.Backfilled = backfilled
+	return r
+}
+
+// CoreUsage is the load on one core of a schedule over [0, Makespan].
+type CoreUsage struct {
+	Core int
+	Busy int64
+	Idle int64
+}
+
+// Utilization is the fraction of the schedule the core was busy.
+func (u CoreUsage) Utilization() float64 {
+	if u.Busy+u.Idle == 0 {
+		return 0
+	}
+	return float64(u.Busy) / float64(u.Busy+u.Idle)
+}
+
+// CoreUtilization returns the busy and idle time of each of r's cores in core order, followed
+// by their total. A uniprocessor schedule has a single core.
+func CoreUtilization(r ScheduleResult) (cores []CoreUsage, total CoreUsage) {
+	cores = make([]CoreUsage, max(1, int64(r.Cores)))
+	for c := range cores {
+		cores[c].Core = c
+	}
+	for _, slice := range r.Gantt {
+		if slice.Core < len(cores) {
+			cores[slice.Core].Busy += slice.Stop - slice.Start
+		}
+	}
+	total.Core = -1
+	for c := range cores {
+		cores[c].Idle = max(0, r.Makespan-cores[c].Busy)
+		total.Busy += cores[c].Busy
+		total.Idle += cores[c].Idle
+	}
+	return cores, total
+}
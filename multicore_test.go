@@ -0,0 +1,194 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMultiCore_Schedule(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: "P0", ArrivalTime: 0, BurstDuration: 10},
+		{ProcessID: "P1", ArrivalTime: 1, BurstDuration: 2},
+		{ProcessID: "P2", ArrivalTime: 2, BurstDuration: 3},
+		{ProcessID: "P3", ArrivalTime: 2, BurstDuration: 1},
+	}
+	// P1 finds core 1 free, but P2 and P3 arrive with both cores busy and queue on core 0
+	// behind P0, leaving core 1 idle from 3.
+	got := MultiCore{Cores: 2}.Schedule(processes)
+	wantGantt := []TimeSlice{
+		{PID: "P0", Start: 0, Stop: 10},
+		{PID: "P1", Start: 1, Stop: 3, Core: 1},
+		{PID: "P2", Start: 10, Stop: 13},
+		{PID: "P3", Start: 13, Stop: 14},
+	}
+	if diff := cmp.Diff(got.Gantt, wantGantt); diff != "" {
+		t.Errorf(diff)
+	}
+	if got.Makespan != 14 || got.Cores != 2 {
+		t.Errorf("makespan %d on %d cores, want 14 on 2", got.Makespan, got.Cores)
+	}
+
+	cores, total := CoreUtilization(got)
+	wantCores := []CoreUsage{{Core: 0, Busy: 14, Idle: 0}, {Core: 1, Busy: 2, Idle: 12}}
+	if diff := cmp.Diff(cores, wantCores); diff != "" {
+		t.Errorf(diff)
+	}
+	if diff := cmp.Diff(total, CoreUsage{Core: -1, Busy: 16, Idle: 12}); diff != "" {
+		t.Errorf(diff)
+	}
+
+	// One core is plain FCFS.
+	single := MultiCore{Cores: 1}.Schedule(processes)
+	single.Cores = 0
+	if diff := cmp.Diff(single, FCFS{}.Schedule(processes)); diff != "" {
+		t.Errorf("one core: %s", diff)
+	}
+}
+
+func Test_outputCores(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: "P0", ArrivalTime: 0, BurstDuration: 4},
+		{ProcessID: "P1", ArrivalTime: 0, BurstDuration: 1},
+	}
+	var w bytes.Buffer
+	outputResult(&w, "Multi-core", MultiCore{Cores: 3}.Schedule(processes), RenderOptions{})
+	for _, want := range []string{
+		"Core 1\n|  P1  |\n0      1\n",
+		"Core 2\n(idle)\n",
+		"Core 0: busy 4, idle 0 (100.00% utilization)\n" +
+			"Core 1: busy 1, idle 3 (25.00% utilization)\n" +
+			"Core 2: busy 0, idle 4 (0.00% utilization)\n" +
+			"All cores: busy 5, idle 7 (41.67% utilization)\n",
+	} {
+		if !strings.Contains(w.String(), want) {
+			t.Errorf("output is missing %q:\n%s", want, w.String())
+		}
+	}
+}
+
+func TestMultiCore_Dispatch(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: "P0", ArrivalTime: 0, BurstDuration: 10},
+		{ProcessID: "P1", ArrivalTime: 1, BurstDuration: 2},
+		{ProcessID: "P2", ArrivalTime: 2, BurstDuration: 3},
+		{ProcessID: "P3", ArrivalTime: 2, BurstDuration: 1},
+	}
+	tests := []struct {
+		policy       DispatchPolicy
+		wantGantt    []TimeSlice
+		wantMakespan int64
+		wantSpread   int64
+	}{
+		{
+			policy: FirstFree,
+			wantGantt: []TimeSlice{
+				{PID: "P0", Start: 0, Stop: 10},
+				{PID: "P1", Start: 1, Stop: 3, Core: 1},
+				{PID: "P2", Start: 10, Stop: 13},
+				{PID: "P3", Start: 13, Stop: 14},
+			},
+			wantMakespan: 14,
+			wantSpread:   12,
+		},
+		{
+			policy: RoundRobinDispatch,
+			wantGantt: []TimeSlice{
+				{PID: "P0", Start: 0, Stop: 10},
+				{PID: "P1", Start: 1, Stop: 3, Core: 1},
+				{PID: "P3", Start: 3, Stop: 4, Core: 1},
+				{PID: "P2", Start: 10, Stop: 13},
+			},
+			wantMakespan: 13,
+			wantSpread:   10,
+		},
+		{
+			// At 2 core 0 has 8 left and core 1 has 1, so both arrivals follow P1.
+			policy: LeastLoaded,
+			wantGantt: []TimeSlice{
+				{PID: "P0", Start: 0, Stop: 10},
+				{PID: "P1", Start: 1, Stop: 3, Core: 1},
+				{PID: "P2", Start: 3, Stop: 6, Core: 1},
+				{PID: "P3", Start: 6, Stop: 7, Core: 1},
+			},
+			wantMakespan: 10,
+			wantSpread:   4,
+		},
+	}
+	comparisons := CompareDispatch(processes, 2)
+	for n, tt := range tests {
+		n, tt := n, tt
+		t.Run(tt.policy.String(), func(t *testing.T) {
+			t.Parallel()
+			got := MultiCore{Cores: 2, Dispatch: tt.policy}.Schedule(processes)
+			if diff := cmp.Diff(got.Gantt, tt.wantGantt); diff != "" {
+				t.Errorf(diff)
+			}
+			c := comparisons[n]
+			if c.Policy != tt.policy || c.Result.Makespan != tt.wantMakespan || c.Spread != tt.wantSpread {
+				t.Errorf("CompareDispatch: %v makespan %d spread %d, want %v makespan %d spread %d",
+					c.Policy, c.Result.Makespan, c.Spread, tt.policy, tt.wantMakespan, tt.wantSpread)
+			}
+		})
+	}
+	if ll, ff := comparisons[2].Result.Makespan, comparisons[0].Result.Makespan; ll >= ff {
+		t.Errorf("least-loaded makespan %d does not beat first-free's %d", ll, ff)
+	}
+}
+
+func TestMultiCore_Backfill(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: "P0", ArrivalTime: 0, BurstDuration: 6, Width: 2},
+		{ProcessID: "P1", ArrivalTime: 0, BurstDuration: 4, Width: 3},
+		{ProcessID: "P2", ArrivalTime: 1, BurstDuration: 3},
+		{ProcessID: "P3", ArrivalTime: 1, BurstDuration: 2},
+	}
+	// P1 needs all three cores, so it waits for P0 until 6. Without backfilling P2 and P3 wait
+	// behind it; with it they use the third core in the meantime, as both finish by 6.
+	c := CompareBackfill(processes, 3)
+	wantStrict := []TimeSlice{
+		{PID: "P0", Start: 0, Stop: 6},
+		{PID: "P0", Start: 0, Stop: 6, Core: 1},
+		{PID: "P1", Start: 6, Stop: 10},
+		{PID: "P1", Start: 6, Stop: 10, Core: 1},
+		{PID: "P1", Start: 6, Stop: 10, Core: 2},
+		{PID: "P2", Start: 10, Stop: 13},
+		{PID: "P3", Start: 10, Stop: 12, Core: 1},
+	}
+	if diff := cmp.Diff(c.Strict.Gantt, wantStrict); diff != "" {
+		t.Errorf("strict: %s", diff)
+	}
+	wantBackfilled := []TimeSlice{
+		{PID: "P0", Start: 0, Stop: 6},
+		{PID: "P0", Start: 0, Stop: 6, Core: 1},
+		{PID: "P2", Start: 1, Stop: 4, Core: 2},
+		{PID: "P3", Start: 4, Stop: 6, Core: 2},
+		{PID: "P1", Start: 6, Stop: 10},
+		{PID: "P1", Start: 6, Stop: 10, Core: 1},
+		{PID: "P1", Start: 6, Stop: 10, Core: 2},
+	}
+	if diff := cmp.Diff(c.Backfilled.Gantt, wantBackfilled); diff != "" {
+		t.Errorf("backfilled: %s", diff)
+	}
+	if c.Strict.Backfilled != 0 || c.Backfilled.Backfilled != 2 {
+		t.Errorf("backfilled %d strict and %d with backfill, want 0 and 2", c.Strict.Backfilled, c.Backfilled.Backfilled)
+	}
+
+	var w bytes.Buffer
+	outputBackfill(&w, c)
+	if want := "Backfilled: 2 of 4 processes, makespan 13 -> 10 (23.08% shorter)\n"; w.String() != want {
+		t.Errorf("outputBackfill got %q, want %q", w.String(), want)
+	}
+
+	// A process that would run past the reservation is not backfilled.
+	processes[3].BurstDuration = 5
+	if r := (MultiCore{Cores: 3, Backfill: true}).Schedule(processes); r.Backfilled != 1 || r.Makespan != 15 {
+		t.Errorf("long P3: backfilled %d, makespan %d, want 1 and 15", r.Backfilled, r.Makespan)
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// processRecord is a process line of the NDJSON output. Times are in time units.
+type processRecord struct {
+	Type             string            `json:"type"`
+	PID              string            `json:"pid"`
+	Priority         int64             `json:"priority"`
+	Burst            int64             `json:"burst"`
+	Arrival          int64             `json:"arrival"`
+	Group            string            `json:"group,omitempty"`
+	DependsOn        []string          `json:"dependsOn,omitempty"`
+	Deadline         int64             `json:"deadline,omitempty"`
+	ResponseDeadline int64             `json:"responseDeadline,omitempty"`
+	Response         float64           `json:"response"`
+	Wait             float64           `json:"wait"`
+	Turnaround       float64           `json:"turnaround"`
+	Completion       float64           `json:"completion"`
+	Meta             map[string]string `json:"meta,omitempty"`
+}
+
+// summaryRecord is the last line of the NDJSON output. Times are in time units.
+type summaryRecord struct {
+	Type            string  `json:"type"`
+	Algo            string  `json:"algo"`
+	Processes       int     `json:"processes"`
+	AvgWait         float64 `json:"avgWait"`
+	AvgTurnaround   float64 `json:"avgTurnaround"`
+	Throughput      float64 `json:"throughput"`
+	Makespan        float64 `json:"makespan"`
+	Stretch         float64 `json:"stretch"`
+	IdleTime        float64 `json:"idleTime"`
+	AvgQueueLength  float64 `json:"avgQueueLength"`
+	ContextSwitches int     `json:"contextSwitches"`
+	Cores           int     `json:"cores,omitempty"`
+}
+
+// encodeNDJSON writes r as newline-delimited JSON: an object of type "process" per process in
+// input order, with its inputs and timing, then one of type "summary" with the averages. Each
+// line is written as it is encoded, so a consumer can read the processes as they stream.
+func encodeNDJSON(w io.Writer, algo string, r ScheduleResult) error {
+	enc := json.NewEncoder(w)
+	scale := float64(max(1, r.Resolution))
+	responses := ResponseTimes(r)
+	for i, row := range r.Rows {
+		p := row.Process
+		record := processRecord{
+			Type:             "process",
+			PID:              p.ProcessID,
+			Priority:         p.Priority,
+			Burst:            p.BurstDuration,
+			Arrival:          p.ArrivalTime,
+			Group:            p.GroupID,
+			DependsOn:        p.DependsOn,
+			Deadline:         p.Deadline,
+			ResponseDeadline: p.ResponseDeadline,
+			Response:         float64(responses[i]) / scale,
+			Wait:             float64(row.Waiting) / scale,
+			Turnaround:       float64(row.Turnaround) / scale,
+			Completion:       float64(row.Completion) / scale,
+			Meta:             p.Meta,
+		}
+		if err := enc.Encode(record); err != nil {
+			return fmt.Errorf("%w: encoding %s", err, p.ProcessID)
+		}
+	}
+	summary := summaryRecord{
+		Type:            "summary",
+		Algo:            algo,
+		Processes:       len(r.Rows),
+		AvgWait:         r.AvgWait,
+		AvgTurnaround:   r.AvgTurnaround,
+		Throughput:      r.Throughput,
+		Makespan:        float64(r.Makespan) / scale,
+		Stretch:         r.Stretch(),
+		IdleTime:        float64(r.IdleTime) / scale,
+		AvgQueueLength:  r.AvgQueueLength,
+		ContextSwitches: r.ContextSwitches,
+		Cores:           r.Cores,
+	}
+	if err := enc.Encode(summary); err != nil {
+		return fmt.Errorf("%w: encoding summary", err)
+	}
+	return nil
+}
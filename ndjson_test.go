@@ -0,0 +1,29 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_encodeNDJSON(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: "P0", ArrivalTime: 0, BurstDuration: 3, Priority: 2},
+		{ProcessID: "P1", ArrivalTime: 1, BurstDuration: 1, GroupID: "web", DependsOn: []string{"P0"}, Meta: map[string]string{"job": "42", "tenant": "acme"}},
+	}
+	var w bytes.Buffer
+	if err := encodeNDJSON(&w, "rr", RR{Quantum: 5, Resolution: 10}.Schedule(processes)); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		`{"type":"process","pid":"P0","priority":2,"burst":3,"arrival":0,"response":0,"wait":0,"turnaround":3,"completion":3}`,
+		`{"type":"process","pid":"P1","priority":0,"burst":1,"arrival":1,"group":"web","dependsOn":["P0"],"response":2,"wait":2,"turnaround":3,"completion":4,"meta":{"job":"42","tenant":"acme"}}`,
+		`{"type":"summary","algo":"rr","processes":2,"avgWait":1,"avgTurnaround":3,"throughput":0.5,"makespan":4,"stretch":1,"idleTime":0,"avgQueueLength":0.5,"contextSwitches":1}`,
+	}
+	if diff := cmp.Diff(strings.Join(want, "\n")+"\n", w.String()); diff != "" {
+		t.Errorf(diff)
+	}
+}
@@ -0,0 +1,250 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"slices"
+)
+
+// CancelPolicy is what Online does with a process cancelled while it is running.
+type CancelPolicy int
+
+const (
+	// StopImmediately takes a cancelled process off the CPU at once.
+	StopImmediately CancelPolicy = iota
+	// FinishQuantum lets a cancelled process run out its current quantum first. If it completes
+	// in that time it completes rather than being cancelled.
+	FinishQuantum
+)
+
+// Cancellation is a process cancelled before it completed: the Time it was cancelled at and
+// the CPU time it had Consumed by then.
+type Cancellation struct {
+	Process  Process
+	Time     int64
+	Consumed int64
+}
+
+// Online is a round-robin scheduler driven as the simulation runs: processes are submitted as
+// they become known rather than all up front, the simulation is advanced with RunUntil or
+// Drain, and any process that has not completed can be cancelled. It ignores DependsOn.
+type Online struct {
+	quantum   int64
+	policy    CancelPolicy
+	now       int64
+	processes []Process
+	remaining []int64
+	timings   []processTiming
+	index     map[string]int
+	finished  []bool // completed or cancelled.
+	completed []bool
+	pending   []int // submitted but not yet arrived, in arrival order.
+	ready     []int
+	gantt     []TimeSlice
+	queueArea int64 // integral of the ready queue length over time.
+	cancelled []Cancellation
+
+	running    int // the process on the CPU, or -1.
+	sliceStart int64
+	sliceEnd   int64
+	cancelling bool // the running process is cancelled at the end of its slice.
+}
+
+// NewOnline returns an Online scheduler at time 0 that runs each process for quantum before
+// preempting it, or to completion if quantum <= 0, and cancels running processes by policy.
+func NewOnline(quantum int64, policy CancelPolicy) *Online {
+	return &Online{quantum: quantum, policy: policy, index: make(map[string]int), running: -1}
+}
+
+// Now is the current simulation time.
+func (o *Online) Now() int64 { return o.now }
+
+// Submit adds p, which joins the ready queue at its ArrivalTime. It returns an error if p has
+// already arrived before the current time, or a process with its ID was already submitted.
+func (o *Online) Submit(p Process) error {
+	switch {
+	case p.ArrivalTime < o.now:
+		return fmt.Errorf("%w: %s arrives at %d, before the current time %d", ErrInvalidArgs, p.ProcessID, p.ArrivalTime, o.now)
+	case p.BurstDuration < 0:
+		return fmt.Errorf("%w: %s: negative burst", ErrInvalidProcess, p.ProcessID)
+	}
+	if _, dup := o.index[p.ProcessID]; dup {
+		return fmt.Errorf("%w: %s was already submitted", ErrInvalidArgs, p.ProcessID)
+	}
+	i := len(o.processes)
+	o.index[p.ProcessID] = i
+	o.processes = append(o.processes, p)
+	o.remaining = append(o.remaining, p.BurstDuration)
+	o.timings = append(o.timings, processTiming{})
+	o.finished = append(o.finished, false)
+	o.completed = append(o.completed, false)
+	n := len(o.pending)
+	for n > 0 && o.processes[o.pending[n-1]].ArrivalTime > p.ArrivalTime {
+		n--
+	}
+	o.pending = slices.Insert(o.pending, n, i)
+	return nil
+}
+
+// Cancel cancels the process pid at the current time: it is dropped from the ready queue, or
+// never arrives, or if it is running is stopped as the CancelPolicy says. It returns an error
+// if pid was never submitted or has already completed or been cancelled.
+func (o *Online) Cancel(pid string) error {
+	i, ok := o.index[pid]
+	switch {
+	case !ok:
+		return fmt.Errorf("%w: no process %s", ErrInvalidArgs, pid)
+	case o.finished[i], i == o.running && o.cancelling:
+		return fmt.Errorf("%w: %s has already completed or been cancelled", ErrInvalidArgs, pid)
+	case i == o.running:
+		o.cancelling = true
+		if o.policy == StopImmediately {
+			o.sliceEnd = o.now
+			o.endSlice()
+		}
+		return nil
+	}
+	if n := slices.Index(o.ready, i); n >= 0 {
+		o.ready = slices.Delete(o.ready, n, n+1)
+	} else {
+		n = slices.Index(o.pending, i)
+		o.pending = slices.Delete(o.pending, n, n+1)
+	}
+	o.cancel(i)
+	return nil
+}
+
+// RunUntil advances the simulation to time t, leaving a process whose slice runs past t on the
+// CPU. It does nothing if t is not after the current time.
+func (o *Online) RunUntil(t int64) {
+	if t <= o.now {
+		return
+	}
+	o.run(t)
+	o.now = t
+}
+
+// Drain runs the simulation until every submitted process has completed or been cancelled.
+func (o *Online) Drain() {
+	o.run(math.MaxInt64)
+}
+
+// run simulates up to t, stopping early once nothing is left to run.
+func (o *Online) run(t int64) {
+	for {
+		o.admit()
+		if o.running < 0 {
+			if len(o.ready) == 0 {
+				// CPU is idle until the next arrival.
+				if len(o.pending) == 0 || o.processes[o.pending[0]].ArrivalTime > t {
+					return
+				}
+				o.now = o.processes[o.pending[0]].ArrivalTime
+				continue
+			}
+			o.dispatch()
+		}
+		if o.sliceEnd > t {
+			o.queueArea += int64(len(o.ready)) * (t - o.now)
+			o.now = t
+			return
+		}
+		o.queueArea += int64(len(o.ready)) * (o.sliceEnd - o.now)
+		o.now = o.sliceEnd
+		o.endSlice()
+	}
+}
+
+// admit moves the processes that have arrived by now to the ready queue.
+func (o *Online) admit() {
+	for len(o.pending) > 0 && o.processes[o.pending[0]].ArrivalTime <= o.now {
+		i := o.pending[0]
+		o.pending = o.pending[1:]
+		// Processes that arrived during the last slice have been waiting since arrival.
+		o.queueArea += o.now - o.processes[i].ArrivalTime
+		o.ready = append(o.ready, i)
+	}
+}
+
+func (o *Online) dispatch() {
+	k := firstPinned(o.ready, o.processes)
+	i := o.ready[k]
+	o.ready = append(o.ready[:k], o.ready[k+1:]...)
+	run := o.remaining[i]
+	if o.quantum > 0 && o.quantum < run {
+		run = o.quantum
+	}
+	o.running, o.sliceStart, o.sliceEnd = i, o.now, o.now+run
+}
+
+// endSlice takes the running process off the CPU at sliceEnd, which is now, and completes,
+// cancels or requeues it.
+func (o *Online) endSlice() {
+	i := o.running
+	o.running = -1
+	if o.sliceEnd > o.sliceStart || o.processes[i].BurstDuration == 0 {
+		o.gantt = append(o.gantt, TimeSlice{PID: o.processes[i].ProcessID, Start: o.sliceStart, Stop: o.sliceEnd})
+	}
+	o.remaining[i] -= o.sliceEnd - o.sliceStart
+	cancelling := o.cancelling
+	o.cancelling = false
+	switch {
+	case o.remaining[i] == 0:
+		o.finished[i], o.completed[i] = true, true
+		o.timings[i].Completion = o.now
+		o.timings[i].Turnaround = o.now - o.processes[i].ArrivalTime
+		o.timings[i].Waiting = o.timings[i].Turnaround - o.processes[i].BurstDuration
+	case cancelling:
+		o.cancel(i)
+	default:
+		// Arrivals during the slice queue ahead of the preempted process.
+		o.admit()
+		o.ready = append(o.ready, i)
+	}
+}
+
+func (o *Online) cancel(i int) {
+	o.finished[i] = true
+	o.cancelled = append(o.cancelled, Cancellation{
+		Process:  o.processes[i],
+		Time:     o.now,
+		Consumed: o.processes[i].BurstDuration - o.remaining[i],
+	})
+}
+
+// Result returns the schedule so far of the processes that have completed, in the order they
+// were submitted. The Gantt also holds the slices of cancelled processes and of any running
+// process's completed slices, and AvgQueueLength counts cancelled processes while they waited.
+func (o *Online) Result() ScheduleResult {
+	var (
+		processes []Process
+		timings   []processTiming
+	)
+	for i, p := range o.processes {
+		if o.completed[i] {
+			processes = append(processes, p)
+			timings = append(timings, o.timings[i])
+		}
+	}
+	return newScheduleResult(processes, o.gantt, timings).withQueueArea(o.queueArea)
+}
+
+// Cancellations returns the cancelled processes in the order they were cancelled.
+func (o *Online) Cancellations() []Cancellation {
+	return slices.Clone(o.cancelled)
+}
+
+// OnlineSchedule renders the processes o has completed, then those it cancelled.
+func OnlineSchedule(w io.Writer, title string, o *Online) {
+	outputResult(w, title, o.Result(), RenderOptions{})
+	outputCancellations(w, o.Cancellations())
+}
+
+// outputCancellations writes when each cancelled process was cancelled and how much of its
+// burst it had run.
+func outputCancellations(w io.Writer, cancelled []Cancellation) {
+	for _, c := range cancelled {
+		_, _ = fmt.Fprintf(w, "Cancelled: %s at %d after %d of %d\n", c.Process.ProcessID, c.Time, c.Consumed, c.Process.BurstDuration)
+	}
+}
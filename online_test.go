@@ -0,0 +1,177 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestOnline_Cancel(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name          string
+		policy        CancelPolicy
+		wantGantt     []TimeSlice
+		wantCancelled Cancellation
+	}{
+		{
+			name:   "stop immediately",
+			policy: StopImmediately,
+			wantGantt: []TimeSlice{
+				{PID: "P0", Start: 0, Stop: 2},
+				{PID: "P1", Start: 2, Stop: 3},
+				{PID: "P2", Start: 3, Stop: 5},
+				{PID: "P0", Start: 5, Stop: 7},
+				{PID: "P2", Start: 7, Stop: 9},
+				{PID: "P0", Start: 9, Stop: 10},
+			},
+			wantCancelled: Cancellation{Time: 3, Consumed: 1},
+		},
+		{
+			name:   "finish quantum",
+			policy: FinishQuantum,
+			wantGantt: []TimeSlice{
+				{PID: "P0", Start: 0, Stop: 2},
+				{PID: "P1", Start: 2, Stop: 4},
+				{PID: "P2", Start: 4, Stop: 6},
+				{PID: "P0", Start: 6, Stop: 8},
+				{PID: "P2", Start: 8, Stop: 10},
+				{PID: "P0", Start: 10, Stop: 11},
+			},
+			wantCancelled: Cancellation{Time: 4, Consumed: 2},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			o := NewOnline(2, tt.policy)
+			processes := []Process{
+				{ProcessID: "P0", ArrivalTime: 0, BurstDuration: 5},
+				{ProcessID: "P1", ArrivalTime: 0, BurstDuration: 3},
+				{ProcessID: "P2", ArrivalTime: 1, BurstDuration: 4},
+			}
+			for _, p := range processes {
+				if err := o.Submit(p); err != nil {
+					t.Fatal(err)
+				}
+			}
+			// P1 is running when it is cancelled at 3.
+			o.RunUntil(3)
+			if err := o.Cancel("P1"); err != nil {
+				t.Fatal(err)
+			}
+			if err := o.Cancel("P1"); !errors.Is(err, ErrInvalidArgs) {
+				t.Errorf("cancelling twice: got %v, want ErrInvalidArgs", err)
+			}
+			o.Drain()
+
+			r := o.Result()
+			if diff := cmp.Diff(tt.wantGantt, r.Gantt); diff != "" {
+				t.Errorf(diff)
+			}
+			var got []string
+			for _, row := range r.Rows {
+				got = append(got, row.Process.ProcessID)
+			}
+			if diff := cmp.Diff([]string{"P0", "P2"}, got); diff != "" {
+				t.Errorf("rows: %s", diff)
+			}
+			tt.wantCancelled.Process = processes[1]
+			if diff := cmp.Diff([]Cancellation{tt.wantCancelled}, o.Cancellations()); diff != "" {
+				t.Errorf("cancellations: %s", diff)
+			}
+			if err := VerifyTurnaround(r); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestOnline_CancelWaiting(t *testing.T) {
+	t.Parallel()
+	o := NewOnline(0, StopImmediately)
+	for _, p := range []Process{
+		{ProcessID: "P0", ArrivalTime: 0, BurstDuration: 4},
+		{ProcessID: "P1", ArrivalTime: 1, BurstDuration: 2},
+		{ProcessID: "P2", ArrivalTime: 9, BurstDuration: 0},
+	} {
+		if err := o.Submit(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// P1 is cancelled from the ready queue and P2 before it arrives.
+	o.RunUntil(2)
+	if err := o.Cancel("P1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := o.Cancel("P2"); err != nil {
+		t.Fatal(err)
+	}
+	o.Drain()
+	if o.Now() != 4 {
+		t.Errorf("drained at %d, want 4", o.Now())
+	}
+	if r := o.Result(); len(r.Rows) != 1 || r.Rows[0].Completion != 4 {
+		t.Errorf("rows %+v, want only P0 completing at 4", r.Rows)
+	}
+
+	var w bytes.Buffer
+	OnlineSchedule(&w, "Online", o)
+	for _, line := range []string{"Cancelled: P1 at 2 after 0 of 2\n", "Cancelled: P2 at 2 after 0 of 0\n"} {
+		if !strings.Contains(w.String(), line) {
+			t.Errorf("output is missing %q:\n%s", line, w.String())
+		}
+	}
+}
+
+func TestOnline_Errors(t *testing.T) {
+	t.Parallel()
+	o := NewOnline(2, StopImmediately)
+	if err := o.Submit(Process{ProcessID: "P0", BurstDuration: 1}); err != nil {
+		t.Fatal(err)
+	}
+	o.RunUntil(5)
+	tests := []struct {
+		name    string
+		err     error
+		wantErr error
+	}{
+		{name: "duplicate", err: o.Submit(Process{ProcessID: "P0", ArrivalTime: 6, BurstDuration: 1}), wantErr: ErrInvalidArgs},
+		{name: "in the past", err: o.Submit(Process{ProcessID: "P1", ArrivalTime: 4, BurstDuration: 1}), wantErr: ErrInvalidArgs},
+		{name: "negative burst", err: o.Submit(Process{ProcessID: "P2", ArrivalTime: 5, BurstDuration: -1}), wantErr: ErrInvalidProcess},
+		{name: "unknown", err: o.Cancel("P9"), wantErr: ErrInvalidArgs},
+		{name: "completed", err: o.Cancel("P0"), wantErr: ErrInvalidArgs},
+	}
+	for _, tt := range tests {
+		if !errors.Is(tt.err, tt.wantErr) {
+			t.Errorf("%s: got %v, want %v", tt.name, tt.err, tt.wantErr)
+		}
+	}
+}
+
+func TestOnline_MatchesRR(t *testing.T) {
+	t.Parallel()
+	// Submitted up front and never cancelled, an Online run is round-robin, pinning included.
+	for seed := int64(0); seed < 20; seed++ {
+		processes := GenerateProcesses(12, seed)
+		if seed%2 == 1 {
+			for i := range processes {
+				processes[i].Pinned = i%3 == 0
+			}
+		}
+		o := NewOnline(3, StopImmediately)
+		for _, p := range processes {
+			if err := o.Submit(p); err != nil {
+				t.Fatal(err)
+			}
+		}
+		o.Drain()
+		if diff := cmp.Diff(RR{Quantum: 3}.Schedule(processes), o.Result()); diff != "" {
+			t.Errorf("seed %d: %s", seed, diff)
+		}
+	}
+}
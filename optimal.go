@@ -0,0 +1,234 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+)
+
+// MaxOptimalityProcesses is the most processes CheckOptimal will enumerate the orderings of.
+const MaxOptimalityProcesses = 10
+
+// Objective is the measure CheckOptimal minimizes.
+type Objective int
+
+const (
+	// AvgWaitObjective is the average wait, in time units.
+	AvgWaitObjective Objective = iota + 1
+	// MakespanObjective is the time the last process completes, in time units.
+	MakespanObjective
+)
+
+var objectiveNames = map[Objective]string{AvgWaitObjective: "wait", MakespanObjective: "makespan"}
+
+func (o Objective) String() string { return objectiveNames[o] }
+
+// Set parses wait or makespan, so an Objective can be a flag.Value.
+func (o *Objective) Set(s string) error {
+	for objective, name := range objectiveNames {
+		if name == s {
+			*o = objective
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: unknown objective %q", ErrInvalidArgs, s)
+}
+
+// of is the value of o for r.
+func (o Objective) of(r ScheduleResult) float64 {
+	if o == MakespanObjective {
+		return float64(r.Makespan) / float64(max(1, r.Resolution))
+	}
+	return r.AvgWait
+}
+
+// Optimality compares a schedule's Value for an Objective with the Optimum over every
+// non-preemptive single-CPU schedule of the same processes, one of which runs them in Order.
+type Optimality struct {
+	Objective Objective
+	Value     float64
+	Optimum   float64
+	Order     []string
+}
+
+// Optimal reports whether the schedule is as good as the best non-preemptive one. A preemptive
+// schedule can beat it, in which case Gap is negative.
+func (o Optimality) Optimal() bool { return o.Gap() <= 1e-9 }
+
+// Gap is how far the schedule's value is above the optimum.
+func (o Optimality) Gap() float64 { return o.Value - o.Optimum }
+
+// CheckOptimal compares r, a schedule of processes, with the best non-preemptive schedule of
+// processes under objective. It tries every ordering that runs each process after its
+// prerequisites, starting each as soon as the CPU is free and the process has arrived, which
+// covers every non-preemptive schedule worth considering. It returns an error rather than
+// enumerate more than MaxOptimalityProcesses processes.
+func CheckOptimal(r ScheduleResult, processes []Process, objective Objective) (Optimality, error) {
+	if len(processes) > MaxOptimalityProcesses {
+		return Optimality{}, fmt.Errorf("%w: %d processes is more than the %d CheckOptimal can enumerate",
+			ErrInvalidArgs, len(processes), MaxOptimalityProcesses)
+	}
+	o := Optimality{Objective: objective, Value: objective.of(r)}
+	var order []int
+	o.Optimum, order = bestOrder(processes, nil, objective)
+	for _, i := range order {
+		o.Order = append(o.Order, processes[i].ProcessID)
+	}
+	return o, nil
+}
+
+// bestOrder returns the least value of objective over the non-preemptive schedules of
+// processes that run the processes at the indices in prefix first, in that order, and the order
+// of the processes in one of them.
+func bestOrder(processes []Process, prefix []int, objective Objective) (float64, []int) {
+	index := make(map[string]int, len(processes))
+	for i, p := range processes {
+		index[p.ProcessID] = i
+	}
+
+	var (
+		best      = math.Inf(1)
+		found     []int // the order of the best schedule so far.
+		done      = make([]bool, len(processes))
+		order     = append(make([]int, 0, len(processes)), prefix...)
+		now, wait int64
+	)
+	for _, i := range prefix {
+		start := max(now, processes[i].ArrivalTime)
+		done[i] = true
+		now, wait = start+processes[i].BurstDuration, wait+start-processes[i].ArrivalTime
+	}
+	// try extends order, whose last process completes at now with total wait so far, and
+	// keeps the best complete ordering. Both objectives only grow as processes are added, so a
+	// partial ordering already at the optimum is abandoned.
+	var try func(now, wait int64)
+	try = func(now, wait int64) {
+		value := float64(now)
+		if objective == AvgWaitObjective {
+			value = float64(wait) / float64(max(1, int64(len(processes))))
+		}
+		if value >= best {
+			return
+		}
+		if len(order) == len(processes) {
+			best = value
+			found = append(found[:0], order...)
+			return
+		}
+	next:
+		for i, p := range processes {
+			if done[i] {
+				continue
+			}
+			for _, dep := range p.DependsOn {
+				if j, ok := index[dep]; ok && !done[j] {
+					continue next
+				}
+			}
+			start := max(now, p.ArrivalTime)
+			done[i] = true
+			order = append(order, i)
+			try(start+p.BurstDuration, wait+start-p.ArrivalTime)
+			order = order[:len(order)-1]
+			done[i] = false
+		}
+	}
+	try(now, wait)
+	return best, found
+}
+
+// Decision is one dispatch reviewed by Coach: at Time the schedule ran Chosen, after which the
+// least average wait still possible was ChosenWait. If running another process that was ready
+// then instead would have allowed a lower BestWait, Better names it; otherwise Better is empty
+// and BestWait is ChosenWait.
+type Decision struct {
+	Time       int64
+	Chosen     string
+	ChosenWait float64
+	Better     string
+	BestWait   float64
+}
+
+// Coach reviews each dispatch of r, a non-preemptive schedule on one CPU in whole time units,
+// for a student: it compares the least average wait possible after the process r ran with the
+// least possible after each other ready process, one that had arrived by then and whose
+// prerequisites had completed, and reports the best of them where r's choice was not. It
+// returns an error for a preemptive or multi-core schedule, or rather than enumerate more than
+// MaxOptimalityProcesses processes.
+func Coach(r ScheduleResult) ([]Decision, error) {
+	if len(r.Rows) > MaxOptimalityProcesses {
+		return nil, fmt.Errorf("%w: %d processes is more than the %d Coach can enumerate",
+			ErrInvalidArgs, len(r.Rows), MaxOptimalityProcesses)
+	}
+	if r.Resolution > 1 || r.Cores > 1 {
+		return nil, fmt.Errorf("%w: Coach needs a single-CPU schedule in whole time units", ErrInvalidArgs)
+	}
+	processes := make([]Process, len(r.Rows))
+	index := make(map[string]int, len(r.Rows))
+	for i, row := range r.Rows {
+		processes[i] = row.Process
+		index[row.Process.ProcessID] = i
+	}
+
+	var (
+		decisions []Decision
+		prefix    []int
+		done      = make([]bool, len(processes))
+	)
+	for _, slice := range r.Gantt {
+		chosen, ok := index[slice.PID]
+		if !ok || done[chosen] {
+			return nil, fmt.Errorf("%w: %s runs more than once, so the schedule is preemptive", ErrInvalidArgs, slice.PID)
+		}
+		d := Decision{Time: slice.Start, Chosen: slice.PID}
+		d.ChosenWait, _ = bestOrder(processes, append(prefix, chosen), AvgWaitObjective)
+		d.BestWait = d.ChosenWait
+	candidates:
+		for i, p := range processes {
+			if done[i] || i == chosen || p.ArrivalTime > slice.Start {
+				continue
+			}
+			for _, dep := range p.DependsOn {
+				if j, ok := index[dep]; ok && !done[j] {
+					continue candidates
+				}
+			}
+			if wait, _ := bestOrder(processes, append(prefix, i), AvgWaitObjective); wait < d.BestWait-1e-9 {
+				d.Better, d.BestWait = p.ProcessID, wait
+			}
+		}
+		decisions = append(decisions, d)
+		prefix = append(prefix, chosen)
+		done[chosen] = true
+	}
+	return decisions, nil
+}
+
+// outputCoaching writes each of decisions, and for those that were not the best, the process
+// that would have done better.
+func outputCoaching(w io.Writer, decisions []Decision) {
+	_, _ = fmt.Fprintln(w, "Coaching (average wait)")
+	for _, d := range decisions {
+		if d.Better == "" {
+			_, _ = fmt.Fprintf(w, "  At %d: ran %s, the best choice\n", d.Time, d.Chosen)
+			continue
+		}
+		_, _ = fmt.Fprintf(w, "  At %d: ran %s; running %s instead allows an average wait of %.2f, not %.2f\n",
+			d.Time, d.Chosen, d.Better, d.BestWait, d.ChosenWait)
+	}
+}
+
+// outputOptimality writes whether o's schedule is optimal and, if not, how far off it is.
+func outputOptimality(w io.Writer, o Optimality) {
+	_, _ = fmt.Fprintf(w, "Optimal %s: %.2f (order %v); this schedule: %.2f", o.Objective, o.Optimum, o.Order, o.Value)
+	switch {
+	case o.Optimal() && o.Gap() < -1e-9:
+		_, _ = fmt.Fprintln(w, ", better by preempting")
+	case o.Optimal():
+		_, _ = fmt.Fprintln(w, ", optimal")
+	case o.Optimum > 0:
+		_, _ = fmt.Fprintf(w, ", off by %.2f (%.2f%%)\n", o.Gap(), 100*o.Gap()/o.Optimum)
+	default:
+		_, _ = fmt.Fprintf(w, ", off by %.2f\n", o.Gap())
+	}
+}
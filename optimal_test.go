@@ -0,0 +1,174 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCheckOptimal(t *testing.T) {
+	t.Parallel()
+	// FCFS waits 0, 9 and 17 and SJF 0, 10 and 8. Leaving the CPU idle for P2, then running P1
+	// and P0, waits only 12, 2 and 0, which no work-conserving scheduler can do.
+	processes := []Process{
+		{ProcessID: "P0", ArrivalTime: 0, BurstDuration: 10},
+		{ProcessID: "P1", ArrivalTime: 1, BurstDuration: 9},
+		{ProcessID: "P2", ArrivalTime: 2, BurstDuration: 1},
+	}
+	tests := []struct {
+		name        string
+		s           Scheduler
+		processes   []Process
+		objective   Objective
+		wantOptimum float64
+		wantOrder   []string
+		wantGap     float64
+	}{
+		{name: "fcfs wait", s: FCFS{}, processes: processes, objective: AvgWaitObjective, wantOptimum: 14.0 / 3, wantOrder: []string{"P2", "P1", "P0"}, wantGap: 4},
+		{name: "sjf wait", s: SJF{}, processes: processes, objective: AvgWaitObjective, wantOptimum: 14.0 / 3, wantOrder: []string{"P2", "P1", "P0"}, wantGap: 4.0 / 3},
+		{name: "fcfs makespan", s: FCFS{}, processes: processes, objective: MakespanObjective, wantOptimum: 20, wantOrder: []string{"P0", "P1", "P2"}},
+		// SRTF waits 1, 10 and 0 by preempting P0 for P2.
+		{name: "srtf beats it", s: SRTF{}, processes: processes, objective: AvgWaitObjective, wantOptimum: 14.0 / 3, wantOrder: []string{"P2", "P1", "P0"}, wantGap: -1},
+		{
+			name: "waits for an arrival",
+			s:    FCFS{},
+			processes: []Process{
+				{ProcessID: "long", ArrivalTime: 0, BurstDuration: 10},
+				{ProcessID: "short", ArrivalTime: 1, BurstDuration: 1},
+			},
+			// Idling until short arrives waits 0 + 2 instead of 0 + 9.
+			objective:   AvgWaitObjective,
+			wantOptimum: 1,
+			wantOrder:   []string{"short", "long"},
+			wantGap:     3.5,
+		},
+		{
+			name: "respects dependencies",
+			s:    FCFS{},
+			processes: []Process{
+				{ProcessID: "P0", ArrivalTime: 0, BurstDuration: 5},
+				{ProcessID: "P1", ArrivalTime: 0, BurstDuration: 1, DependsOn: []string{"P0"}},
+			},
+			objective:   AvgWaitObjective,
+			wantOptimum: 2.5,
+			wantOrder:   []string{"P0", "P1"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			o, err := CheckOptimal(tt.s.Schedule(tt.processes), tt.processes, tt.objective)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if math.Abs(o.Optimum-tt.wantOptimum) > 1e-9 || math.Abs(o.Gap()-tt.wantGap) > 1e-9 {
+				t.Errorf("optimum %v, gap %v, want %v and %v", o.Optimum, o.Gap(), tt.wantOptimum, tt.wantGap)
+			}
+			if diff := cmp.Diff(o.Order, tt.wantOrder); diff != "" {
+				t.Errorf(diff)
+			}
+			if o.Optimal() != (tt.wantGap <= 0) {
+				t.Errorf("Optimal() = %v with gap %v", o.Optimal(), o.Gap())
+			}
+		})
+	}
+
+	if _, err := CheckOptimal(ScheduleResult{}, GenerateProcesses(MaxOptimalityProcesses+1, 1), AvgWaitObjective); !errors.Is(err, ErrInvalidArgs) {
+		t.Errorf("too many processes: error %v, want %v", err, ErrInvalidArgs)
+	}
+	// The largest allowed workload is still quick to enumerate, and SJF is never better than
+	// the optimum.
+	many := GenerateProcesses(MaxOptimalityProcesses, 1)
+	if o, err := CheckOptimal(SJF{}.Schedule(many), many, AvgWaitObjective); err != nil || o.Gap() < 0 {
+		t.Errorf("%d processes: %+v, %v", len(many), o, err)
+	}
+}
+
+func Test_outputOptimality(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		o    Optimality
+		want string
+	}{
+		{o: Optimality{Objective: AvgWaitObjective, Value: 2, Optimum: 2, Order: []string{"A", "B"}}, want: "Optimal wait: 2.00 (order [A B]); this schedule: 2.00, optimal\n"},
+		{o: Optimality{Objective: MakespanObjective, Value: 12, Optimum: 10, Order: []string{"A"}}, want: "Optimal makespan: 10.00 (order [A]); this schedule: 12.00, off by 2.00 (20.00%)\n"},
+		{o: Optimality{Objective: AvgWaitObjective, Value: 1, Optimum: 0, Order: []string{"A"}}, want: "Optimal wait: 0.00 (order [A]); this schedule: 1.00, off by 1.00\n"},
+		{o: Optimality{Objective: AvgWaitObjective, Value: 1, Optimum: 2, Order: []string{"A"}}, want: "Optimal wait: 2.00 (order [A]); this schedule: 1.00, better by preempting\n"},
+	}
+	for _, tt := range tests {
+		var w bytes.Buffer
+		outputOptimality(&w, tt.o)
+		if w.String() != tt.want {
+			t.Errorf("got %q, want %q", w.String(), tt.want)
+		}
+	}
+}
+
+func TestCoach(t *testing.T) {
+	t.Parallel()
+	// FCFS runs P1 at 10 while P2, much shorter, is ready; SJF runs P2 there.
+	processes := []Process{
+		{ProcessID: "P0", ArrivalTime: 0, BurstDuration: 10},
+		{ProcessID: "P1", ArrivalTime: 1, BurstDuration: 9},
+		{ProcessID: "P2", ArrivalTime: 2, BurstDuration: 1},
+	}
+	approx := cmp.Comparer(func(a, b float64) bool { return math.Abs(a-b) < 1e-9 })
+	tests := []struct {
+		name string
+		s    Scheduler
+		want []Decision
+	}{
+		{
+			name: "fcfs",
+			s:    FCFS{},
+			want: []Decision{
+				{Time: 0, Chosen: "P0", ChosenWait: 6, BestWait: 6},
+				{Time: 10, Chosen: "P1", ChosenWait: 26.0 / 3, Better: "P2", BestWait: 6},
+				{Time: 19, Chosen: "P2", ChosenWait: 26.0 / 3, BestWait: 26.0 / 3},
+			},
+		},
+		{
+			name: "sjf",
+			s:    SJF{},
+			want: []Decision{
+				{Time: 0, Chosen: "P0", ChosenWait: 6, BestWait: 6},
+				{Time: 10, Chosen: "P2", ChosenWait: 6, BestWait: 6},
+				{Time: 11, Chosen: "P1", ChosenWait: 6, BestWait: 6},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := Coach(tt.s.Schedule(processes))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.want, got, approx); diff != "" {
+				t.Errorf(diff)
+			}
+		})
+	}
+
+	for _, s := range []Scheduler{RR{Quantum: 2}, MultiCore{Cores: 2}, RR{Quantum: 10, Resolution: 10}} {
+		if _, err := Coach(s.Schedule(processes)); !errors.Is(err, ErrInvalidArgs) {
+			t.Errorf("%s: error %v, want %v", s.Name(), err, ErrInvalidArgs)
+		}
+	}
+
+	var w bytes.Buffer
+	decisions, _ := Coach(FCFS{}.Schedule(processes))
+	outputCoaching(&w, decisions)
+	want := "Coaching (average wait)\n" +
+		"  At 0: ran P0, the best choice\n" +
+		"  At 10: ran P1; running P2 instead allows an average wait of 6.00, not 8.67\n" +
+		"  At 19: ran P2, the best choice\n"
+	if diff := cmp.Diff(want, w.String()); diff != "" {
+		t.Errorf(diff)
+	}
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"fmt"
+	"math"
+)
+
+// Hyperperiod returns the least common multiple of the periods of the periodic processes, the
+// time after which their releases repeat, or 0 if none of them is periodic.
+func Hyperperiod(processes []Process) (int64, error) {
+	var h int64
+	for _, p := range processes {
+		if p.Period <= 0 {
+			continue
+		}
+		if h == 0 {
+			h = p.Period
+			continue
+		}
+		a, b := h, p.Period
+		for b != 0 {
+			a, b = b, a%b
+		}
+		if h/a > math.MaxInt64/p.Period {
+			return 0, fmt.Errorf("%w: hyperperiod at %s", ErrOverflow, p.ProcessID)
+		}
+		h = h / a * p.Period
+	}
+	return h, nil
+}
+
+// ExpandPeriodic returns the jobs released by processes over hyperperiods hyperperiods. Job k of
+// a periodic process p is released at p.ArrivalTime + k*p.Period, is named p.ProcessID.k, and
+// has a Deadline at its next release; a process without a Period is released once, as given.
+// Jobs are listed process by process, and their DependsOn is dropped since a job of one
+// process has no single job of another to wait for.
+func ExpandPeriodic(processes []Process, hyperperiods int) ([]Process, error) {
+	h, err := Hyperperiod(processes)
+	if err != nil {
+		return nil, err
+	}
+	if h > 0 && int64(hyperperiods) > math.MaxInt64/h {
+		return nil, fmt.Errorf("%w: %d hyperperiods of %d", ErrOverflow, hyperperiods, h)
+	}
+	var jobs []Process
+	for _, p := range processes {
+		if p.Period <= 0 {
+			jobs = append(jobs, p)
+			continue
+		}
+		for k := int64(0); k < int64(hyperperiods)*h/p.Period; k++ {
+			job := p
+			job.ProcessID = fmt.Sprintf("%s.%d", p.ProcessID, k)
+			job.ArrivalTime = p.ArrivalTime + k*p.Period
+			job.Deadline = job.ArrivalTime + p.Period
+			job.DependsOn = nil
+			jobs = append(jobs, job)
+		}
+	}
+	return jobs, ValidateProcesses(jobs)
+}
+
+// PeriodMetrics are the averages, in time units, of the jobs released in one hyperperiod, and
+// how many of them completed after their deadline.
+type PeriodMetrics struct {
+	Jobs           int
+	AvgWait        float64
+	AvgTurnaround  float64
+	DeadlineMisses int
+}
+
+// SteadyState is a periodic workload run for len(Periods) hyperperiods of Hyperperiod time
+// units. The first Warmup periods are left out of AvgWait, AvgTurnaround and DeadlineMisses,
+// which are the means and total over the rest.
+type SteadyState struct {
+	Result         ScheduleResult
+	Hyperperiod    int64
+	Warmup         int
+	Periods        []PeriodMetrics
+	AvgWait        float64
+	AvgTurnaround  float64
+	DeadlineMisses int
+}
+
+// RunPeriodic runs s on the jobs ExpandPeriodic releases from processes over hyperperiods
+// hyperperiods and reports the metrics of each, averaged over those after the first warmup. A
+// job counts toward the hyperperiod it is released in, so a job finishing in the next one still
+// belongs to its own. It returns an error unless at least one hyperperiod is left after the
+// warmup and some process is periodic.
+func RunPeriodic(s Scheduler, processes []Process, hyperperiods, warmup int) (SteadyState, error) {
+	if warmup < 0 || hyperperiods <= warmup {
+		return SteadyState{}, fmt.Errorf("%w: %d hyperperiods leave none after a warmup of %d", ErrInvalidArgs, hyperperiods, warmup)
+	}
+	h, err := Hyperperiod(processes)
+	if err != nil {
+		return SteadyState{}, err
+	}
+	if h == 0 {
+		return SteadyState{}, fmt.Errorf("%w: no process has a period", ErrInvalidArgs)
+	}
+	jobs, err := ExpandPeriodic(processes, hyperperiods)
+	if err != nil {
+		return SteadyState{}, err
+	}
+
+	r := s.Schedule(jobs)
+	ss := SteadyState{Result: r, Hyperperiod: h, Warmup: warmup, Periods: make([]PeriodMetrics, hyperperiods)}
+	scale := float64(max(1, r.Resolution))
+	for _, row := range r.Rows {
+		k := min(int(row.Process.ArrivalTime/h), hyperperiods-1)
+		m := &ss.Periods[k]
+		m.Jobs++
+		m.AvgWait += float64(row.Waiting) / scale
+		m.AvgTurnaround += float64(row.Turnaround) / scale
+		if missesDeadline(row, r.Resolution) {
+			m.DeadlineMisses++
+		}
+	}
+	for k := range ss.Periods {
+		m := &ss.Periods[k]
+		if m.Jobs > 0 {
+			m.AvgWait /= float64(m.Jobs)
+			m.AvgTurnaround /= float64(m.Jobs)
+		}
+		if k >= warmup {
+			ss.AvgWait += m.AvgWait / float64(hyperperiods-warmup)
+			ss.AvgTurnaround += m.AvgTurnaround / float64(hyperperiods-warmup)
+			ss.DeadlineMisses += m.DeadlineMisses
+		}
+	}
+	return ss, nil
+}
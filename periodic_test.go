@@ -0,0 +1,93 @@
+package main
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestRunPeriodic(t *testing.T) {
+	t.Parallel()
+	// A and B repeat every 12. The one-off boot process delays the first hyperperiod's jobs,
+	// making A.1 miss its deadline, and the later hyperperiods are identical.
+	processes := []Process{
+		{ProcessID: "A", BurstDuration: 1, Period: 4},
+		{ProcessID: "B", BurstDuration: 2, Period: 6},
+		{ProcessID: "boot", BurstDuration: 5},
+	}
+	ss, err := RunPeriodic(FCFS{}, processes, 3, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []PeriodMetrics{
+		{Jobs: 6, AvgWait: 14.0 / 6, AvgTurnaround: 26.0 / 6, DeadlineMisses: 1},
+		{Jobs: 5, AvgWait: 0.2, AvgTurnaround: 1.6},
+		{Jobs: 5, AvgWait: 0.2, AvgTurnaround: 1.6},
+	}
+	if diff := cmp.Diff(want, ss.Periods, cmpopts.EquateApprox(0, 1e-9)); diff != "" {
+		t.Errorf(diff)
+	}
+	if ss.Hyperperiod != 12 || math.Abs(ss.AvgWait-0.2) > 1e-9 || math.Abs(ss.AvgTurnaround-1.6) > 1e-9 || ss.DeadlineMisses != 0 {
+		t.Errorf("hyperperiod %d, steady state wait %v, turnaround %v, %d misses, want 12, 0.2, 1.6 and 0",
+			ss.Hyperperiod, ss.AvgWait, ss.AvgTurnaround, ss.DeadlineMisses)
+	}
+}
+
+func TestRunPeriodic_Errors(t *testing.T) {
+	t.Parallel()
+	periodic := []Process{{ProcessID: "A", BurstDuration: 1, Period: 4}}
+	tests := []struct {
+		name         string
+		processes    []Process
+		hyperperiods int
+		warmup       int
+		wantErr      error
+	}{
+		{name: "all warmup", processes: periodic, hyperperiods: 2, warmup: 2, wantErr: ErrInvalidArgs},
+		{name: "negative warmup", processes: periodic, hyperperiods: 2, warmup: -1, wantErr: ErrInvalidArgs},
+		{name: "not periodic", processes: []Process{{ProcessID: "A", BurstDuration: 1}}, hyperperiods: 2, wantErr: ErrInvalidArgs},
+		{
+			name: "hyperperiod overflows",
+			processes: []Process{
+				{ProcessID: "A", BurstDuration: 1, Period: math.MaxInt64 / 2},
+				{ProcessID: "B", BurstDuration: 1, Period: math.MaxInt64/2 - 1},
+			},
+			hyperperiods: 1,
+			wantErr:      ErrOverflow,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if _, err := RunPeriodic(FCFS{}, tt.processes, tt.hyperperiods, tt.warmup); !errors.Is(err, tt.wantErr) {
+				t.Errorf("got %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestExpandPeriodic(t *testing.T) {
+	t.Parallel()
+	jobs, err := ExpandPeriodic([]Process{
+		{ProcessID: "A", ArrivalTime: 1, BurstDuration: 1, Period: 2},
+		{ProcessID: "B", ArrivalTime: 0, BurstDuration: 1, Period: 4, DependsOn: []string{"A"}},
+	}, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Process{
+		{ProcessID: "A.0", ArrivalTime: 1, BurstDuration: 1, Period: 2, Deadline: 3},
+		{ProcessID: "A.1", ArrivalTime: 3, BurstDuration: 1, Period: 2, Deadline: 5},
+		{ProcessID: "A.2", ArrivalTime: 5, BurstDuration: 1, Period: 2, Deadline: 7},
+		{ProcessID: "A.3", ArrivalTime: 7, BurstDuration: 1, Period: 2, Deadline: 9},
+		{ProcessID: "B.0", ArrivalTime: 0, BurstDuration: 1, Period: 4, Deadline: 4},
+		{ProcessID: "B.1", ArrivalTime: 4, BurstDuration: 1, Period: 4, Deadline: 8},
+	}
+	if diff := cmp.Diff(want, jobs); diff != "" {
+		t.Errorf(diff)
+	}
+}
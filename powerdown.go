@@ -0,0 +1,134 @@
+package main
+
+import (
+	"fmt"
+	"io"
+)
+
+// PowerDown is a Scheduler that models a CPU that powers down to save energy: once it has idled
+// for longer than Threshold it sleeps, drawing no power, until the next process arrives, and
+// then takes WakeLatency to wake before anything can run. The processes that arrive while it
+// wakes wait for it, and Scheduler schedules them as if they arrived once it was awake. Both
+// times are in time units. On several cores the CPU sleeps only while every core is idle, and
+// only arrivals wake it, so under RRIO a process returning from I/O to a sleeping CPU runs at
+// once.
+type PowerDown struct {
+	Scheduler   Scheduler
+	Threshold   int64
+	WakeLatency int64
+}
+
+func (s PowerDown) Name() string { return s.Scheduler.Name() }
+
+func (s PowerDown) Schedule(processes []Process) ScheduleResult {
+	return s.scheduleClocked(processes, nil)
+}
+
+// scheduleClocked reschedules with each wake in turn, from the earliest: a wake only delays
+// what comes after it, so the schedule up to each earlier wake stays the same.
+func (s PowerDown) scheduleClocked(processes []Process, clock *decisionClock) ScheduleResult {
+	delayed := append([]Process(nil), processes...)
+	woken := make(map[int64]bool) // the ticks at which the CPU finished waking.
+	for {
+		r := scheduleWith(s.Scheduler, delayed, clock)
+		scale := max(1, r.Resolution)
+		start, ok := s.nextWake(r, woken)
+		if !ok {
+			return s.restoreArrivals(r, processes, delayed)
+		}
+		awake := start + s.WakeLatency*scale
+		woken[start], woken[awake] = true, true
+		for i := range delayed {
+			if t := delayed[i].ArrivalTime * scale; delayed[i].BurstDuration > 0 && t >= start && t < awake {
+				delayed[i].ArrivalTime = awake / scale
+			}
+		}
+	}
+}
+
+// nextWake returns the tick at which the CPU first wakes in r from idling longer than the
+// Threshold, skipping the wakes already modeled.
+func (s PowerDown) nextWake(r ScheduleResult, woken map[int64]bool) (int64, bool) {
+	var busyTill int64
+	for _, slice := range r.Gantt {
+		if slice.Start == slice.Stop {
+			continue
+		}
+		if slice.Start-busyTill > s.Threshold*max(1, r.Resolution) && !woken[slice.Start] {
+			return slice.Start, true
+		}
+		busyTill = max(busyTill, slice.Stop)
+	}
+	return 0, false
+}
+
+// restoreArrivals returns r, a schedule of delayed, as the schedule of processes: each process
+// delayed by a wake gets its own arrival back, and the delay counts as waiting.
+func (s PowerDown) restoreArrivals(r ScheduleResult, processes, delayed []Process) ScheduleResult {
+	scale := max(1, r.Resolution)
+	var total int64 // in ticks.
+	for i := range r.Rows {
+		d := (delayed[i].ArrivalTime - processes[i].ArrivalTime) * scale
+		r.Rows[i].Process = processes[i]
+		r.Rows[i].Waiting += d
+		r.Rows[i].Turnaround += d
+		total += d
+	}
+	if total == 0 {
+		return r
+	}
+	n := float64(len(r.Rows))
+	r.AvgWait += float64(total) / float64(scale) / n
+	r.AvgTurnaround += float64(total) / float64(scale) / n
+	if r.Makespan > 0 {
+		r.AvgQueueLength += float64(total) / float64(r.Makespan)
+	}
+	return r
+}
+
+// PowerDownReport weighs what powering down saved against what waking cost: the CPU slept
+// Sleeps times for Asleep time units in all, saving Saved energy, and each wake took the
+// WakeLatency, WakeTime in all, raising the average wait by ExtraWait over never powering down.
+type PowerDownReport struct {
+	Sleeps    int
+	Asleep    float64
+	Saved     float64
+	WakeTime  float64
+	ExtraWait float64
+}
+
+// PowerDownSavings schedules processes with s and with s.Scheduler alone and reports the
+// difference, with an idle CPU that stays on drawing idlePower.
+func PowerDownSavings(s PowerDown, processes []Process, idlePower float64) PowerDownReport {
+	r := s.Schedule(processes)
+	scale := max(1, r.Resolution)
+	var (
+		report   PowerDownReport
+		busyTill int64
+	)
+	for _, slice := range r.Gantt {
+		if slice.Start == slice.Stop {
+			continue
+		}
+		// Every idle interval longer than the Threshold ended in a wake, which lengthened it.
+		if gap := slice.Start - busyTill - s.WakeLatency*scale; gap > s.Threshold*scale {
+			report.Sleeps++
+			report.Asleep += float64(gap-s.Threshold*scale) / float64(scale)
+		}
+		busyTill = max(busyTill, slice.Stop)
+	}
+	report.Saved = idlePower * report.Asleep
+	report.WakeTime = float64(int64(report.Sleeps) * s.WakeLatency)
+	report.ExtraWait = r.AvgWait - s.Scheduler.Schedule(processes).AvgWait
+	return report
+}
+
+// outputPowerDown writes the energy powering down saved and the time waking cost.
+func outputPowerDown(w io.Writer, p PowerDownReport) {
+	if p.Sleeps == 0 {
+		_, _ = fmt.Fprintln(w, "Power down: never, no idle interval is longer than the threshold")
+		return
+	}
+	_, _ = fmt.Fprintf(w, "Power down: slept %d time(s) for %.2f, saving %.2f energy; waking took %.2f, adding %.2f to the average wait\n",
+		p.Sleeps, p.Asleep, p.Saved, p.WakeTime, p.ExtraWait)
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPowerDown(t *testing.T) {
+	t.Parallel()
+	// The CPU idles from 2 to 6, sleeping from 4, and wakes from 6 to 8 while B and C arrive.
+	processes := []Process{
+		{ProcessID: "A", ArrivalTime: 0, BurstDuration: 2},
+		{ProcessID: "B", ArrivalTime: 6, BurstDuration: 2},
+		{ProcessID: "C", ArrivalTime: 7, BurstDuration: 1},
+		{ProcessID: "D", ArrivalTime: 8, BurstDuration: 1},
+	}
+	tests := []struct {
+		name       string
+		s          PowerDown
+		wantWaits  []int64
+		wantReport PowerDownReport
+	}{
+		{
+			name:       "fcfs",
+			s:          PowerDown{Scheduler: FCFS{}, Threshold: 2, WakeLatency: 2},
+			wantWaits:  []int64{0, 2, 3, 3},
+			wantReport: PowerDownReport{Sleeps: 1, Asleep: 2, Saved: 0.2, WakeTime: 2, ExtraWait: 1.5},
+		},
+		{
+			name:       "rr in ticks",
+			s:          PowerDown{Scheduler: RR{Quantum: 10, Resolution: 10}, Threshold: 2, WakeLatency: 2},
+			wantWaits:  []int64{0, 40, 20, 20},
+			wantReport: PowerDownReport{Sleeps: 1, Asleep: 2, Saved: 0.2, WakeTime: 2, ExtraWait: 1.5},
+		},
+		{
+			// A arrives at 0 without the CPU idling; the CPU sleeps from 2, and C arrives once
+			// it is awake.
+			name:       "no threshold",
+			s:          PowerDown{Scheduler: FCFS{}, Threshold: 0, WakeLatency: 1},
+			wantWaits:  []int64{0, 1, 2, 2},
+			wantReport: PowerDownReport{Sleeps: 1, Asleep: 4, Saved: 0.4, WakeTime: 1, ExtraWait: 0.75},
+		},
+		{
+			name:      "never idle long enough",
+			s:         PowerDown{Scheduler: FCFS{}, Threshold: 4, WakeLatency: 2},
+			wantWaits: []int64{0, 0, 1, 1},
+		},
+	}
+	approx := cmp.Comparer(func(a, b float64) bool { return math.Abs(a-b) < 1e-9 })
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			r := tt.s.Schedule(processes)
+			var waits []int64
+			for i, row := range r.Rows {
+				waits = append(waits, row.Waiting)
+				if diff := cmp.Diff(processes[i], row.Process); diff != "" {
+					t.Errorf("row %d: %s", i, diff)
+				}
+			}
+			if diff := cmp.Diff(tt.wantWaits, waits); diff != "" {
+				t.Errorf(diff)
+			}
+			if littles := r.Throughput * r.AvgWait; math.Abs(r.AvgQueueLength-littles) > 1e-9 {
+				t.Errorf("measured L = %v, Little's Law L = %v", r.AvgQueueLength, littles)
+			}
+			if diff := cmp.Diff(tt.wantReport, PowerDownSavings(tt.s, processes, 0.1), approx); diff != "" {
+				t.Errorf(diff)
+			}
+		})
+	}
+
+	var w bytes.Buffer
+	outputPowerDown(&w, PowerDownReport{Sleeps: 1, Asleep: 2, Saved: 0.2, WakeTime: 2, ExtraWait: 1.5})
+	want := "Power down: slept 1 time(s) for 2.00, saving 0.20 energy; waking took 2.00, adding 1.50 to the average wait\n"
+	if diff := cmp.Diff(want, w.String()); diff != "" {
+		t.Errorf(diff)
+	}
+}
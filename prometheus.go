@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// DefaultMetricPrefix starts the name of every metric outputPrometheus writes unless another is
+// given.
+const DefaultMetricPrefix = "scheduler"
+
+// metricPrefix is the form of a Prometheus metric name.
+var metricPrefix = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:]*$`)
+
+// ValidateMetricPrefix checks that prefix can start a Prometheus metric name.
+func ValidateMetricPrefix(prefix string) error {
+	if !metricPrefix.MatchString(prefix) {
+		return fmt.Errorf("%w: metric prefix %q is not a Prometheus metric name", ErrInvalidArgs, prefix)
+	}
+	return nil
+}
+
+// outputPrometheus writes r's summary metrics in the Prometheus text exposition format, each a
+// gauge named prefix_metric and labeled with algo, so a simulation service can be scraped. Times
+// are in time units. prefix must pass ValidateMetricPrefix.
+func outputPrometheus(w io.Writer, prefix, algo string, r ScheduleResult) {
+	_, total := CoreUtilization(r)
+	label := fmt.Sprintf(`{algorithm="%s"}`, strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(algo))
+	metrics := []struct {
+		name, help string
+		value      float64
+	}{
+		{"avg_wait", "Average time a process waited, in time units.", r.AvgWait},
+		{"avg_turnaround", "Average time from a process's arrival to its completion, in time units.", r.AvgTurnaround},
+		{"throughput", "Processes completed per time unit.", r.Throughput},
+		{"utilization", "Fraction of the makespan the CPUs were busy.", total.Utilization()},
+		{"context_switches", "Times a CPU switched from one process to another.", float64(r.ContextSwitches)},
+		{"makespan", "Time the last process completed, in time units.", float64(r.Makespan) / float64(max(1, r.Resolution))},
+	}
+	for _, m := range metrics {
+		name := prefix + "_" + m.name
+		_, _ = fmt.Fprintf(w, "# HELP %s %s\n", name, m.help)
+		_, _ = fmt.Fprintf(w, "# TYPE %s gauge\n", name)
+		_, _ = fmt.Fprintf(w, "%s%s %s\n", name, label, strconv.FormatFloat(m.value, 'g', -1, 64))
+	}
+}
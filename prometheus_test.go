@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_outputPrometheus(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: "P0", ArrivalTime: 0, BurstDuration: 3},
+		{ProcessID: "P1", ArrivalTime: 5, BurstDuration: 1},
+	}
+	var w bytes.Buffer
+	outputPrometheus(&w, "sim", `rr "q=2"`, RR{Quantum: 2}.Schedule(processes))
+	want := []string{
+		"# HELP sim_avg_wait Average time a process waited, in time units.",
+		"# TYPE sim_avg_wait gauge",
+		`sim_avg_wait{algorithm="rr \"q=2\""} 0`,
+		"# HELP sim_avg_turnaround Average time from a process's arrival to its completion, in time units.",
+		"# TYPE sim_avg_turnaround gauge",
+		`sim_avg_turnaround{algorithm="rr \"q=2\""} 2`,
+		"# HELP sim_throughput Processes completed per time unit.",
+		"# TYPE sim_throughput gauge",
+		`sim_throughput{algorithm="rr \"q=2\""} 0.3333333333333333`,
+		"# HELP sim_utilization Fraction of the makespan the CPUs were busy.",
+		"# TYPE sim_utilization gauge",
+		`sim_utilization{algorithm="rr \"q=2\""} 0.6666666666666666`,
+		"# HELP sim_context_switches Times a CPU switched from one process to another.",
+		"# TYPE sim_context_switches gauge",
+		`sim_context_switches{algorithm="rr \"q=2\""} 1`,
+		"# HELP sim_makespan Time the last process completed, in time units.",
+		"# TYPE sim_makespan gauge",
+		`sim_makespan{algorithm="rr \"q=2\""} 6`,
+	}
+	if diff := cmp.Diff(strings.Join(want, "\n")+"\n", w.String()); diff != "" {
+		t.Errorf(diff)
+	}
+}
+
+func TestValidateMetricPrefix(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		prefix string
+		ok     bool
+	}{
+		{prefix: DefaultMetricPrefix, ok: true},
+		{prefix: "job:sim_2", ok: true},
+		{prefix: ""},
+		{prefix: "2sim"},
+		{prefix: "sim-load"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.prefix, func(t *testing.T) {
+			t.Parallel()
+			err := ValidateMetricPrefix(tt.prefix)
+			if tt.ok && err != nil || !tt.ok && !errors.Is(err, ErrInvalidArgs) {
+				t.Errorf("ValidateMetricPrefix() = %v", err)
+			}
+		})
+	}
+}
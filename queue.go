@@ -0,0 +1,253 @@
+package main
+
+import (
+	"container/heap"
+	"slices"
+)
+
+// picker is the ready queue of the non-preemptive simulation: it holds the indices of the
+// processes ready to run and chooses the next of them to run.
+type picker interface {
+	enqueue(i int)
+	next(now int64) int
+	Len() int
+}
+
+// readyQueue is a ready queue of process indices, kept as a heap so that the next process to
+// run is selected in O(log n). Indices are ordered by less; ties go to the index enqueued first.
+type readyQueue struct {
+	items []queued
+	less  func(a, b int) bool
+	seq   int
+}
+
+type queued struct {
+	index int
+	seq   int
+}
+
+func newReadyQueue(less func(a, b int) bool) *readyQueue {
+	return &readyQueue{less: less}
+}
+
+// enqueue adds process index i to the queue.
+func (q *readyQueue) enqueue(i int) {
+	heap.Push(q, queued{index: i, seq: q.seq})
+	q.seq++
+}
+
+// dequeue removes and returns the next process index to run.
+func (q *readyQueue) dequeue() int {
+	return heap.Pop(q).(queued).index
+}
+
+// next is dequeue, so a readyQueue is a picker.
+func (q *readyQueue) next(int64) int {
+	return q.dequeue()
+}
+
+// peek returns the next process index to run without removing it.
+func (q *readyQueue) peek() int {
+	return q.items[0].index
+}
+
+//region heap.Interface
+
+func (q *readyQueue) Len() int { return len(q.items) }
+
+func (q *readyQueue) Less(i, j int) bool {
+	a, b := q.items[i], q.items[j]
+	if q.less(a.index, b.index) {
+		return true
+	}
+	if q.less(b.index, a.index) {
+		return false
+	}
+	return a.seq < b.seq
+}
+
+func (q *readyQueue) Swap(i, j int) { q.items[i], q.items[j] = q.items[j], q.items[i] }
+
+func (q *readyQueue) Push(x any) { q.items = append(q.items, x.(queued)) }
+
+func (q *readyQueue) Pop() any {
+	last := q.items[len(q.items)-1]
+	q.items = q.items[:len(q.items)-1]
+	return last
+}
+
+//endregion
+
+// lessQueue is a readyQueue of processes ordered by less, with pinned processes first.
+func lessQueue(processes []Process, less func(a, b Process) bool) *readyQueue {
+	return newReadyQueue(func(a, b int) bool {
+		if processes[a].Pinned != processes[b].Pinned {
+			return processes[a].Pinned
+		}
+		return less(processes[a], processes[b])
+	})
+}
+
+// firstPinned returns the position in queue of its first pinned process, or 0 if none is, for
+// the engines that otherwise run the head of a queue.
+func firstPinned(queue []int, processes []Process) int {
+	for n, i := range queue {
+		if processes[i].Pinned {
+			return n
+		}
+	}
+	return 0
+}
+
+// hasPinned reports whether any process in queue is pinned.
+func hasPinned(queue []int, processes []Process) bool {
+	return slices.ContainsFunc(queue, func(i int) bool { return processes[i].Pinned })
+}
+
+// eligible returns the processes in ready that a decision may choose between: if any besides
+// running are pinned, those, after running, which pinning does not preempt, and otherwise all
+// of ready. running is -1 when the CPU is free.
+func eligible(ready []int, processes []Process, running int) []int {
+	var pinned []int
+	for _, i := range ready {
+		if i != running && processes[i].Pinned {
+			pinned = append(pinned, i)
+		}
+	}
+	if len(pinned) == 0 {
+		return ready
+	}
+	if running >= 0 {
+		pinned = append([]int{running}, pinned...)
+	}
+	return pinned
+}
+
+// selectQueue is a picker that leaves the choice of the next process to a SelectFunc. Ready
+// processes are kept in the order they became ready, and if any of them are pinned only those
+// are offered.
+type selectQueue struct {
+	processes []Process
+	choose    SelectFunc
+	ready     []int
+}
+
+func (q *selectQueue) enqueue(i int) { q.ready = append(q.ready, i) }
+
+func (q *selectQueue) Len() int { return len(q.ready) }
+
+func (q *selectQueue) next(now int64) int {
+	var candidates []int // positions in q.ready.
+	for n, i := range q.ready {
+		if q.processes[i].Pinned {
+			candidates = append(candidates, n)
+		}
+	}
+	if len(candidates) == 0 {
+		for n := range q.ready {
+			candidates = append(candidates, n)
+		}
+	}
+	offered := make([]Process, len(candidates))
+	for k, n := range candidates {
+		offered[k] = q.processes[q.ready[n]]
+	}
+	k := q.choose(offered, now)
+	if k < 0 || k >= len(candidates) {
+		k = 0
+	}
+	n := candidates[k]
+	i := q.ready[n]
+	q.ready = append(q.ready[:n], q.ready[n+1:]...)
+	return i
+}
+
+// arrivals releases processes, in arrival order, once they have arrived and every process they
+// depend on has completed.
+type arrivals struct {
+	processes []Process
+	order     []int // process indices by arrival.
+	next      int   // the next process in order to arrive.
+	blocked   []int // arrived processes waiting for prerequisites, in arrival order.
+	done      map[string]bool
+	forced    map[int]bool // blocked processes released despite their prerequisites.
+}
+
+func newArrivals(processes []Process) *arrivals {
+	return &arrivals{
+		processes: processes,
+		order: sortedIndices(processes, func(a, b Process) bool {
+			return a.ArrivalTime < b.ArrivalTime
+		}),
+		done:   make(map[string]bool),
+		forced: make(map[int]bool),
+	}
+}
+
+// release calls admit for each process that has arrived by now and can run, in arrival order.
+func (a *arrivals) release(now int64, admit func(i int)) {
+	a.releaseArrived(now, true, admit)
+}
+
+// releaseBefore is release for the processes that arrived strictly before now.
+func (a *arrivals) releaseBefore(now int64, admit func(i int)) {
+	a.releaseArrived(now, false, admit)
+}
+
+func (a *arrivals) releaseArrived(now int64, atNow bool, admit func(i int)) {
+	for a.next < len(a.order) {
+		if t := a.processes[a.order[a.next]].ArrivalTime; t > now || t == now && !atNow {
+			break
+		}
+		a.blocked = append(a.blocked, a.order[a.next])
+		a.next++
+	}
+	kept := a.blocked[:0]
+	for _, i := range a.blocked {
+		if a.ready(i) {
+			admit(i)
+		} else {
+			kept = append(kept, i)
+		}
+	}
+	a.blocked = kept
+}
+
+func (a *arrivals) ready(i int) bool {
+	if a.forced[i] {
+		return true
+	}
+	for _, pid := range a.processes[i].DependsOn {
+		if !a.done[pid] {
+			return false
+		}
+	}
+	return true
+}
+
+// complete records that process i has completed, which may unblock the processes depending on it.
+func (a *arrivals) complete(i int) {
+	a.done[a.processes[i].ProcessID] = true
+}
+
+// nextArrival returns the arrival time of the next process yet to arrive.
+func (a *arrivals) nextArrival() (int64, bool) {
+	if a.next == len(a.order) {
+		return 0, false
+	}
+	return a.processes[a.order[a.next]].ArrivalTime, true
+}
+
+// idleUntil returns the time an idle CPU next has something to run: the next arrival. If
+// nothing is left to arrive, the blocked processes depend on each other or on processes that do
+// not exist, which ValidateProcesses rejects; the first is released anyway so a simulation
+// always finishes.
+func (a *arrivals) idleUntil(now int64) int64 {
+	if t, ok := a.nextArrival(); ok {
+		return t
+	}
+	if len(a.blocked) > 0 {
+		a.forced[a.blocked[0]] = true
+	}
+	return now
+}
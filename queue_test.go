@@ -0,0 +1,86 @@
+package main
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_readyQueue(t *testing.T) {
+	t.Parallel()
+	bursts := []int64{5, 1, 3, 1, 4}
+	q := newReadyQueue(func(a, b int) bool { return bursts[a] < bursts[b] })
+	for i := range bursts {
+		q.enqueue(i)
+	}
+	if got := q.peek(); got != 1 {
+		t.Errorf("peek() = %d, want 1", got)
+	}
+	var got []int
+	for q.Len() > 0 {
+		got = append(got, q.dequeue())
+	}
+	// Equal bursts leave in the order they were enqueued.
+	if diff := cmp.Diff(got, []int{1, 3, 2, 4, 0}); diff != "" {
+		t.Errorf(diff)
+	}
+}
+
+// rescanSJFPriority is the selection SJFPriority used before the ready queue: every decision
+// re-scans all processes for those available and sorts them.
+func rescanSJFPriority(processes []Process) {
+	var currentTime int64
+	done := make([]bool, len(processes))
+	for completed := 0; completed < len(processes); {
+		var available []int
+		for i, p := range processes {
+			if !done[i] && p.ArrivalTime <= currentTime {
+				available = append(available, i)
+			}
+		}
+		sort.Slice(available, func(i, j int) bool {
+			if processes[available[i]].BurstDuration == processes[available[j]].BurstDuration {
+				return processes[available[i]].Priority < processes[available[j]].Priority
+			}
+			return processes[available[i]].BurstDuration < processes[available[j]].BurstDuration
+		})
+		if len(available) == 0 {
+			currentTime++
+			continue
+		}
+		done[available[0]] = true
+		completed++
+		currentTime += processes[available[0]].BurstDuration
+	}
+}
+
+func BenchmarkSJFPrioritySelection(b *testing.B) {
+	processes := GenerateProcesses(2000, 1)
+	b.Run("heap", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			SJFPriority{}.Schedule(processes)
+		}
+	})
+	b.Run("rescan", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			rescanSJFPriority(processes)
+		}
+	})
+
+	// EDF keeps its waiting processes on the same heap, by deadline.
+	deadlines := append([]Process(nil), processes...)
+	for i := range deadlines {
+		deadlines[i].Deadline = deadlines[i].ArrivalTime + 3*deadlines[i].BurstDuration
+	}
+	b.Run("edf", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			EDF{}.Schedule(deadlines)
+		}
+	})
+	b.Run("edf 4 cores", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			EDF{Cores: 4}.Schedule(deadlines)
+		}
+	})
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// replHelp lists the commands RunREPL understands.
+const replHelp = `Commands:
+  add ID BURST ARRIVAL [PRIORITY]   add a process
+  list                              list the processes
+  algorithm NAME                    schedule with fcfs, sjf, sjfp, rr or a spec such as hrrn:preemptive
+  run                               schedule the processes and print the result
+  clear                             remove every process
+  help                              print this list
+  quit                              stop`
+
+// RunREPL reads commands from in, one per line, and writes their results to out: it builds up a
+// workload process by process and schedules it with the algorithm named algo, configured by cfg
+// as for RunFile, printing the result after each run. A command that fails prints the error and
+// the loop carries on. Blank lines and lines starting with # are skipped. It stops at quit or
+// the end of in, and returns an error only if reading in fails.
+func RunREPL(in io.Reader, out io.Writer, algo string, cfg SchedulerConfig) error {
+	s, title, err := schedulerNamed(algo, cfg)
+	if err != nil {
+		return err
+	}
+	var processes []Process
+	scanner := bufio.NewScanner(in)
+	for line := 1; ; line++ {
+		_, _ = fmt.Fprint(out, "> ")
+		if !scanner.Scan() {
+			break
+		}
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		switch cmd, args := strings.ToLower(fields[0]), fields[1:]; cmd {
+		case "add":
+			added, err := parseProcesses([][]string{args}, []int{line}, cfg.PriorityNames)
+			all := append(append([]Process(nil), processes...), added...)
+			if err == nil {
+				err = ValidateProcesses(all)
+			}
+			if err == nil {
+				err = ValidateResolution(all, cfg.Resolution)
+			}
+			if err == nil {
+				err = cfg.ValidatePriorities(added)
+			}
+			if err != nil {
+				_, _ = fmt.Fprintf(out, "error: %v\n", err)
+				continue
+			}
+			processes = append(processes, added...)
+		case "list":
+			outputProcessList(out, processes)
+		case "algorithm":
+			if len(args) != 1 {
+				_, _ = fmt.Fprintln(out, "error: algorithm takes one name")
+				continue
+			}
+			next, nextTitle, err := schedulerNamed(args[0], cfg)
+			if err != nil {
+				_, _ = fmt.Fprintf(out, "error: %v\n", err)
+				continue
+			}
+			s, title, algo = next, nextTitle, args[0]
+			_, _ = fmt.Fprintf(out, "Algorithm: %s\n", title)
+		case "run":
+			if len(processes) == 0 {
+				_, _ = fmt.Fprintln(out, "error: no processes to run")
+				continue
+			}
+			r := s.Schedule(processes)
+			if algo == sjfp.String() {
+				outputSJFPriority(out, title, r, RenderOptions{})
+			} else {
+				outputResult(out, title, r, RenderOptions{})
+				outputAlgorithmSummary(out, s, r)
+			}
+		case "clear":
+			processes = nil
+		case "help":
+			_, _ = fmt.Fprintln(out, replHelp)
+		case "quit", "exit":
+			return nil
+		default:
+			_, _ = fmt.Fprintf(out, "error: unknown command %q; try help\n", cmd)
+		}
+	}
+	_, _ = fmt.Fprintln(out)
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("%w: reading commands", err)
+	}
+	return nil
+}
+
+// outputProcessList writes processes one per line in the order they were added.
+func outputProcessList(w io.Writer, processes []Process) {
+	if len(processes) == 0 {
+		_, _ = fmt.Fprintln(w, "No processes")
+		return
+	}
+	for _, p := range processes {
+		_, _ = fmt.Fprintf(w, "%s: burst %d, arrival %d, priority %d\n", p.ProcessID, p.BurstDuration, p.ArrivalTime, p.Priority)
+	}
+}
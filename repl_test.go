@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRunREPL(t *testing.T) {
+	t.Parallel()
+	in := strings.NewReader(`# a comment, then a blank line
+
+add P1 5 0
+add P2 2 1 high
+add P3 x 1
+list
+algorithm sjf
+run
+algorithm hrrn:nonsense
+clear
+list
+run
+frobnicate
+quit
+add P4 1 0
+`)
+	var w bytes.Buffer
+	if err := RunREPL(in, &w, "fcfs", SchedulerConfig{}); err != nil {
+		t.Fatal(err)
+	}
+	out := w.String()
+	for _, want := range []string{
+		"invalid syntax: line 5: burst duration\n",
+		"> P1: burst 5, arrival 0, priority 0\nP2: burst 2, arrival 1, priority 1\n",
+		"Algorithm: Shortest-job-first\n",
+		"    Shortest-job-first\n",
+		"Average wait: 2.00\n",
+		`error: invalid args: hrrn: unknown parameter "nonsense"`,
+		"> No processes\n",
+		"error: no processes to run\n",
+		`error: unknown command "frobnicate"; try help`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output is missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "P4") {
+		t.Errorf("commands after quit ran:\n%s", out)
+	}
+	if got := strings.Count(out, "Gantt schedule"); got != 1 {
+		t.Errorf("ran %d times, want 1:\n%s", got, out)
+	}
+}
+
+func TestRunREPL_UnknownAlgorithm(t *testing.T) {
+	t.Parallel()
+	if err := RunREPL(strings.NewReader(""), &bytes.Buffer{}, "lottery", SchedulerConfig{}); err == nil {
+		t.Error("RunREPL() with an unknown algorithm did not fail")
+	}
+}
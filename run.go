@@ -0,0 +1,68 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// ErrInvalidRun is wrapped by the errors ImportRun returns for a bundle it cannot read.
+var ErrInvalidRun = errors.New("invalid run")
+
+// runFormatVersion is the version of the bundle ExportRun writes. It changes whenever a bundle
+// written by an older version would no longer import correctly.
+const runFormatVersion = 1
+
+// Run is everything about one scheduling run: the Algo and Config it was scheduled with, the
+// Processes it scheduled, its Result, and the trace of Events of the Result. Version is the
+// format of the bundle it was imported from.
+type Run struct {
+	Version   int             `json:"version"`
+	Algo      string          `json:"algo"`
+	Processes []Process       `json:"processes"`
+	Config    SchedulerConfig `json:"config"`
+	Result    ScheduleResult  `json:"result"`
+	Events    []Event         `json:"events"`
+}
+
+// ExportRun writes r, a schedule of processes by the algorithm named algo under cfg, to w as a
+// single self-contained JSON bundle, with its event trace and the format version, for
+// ImportRun to read back. The SwitchPenalty callback in cfg cannot be written and is left
+// out.
+func ExportRun(w io.Writer, algo string, processes []Process, cfg SchedulerConfig, r ScheduleResult) error {
+	run := Run{Version: runFormatVersion, Algo: algo, Processes: processes, Config: cfg, Result: r, Events: Events(r)}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(run); err != nil {
+		return fmt.Errorf("%w: encoding the run", err)
+	}
+	return nil
+}
+
+// ImportRun reads a bundle written by ExportRun from r. A bundle in another format version is
+// an error rather than a partial Run.
+func ImportRun(r io.Reader) (Run, error) {
+	var run Run
+	if err := json.NewDecoder(r).Decode(&run); err != nil {
+		return Run{}, fmt.Errorf("%w: %v", ErrInvalidRun, err)
+	}
+	if run.Version != runFormatVersion {
+		return Run{}, fmt.Errorf("%w: format version %d, want %d", ErrInvalidRun, run.Version, runFormatVersion)
+	}
+	return run, nil
+}
+
+// writeRun exports a run to the file named name, as ExportRun does.
+func writeRun(name, algo string, processes []Process, cfg SchedulerConfig, r ScheduleResult) error {
+	f, err := os.Create(name)
+	if err != nil {
+		return fmt.Errorf("%w: error creating run file", err)
+	}
+	if err := ExportRun(f, algo, processes, cfg, r); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
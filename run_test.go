@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestExportRun(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: "P0", ArrivalTime: 0, BurstDuration: 5, Priority: 2, IO: []IOBurst{{At: 2, Duration: 3}}, Meta: map[string]string{"job": "build-7"}},
+		{ProcessID: "P1", ArrivalTime: 1, BurstDuration: 3, DependsOn: []string{"P0"}, BurstDist: &BurstDistribution{Kind: Uniform, Min: 2, Max: 4}},
+		{ProcessID: "P2", ArrivalTime: 2, BurstDuration: 1, Deadline: 9, GroupID: "batch"},
+	}
+	cfg := SchedulerConfig{Quantum: 2, Priorities: &PriorityRange{Min: 0, Max: 5}, PriorityNames: map[string]int64{"high": 1}}
+	r := RRIO{Quantum: 2}.Schedule(processes)
+
+	var b bytes.Buffer
+	if err := ExportRun(&b, "rrio", processes, cfg, r); err != nil {
+		t.Fatalf("ExportRun: %v", err)
+	}
+	got, err := ImportRun(&b)
+	if err != nil {
+		t.Fatalf("ImportRun: %v", err)
+	}
+	want := Run{Version: runFormatVersion, Algo: "rrio", Processes: processes, Config: cfg, Result: r, Events: Events(r)}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf(diff)
+	}
+
+	// The callback is left out rather than failing the export.
+	cfg.SwitchPenalty = func(Process, int64) int64 { return 1 }
+	b.Reset()
+	if err := ExportRun(&b, "rr", processes, cfg, RR{Quantum: 2}.Schedule(processes)); err != nil {
+		t.Errorf("ExportRun with a callback: %v", err)
+	}
+}
+
+func TestImportRun_Invalid(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{name: "version", input: `{"version": 2, "algo": "fcfs"}`, wantErr: "invalid run: format version 2, want 1"},
+		{name: "unversioned", input: `{"algo": "fcfs"}`, wantErr: "invalid run: format version 0, want 1"},
+		{name: "truncated", input: `{"version": 1, "algo": "fcfs"`, wantErr: "invalid run: unexpected EOF"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := ImportRun(strings.NewReader(tt.input))
+			if !errors.Is(err, ErrInvalidRun) {
+				t.Fatalf("error %v is not ErrInvalidRun", err)
+			}
+			if diff := cmp.Diff(tt.wantErr, err.Error()); diff != "" {
+				t.Errorf(diff)
+			}
+		})
+	}
+}
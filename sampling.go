@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"strings"
+)
+
+// BurstDistribution is the distribution a process's burst is drawn from: Fixed, always Min;
+// Uniform, in [Min, Max]; or Exponential, with a mean of Mean rounded up so every burst is at
+// least 1.
+type BurstDistribution struct {
+	Kind     Distribution
+	Min, Max int64
+	Mean     float64
+}
+
+// sample draws a burst from d with rng.
+func (d BurstDistribution) sample(rng *rand.Rand) int64 {
+	switch d.Kind {
+	case Uniform:
+		return d.Min + rng.Int63n(d.Max-d.Min+1)
+	case Exponential:
+		return max(1, int64(math.Ceil(rng.ExpFloat64()*d.Mean)))
+	default:
+		return d.Min
+	}
+}
+
+// validate checks that d can be sampled.
+func (d BurstDistribution) validate() error {
+	switch d.Kind {
+	case Fixed, Uniform:
+		if d.Min < 0 || d.Kind == Uniform && d.Max < d.Min {
+			return fmt.Errorf("burst range [%d, %d] is empty or negative", d.Min, d.Max)
+		}
+	case Exponential:
+		if d.Mean <= 0 {
+			return fmt.Errorf("mean burst %v is not positive", d.Mean)
+		}
+	default:
+		return fmt.Errorf("burst distribution %d is not fixed, uniform or exponential", d.Kind)
+	}
+	return nil
+}
+
+// Sampled is a Scheduler that realizes the bursts of processes with a BurstDist before
+// Scheduler schedules them, so a workload's uncertain job lengths can be rerun with different
+// seeds. Bursts are drawn in arrival order, the order the processes become able to run, so a
+// seed realizes the same bursts under every Scheduler. The rows hold the realized bursts;
+// schedulers that know bursts in advance, such as SJF, see them as if they were exact.
+type Sampled struct {
+	Scheduler Scheduler
+	Seed      int64
+}
+
+func (s Sampled) Name() string { return s.Scheduler.Name() }
+
+func (s Sampled) Schedule(processes []Process) ScheduleResult {
+	return s.scheduleClocked(processes, nil)
+}
+
+func (s Sampled) scheduleClocked(processes []Process, clock *decisionClock) ScheduleResult {
+	return scheduleWith(s.Scheduler, SampleBursts(processes, s.Seed), clock)
+}
+
+// SampleBursts returns a copy of processes in which each process with a BurstDist has a
+// BurstDuration drawn from it, in arrival order, with seed. processes are left as is.
+func SampleBursts(processes []Process, seed int64) []Process {
+	rng := rand.New(rand.NewSource(seed))
+	sampled := append([]Process(nil), processes...)
+	for _, i := range sortedIndices(processes, func(a, b Process) bool { return a.ArrivalTime < b.ArrivalTime }) {
+		if d := sampled[i].BurstDist; d != nil {
+			sampled[i].BurstDuration = d.sample(rng)
+		}
+	}
+	return sampled
+}
+
+// outputRealizedBursts writes the burst each process with a BurstDist was given, if any.
+func outputRealizedBursts(w io.Writer, r ScheduleResult) {
+	var parts []string
+	for _, row := range r.Rows {
+		if row.Process.BurstDist != nil {
+			parts = append(parts, fmt.Sprintf("%s %d", row.Process.ProcessID, row.Process.BurstDuration))
+		}
+	}
+	if len(parts) > 0 {
+		_, _ = fmt.Fprintf(w, "Realized bursts: %s\n", strings.Join(parts, ", "))
+	}
+}
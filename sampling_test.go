@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSampleBursts(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: "U", ArrivalTime: 2, BurstDist: &BurstDistribution{Kind: Uniform, Min: 3, Max: 6}},
+		{ProcessID: "E", ArrivalTime: 0, BurstDist: &BurstDistribution{Kind: Exponential, Mean: 4}},
+		{ProcessID: "F", ArrivalTime: 1, BurstDist: &BurstDistribution{Kind: Fixed, Min: 7}},
+		{ProcessID: "X", ArrivalTime: 1, BurstDuration: 2},
+	}
+	input := append([]Process(nil), processes...)
+	for seed := int64(0); seed < 20; seed++ {
+		got := SampleBursts(processes, seed)
+		if diff := cmp.Diff(input, processes); diff != "" {
+			t.Fatalf("input modified: %s", diff)
+		}
+		if diff := cmp.Diff(got, SampleBursts(processes, seed)); diff != "" {
+			t.Errorf("seed %d: not repeatable: %s", seed, diff)
+		}
+		if u := got[0].BurstDuration; u < 3 || u > 6 {
+			t.Errorf("seed %d: uniform burst %d is outside [3, 6]", seed, u)
+		}
+		if e := got[1].BurstDuration; e < 1 {
+			t.Errorf("seed %d: exponential burst %d is below 1", seed, e)
+		}
+		if got[2].BurstDuration != 7 || got[3].BurstDuration != 2 {
+			t.Errorf("seed %d: fixed burst %d and exact burst %d, want 7 and 2", seed, got[2].BurstDuration, got[3].BurstDuration)
+		}
+	}
+}
+
+func TestSampled_Schedule(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: "P0", ArrivalTime: 0, BurstDist: &BurstDistribution{Kind: Uniform, Min: 1, Max: 10}},
+		{ProcessID: "P1", ArrivalTime: 1, BurstDist: &BurstDistribution{Kind: Exponential, Mean: 5}},
+		{ProcessID: "P2", ArrivalTime: 1, BurstDuration: 3},
+	}
+	fcfs := Sampled{Scheduler: FCFS{}, Seed: 3}.Schedule(processes)
+	rr := Sampled{Scheduler: RR{Quantum: 2}, Seed: 3}.Schedule(processes)
+	var realized []string
+	for i := range processes {
+		if fcfs.Rows[i].Process.BurstDuration != rr.Rows[i].Process.BurstDuration {
+			t.Errorf("%s: burst %d under FCFS but %d under RR", processes[i].ProcessID,
+				fcfs.Rows[i].Process.BurstDuration, rr.Rows[i].Process.BurstDuration)
+		}
+		if processes[i].BurstDist != nil {
+			realized = append(realized, fmt.Sprintf("%s %d", processes[i].ProcessID, fcfs.Rows[i].Process.BurstDuration))
+		}
+	}
+	for _, r := range []ScheduleResult{fcfs, rr} {
+		if err := VerifyTurnaround(r); err != nil {
+			t.Error(err)
+		}
+	}
+
+	var w bytes.Buffer
+	outputSchedule(&w, nil, fcfs, RenderOptions{})
+	if line := "Realized bursts: " + strings.Join(realized, ", ") + "\n"; !strings.Contains(w.String(), line) {
+		t.Errorf("summary is missing %q:\n%s", line, w.String())
+	}
+}
+
+func TestValidateProcesses_BurstDist(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		dist BurstDistribution
+		ok   bool
+	}{
+		{name: "fixed", dist: BurstDistribution{Kind: Fixed, Min: 2}, ok: true},
+		{name: "uniform", dist: BurstDistribution{Kind: Uniform, Min: 1, Max: 1}, ok: true},
+		{name: "exponential", dist: BurstDistribution{Kind: Exponential, Mean: 0.5}, ok: true},
+		{name: "empty range", dist: BurstDistribution{Kind: Uniform, Min: 2, Max: 1}},
+		{name: "negative", dist: BurstDistribution{Kind: Fixed, Min: -1}},
+		{name: "no mean", dist: BurstDistribution{Kind: Exponential}},
+		{name: "arrival distribution", dist: BurstDistribution{Kind: Poisson}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := ValidateProcesses([]Process{{ProcessID: "P0", BurstDist: &tt.dist}})
+			if tt.ok && err != nil || !tt.ok && !errors.Is(err, ErrInvalidProcess) {
+				t.Errorf("ValidateProcesses() = %v", err)
+			}
+		})
+	}
+}
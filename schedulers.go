@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"io"
 	"sort"
+	"strings"
+	"time"
 )
 
 type (
@@ -16,183 +18,844 @@ type (
 		Turnaround    int
 		Waiting       int
 		Burst         int
+		// Pinned processes are dispatched before any unpinned process that has arrived, and in
+		// the algorithm's usual order among themselves, by every Scheduler and by Online.
+		// Pinning does not preempt: a pinned arrival waits for the running process to finish,
+		// for its quantum to expire, or for the algorithm to preempt it as usual. Under
+		// FairShare and GangRR a pinned process's group takes the next turn, and under MultiCore
+		// without Gang or Backfill it goes ahead of the processes queued on its core.
+		// ProcessorSharing runs every ready process at once and ignores it.
+		Pinned bool
+		// Deadline is the time by which the process should complete; 0 means it has none.
+		Deadline int64
+		// ResponseDeadline is the most time the process should wait after arriving before it
+		// first reaches the CPU, as for an interactive task; 0 means it has none. Unlike
+		// Deadline it is relative to the arrival.
+		ResponseDeadline int64
+		// GroupID names the group the process shares the CPU with under FairShare.
+		GroupID string
+		// DependsOn lists the ProcessIDs that must complete before this process can run. Until
+		// then it is not ready even if it has arrived, and the time counts as waiting.
+		DependsOn []string
+		// UtilityDecay is how fast completing the process loses utility once it is past its
+		// Deadline under DecayingUtility; 0 means 1.
+		UtilityDecay float64
+		// Value is what completing the process is worth, for ValueThroughput; 0 by default.
+		Value float64
+		// Power is the rate at which the process uses energy while it runs, for Energy; 0
+		// means 1.
+		Power float64
+		// Period, if positive, makes the process periodic: ExpandPeriodic releases a job of it
+		// every Period time units from its ArrivalTime.
+		Period int64
+		// Width is the number of cores the process holds at once under MultiCore's Gang mode;
+		// 0 means 1.
+		Width int
+		// BurstDist, if set, makes the burst uncertain: Sampled draws BurstDuration from it
+		// before scheduling, and the other schedulers ignore it.
+		BurstDist *BurstDistribution
+		// IO lists the waits for I/O during the process's burst, in order. Only RRIO blocks
+		// processes for them; the other schedulers run the burst through.
+		IO []IOBurst
+		// Meta is free-form metadata, such as the ID of the real job the process stands for. The
+		// schedulers ignore it and carry it through to the results unchanged, so copies of a
+		// Process share it.
+		Meta map[string]string
 	}
 	TimeSlice struct {
 		PID   string
 		Start int64
 		Stop  int64
+		// Core is the CPU the slice ran on, always 0 outside a multi-core schedule.
+		Core int
+	}
+
+	// ProcessResult is the timing of a single process in a schedule.
+	ProcessResult struct {
+		Process    Process
+		Waiting    int64
+		Turnaround int64
+		Completion int64
+		// Blocked is the time the process spent blocked for I/O, which is not part of Waiting.
+		Blocked int64
+	}
+	// ScheduleResult is the outcome of scheduling a set of processes. Rows are in the order the
+	// processes were given.
+	//
+	// When Resolution is above 1 the schedule was simulated in ticks of 1/Resolution time
+	// units: the Gantt, the row Waiting, Turnaround and Completion, and Makespan are in ticks,
+	// while the averages and Throughput are always in whole time units.
+	ScheduleResult struct {
+		Gantt         []TimeSlice
+		Rows          []ProcessResult
+		AvgWait       float64
+		AvgTurnaround float64
+		// Throughput is the processes completed per time unit of the makespan; 0 when the
+		// makespan is, as when every burst is zero.
+		Throughput float64
+		// Makespan is the time the last process completed, in ticks.
+		Makespan int64
+		// Resolution is the number of ticks per time unit; 0 and 1 both mean a tick is one.
+		Resolution int64
+		// AvgQueueLength is the time-average number of processes waiting in the ready queue
+		// over [0, Makespan], as measured by the simulation. By Little's Law it should equal
+		// Throughput * AvgWait.
+		AvgQueueLength float64
+		// ContextSwitches is the number of times a CPU went from running one process to
+		// running a different one, whether or not it idled in between.
+		ContextSwitches int
+		// IdleTime is the time in ticks the CPU was idle over [0, Makespan], and AvgIdleGap the
+		// mean length in time units of the intervals it was idle for.
+		IdleTime   int64
+		AvgIdleGap float64
+		// Cores is the number of CPUs the schedule ran on; 0 means a single CPU. A multi-core
+		// schedule is idle only while every core is.
+		Cores int
+		// Backfilled is the number of processes a backfilling MultiCore started ahead of an
+		// earlier one that was waiting for cores.
+		Backfilled int
+	}
+
+	// Boundary decides whether a process arriving at the instant the CPU becomes free, because
+	// the running process completed or was preempted, is a candidate for the decision made then.
+	Boundary int
+
+	// SchedulerConfig holds the options shared by the schedulers.
+	SchedulerConfig struct {
+		// Quantum is the round-robin time slice, in ticks of 1/Resolution time units.
+		Quantum int64
+		// Resolution is the number of round-robin ticks per time unit; 0 and 1 both mean the
+		// simulation runs in whole time units.
+		Resolution int64
+		// HigherPriorityFirst makes a larger Priority value more urgent. By default a lower
+		// Priority value is more urgent, so priority 1 runs before priority 2.
+		HigherPriorityFirst bool
+		// Checkpoints treats processes with a zero BurstDuration as checkpoints: they complete
+		// at their arrival without waiting for the CPU, appear as zero-width markers in the
+		// Gantt, and are left out of the averages, throughput and makespan.
+		Checkpoints bool
+		// SwitchPenalty, if set, is the cache-warmup cost in ticks of switching the CPU to p
+		// after p has been off it for offCPU ticks, or since its arrival if it has not run yet.
+		// Only the preemptive schedulers apply it.
+		SwitchPenalty func(p Process, offCPU int64) int64 `json:"-"`
+		// Boundary is how FCFS, SJF, SJFPriority and RR order an arrival and a decision at the
+		// same instant. The other schedulers always admit arrivals first.
+		Boundary Boundary
+		// Cores, if above 1, runs FCFS on that many CPUs, queuing each process on the core
+		// Dispatch picks, or with Backfill from one backfilling queue; see MultiCore.
+		Cores    int
+		Dispatch DispatchPolicy
+		Backfill bool
+		// Priorities, if set, is the range Priority values must fall in; see
+		// ValidatePriorities. By default every Priority is valid, including negative ones.
+		Priorities *PriorityRange
+		// PriorityNames maps the names a workload file may give as a priority, in lower case,
+		// to Priority values. nil is DefaultPriorityNames.
+		PriorityNames map[string]int64
+	}
+
+	// Scheduler computes a schedule for processes without modifying them.
+	Scheduler interface {
+		Name() string
+		Schedule(processes []Process) ScheduleResult
 	}
 )
 
 //region Schedulers
 
+type (
+	// FCFS schedules processes first-come, first-serve.
+	FCFS struct {
+		Boundary Boundary
+	}
+	// SJF schedules processes shortest-job-first, choosing among the processes that have arrived.
+	SJF struct {
+		Boundary Boundary
+	}
+	// SJFPriority schedules available processes shortest-job-first, breaking ties by priority.
+	SJFPriority struct {
+		SchedulerConfig
+	}
+	// RR schedules processes round-robin, preempting after every Quantum ticks. A tick is one
+	// time unit, or 1/Resolution of one when Resolution is above 1, so a Quantum of 5 with a
+	// Resolution of 10 is half a time unit. SwitchPenalty and Boundary are as in SchedulerConfig.
+	//
+	// The result's Gantt, row times and Makespan are in those ticks, and its averages in time
+	// units; see ScheduleResult. Schedule panics with an ErrOverflow error, which SafeSchedule
+	// returns, if a workload's times overflow an int64 once scaled to ticks, so check new
+	// workloads with ValidateResolution first.
+	RR struct {
+		Quantum       int64
+		Resolution    int64
+		SwitchPenalty func(p Process, offCPU int64) int64
+		Boundary      Boundary
+	}
+)
+
+const (
+	// ArrivalsFirst, the default, admits a process arriving as the CPU becomes free before the
+	// next process is chosen, so it can be chosen. Under round-robin it also joins the ready
+	// queue ahead of a process preempted at that instant.
+	ArrivalsFirst Boundary = iota
+	// DecisionFirst chooses the next process before admitting one arriving at that instant, which
+	// then joins the ready queue behind a process preempted at that instant. An idle CPU still
+	// runs a process as it arrives.
+	DecisionFirst
+)
+
+func (FCFS) Name() string        { return fcfs.String() }
+func (SJF) Name() string         { return sjf.String() }
+func (SJFPriority) Name() string { return sjfp.String() }
+func (RR) Name() string          { return rr.String() }
+
+func (s FCFS) Schedule(processes []Process) ScheduleResult {
+	return s.scheduleClocked(processes, nil)
+}
+
+func (s SJF) Schedule(processes []Process) ScheduleResult {
+	return s.scheduleClocked(processes, nil)
+}
+
+func (s SJFPriority) Schedule(processes []Process) ScheduleResult {
+	return s.scheduleClocked(processes, nil)
+}
+
+func (s RR) Schedule(processes []Process) ScheduleResult {
+	return s.scheduleClocked(processes, nil)
+}
+
+func (s FCFS) scheduleClocked(processes []Process, clock *decisionClock) ScheduleResult {
+	return simulate(processes, 0, nil, s.Boundary, clock)
+}
+
+func (s RR) scheduleClocked(processes []Process, clock *decisionClock) ScheduleResult {
+	if s.Resolution <= 1 {
+		return simulate(processes, s.Quantum, s.SwitchPenalty, s.Boundary, clock)
+	}
+
+	if err := ValidateResolution(processes, s.Resolution); err != nil {
+		panic(err)
+	}
+	scaled := make([]Process, len(processes))
+	for i, p := range processes {
+		p.ArrivalTime *= s.Resolution
+		p.BurstDuration *= s.Resolution
+		scaled[i] = p
+	}
+	r := simulate(scaled, s.Quantum, s.SwitchPenalty, s.Boundary, clock)
+	for i := range r.Rows {
+		r.Rows[i].Process = processes[i]
+	}
+	r.AvgWait /= float64(s.Resolution)
+	r.AvgTurnaround /= float64(s.Resolution)
+	r.AvgIdleGap /= float64(s.Resolution)
+	r.Throughput *= float64(s.Resolution)
+	r.Resolution = s.Resolution
+
+	return r
+}
+
+func (s SJF) scheduleClocked(processes []Process, clock *decisionClock) ScheduleResult {
+	return nonPreemptive(processes, lessQueue(processes, shorter), s.Boundary, clock)
+}
+
+func (s SJFPriority) scheduleClocked(processes []Process, clock *decisionClock) ScheduleResult {
+	return nonPreemptive(processes, lessQueue(processes, s.shorterThenPriority), s.Boundary, clock)
+}
+
+// shorter is the SJF order.
+func shorter(a, b Process) bool {
+	return a.BurstDuration < b.BurstDuration
+}
+
+// shorterThenPriority is the SJFPriority order.
+func (c SchedulerConfig) shorterThenPriority(a, b Process) bool {
+	if a.BurstDuration == b.BurstDuration {
+		return c.morePriority(a, b)
+	}
+	return a.BurstDuration < b.BurstDuration
+}
+
 // FCFSSchedule outputs a schedule of processes in a GANTT chart and a table of timing given:
 // • an output writer
 // • a title for the chart
 // • a slice of processes
 func FCFSSchedule(w io.Writer, title string, processes []Process) {
-	var (
-		serviceTime     int64
-		totalWait       float64
-		totalTurnaround float64
-		lastCompletion  float64
-		waitingTime     int64
-		schedule        = make([][]string, len(processes))
-		gantt           = make([]TimeSlice, 0)
-	)
-	for i := range processes {
-		if processes[i].ArrivalTime > 0 {
-			waitingTime = serviceTime - processes[i].ArrivalTime
-		}
-		totalWait += float64(waitingTime)
+	outputResult(w, title, FCFS{}.Schedule(processes), RenderOptions{})
+}
 
-		start := waitingTime + processes[i].ArrivalTime
+func SJFSchedule(w io.Writer, title string, processes []Process) {
+	outputResult(w, title, SJF{}.Schedule(processes), RenderOptions{})
+}
 
-		turnaround := processes[i].BurstDuration + waitingTime
-		totalTurnaround += float64(turnaround)
+func max(a, b int64) int64 {
+	if a > b {
+		return a
+	}
+	return b
+}
 
-		completion := processes[i].BurstDuration + processes[i].ArrivalTime + waitingTime
-		lastCompletion = float64(completion)
+func SJFPrioritySchedule(w io.Writer, title string, processes []Process, cfg SchedulerConfig) {
+	outputSJFPriority(w, title, SJFPriority{cfg}.Schedule(processes), RenderOptions{})
+}
 
-		schedule[i] = []string{
-			fmt.Sprint(processes[i].ProcessID),
-			fmt.Sprint(processes[i].Priority),
-			fmt.Sprint(processes[i].BurstDuration),
-			fmt.Sprint(processes[i].ArrivalTime),
-			fmt.Sprint(waitingTime),
-			fmt.Sprint(turnaround),
-			fmt.Sprint(completion),
-		}
-		serviceTime += processes[i].BurstDuration
+// outputSJFPriority renders r as one line per process in completion order.
+func outputSJFPriority(w io.Writer, title string, r ScheduleResult, opts RenderOptions) {
+	fmt.Fprintf(w, "------ %s ------\n", title)
 
-		gantt = append(gantt, TimeSlice{
-			PID:   processes[i].ProcessID,
-			Start: start,
-			Stop:  serviceTime,
-		})
+	for _, i := range completionOrder(r) {
+		row := r.Rows[i]
+		fmt.Fprintf(w, "Process %s completed at %d, Wait: %d, Turnaround: %d\n", row.Process.ProcessID, row.Completion, row.Waiting, row.Turnaround)
 	}
 
-	count := float64(len(processes))
-	aveWait := totalWait / count
-	aveTurnaround := totalTurnaround / count
-	aveThroughput := count / lastCompletion
+	fmt.Fprintf(w, "Average wait time: %s\n", opts.Rounding.format(r.AvgWait))
+	fmt.Fprintf(w, "Average turnaround time: %s\n", opts.Rounding.format(r.AvgTurnaround))
+	fmt.Fprintf(w, "Throughput: %.2f processes/unit time\n", r.Throughput)
+}
 
-	outputTitle(w, title)
-	outputGantt(w, gantt)
-	outputSchedule(w, schedule, aveWait, aveTurnaround, aveThroughput)
+// RRSchedule outputs a round-robin schedule of processes in a GANTT chart and a table of timing,
+// preempting the running process after every quantum time units.
+func RRSchedule(w io.Writer, title string, processes []Process, quantum int64) {
+	outputResult(w, title, RR{Quantum: quantum}.Schedule(processes), RenderOptions{})
 }
 
-func SJFSchedule(w io.Writer, title string, processes []Process) {
-	// Step 1: Sort the processes by burst time
-	sort.Slice(processes, func(i, j int) bool {
-		return processes[i].BurstDuration < processes[j].BurstDuration
-	})
+//endregion
+
+//region Simulation core
 
+// decisionClock accumulates the wall-clock time a simulation spends deciding which process runs
+// next, as opposed to simulated time, reading the time from now, or the real clock if now is nil.
+// A nil decisionClock measures nothing.
+type decisionClock struct {
+	now   Clock
+	total time.Duration
+}
+
+// start returns the time a decision starts, if c is measuring.
+func (c *decisionClock) start() time.Time {
+	if c == nil {
+		return time.Time{}
+	}
+	return clockOrWall(c.now).Now()
+}
+
+// stop adds the time since a decision started at t0.
+func (c *decisionClock) stop(t0 time.Time) {
+	if c != nil {
+		c.total += clockOrWall(c.now).Now().Sub(t0)
+	}
+}
+
+// clocked is implemented by the schedulers whose decisions a decisionClock can measure.
+type clocked interface {
+	scheduleClocked(processes []Process, clock *decisionClock) ScheduleResult
+}
+
+// scheduleWith schedules processes with s, measuring its decisions on clock if s supports it.
+func scheduleWith(s Scheduler, processes []Process, clock *decisionClock) ScheduleResult {
+	if c, ok := s.(clocked); ok {
+		return c.scheduleClocked(processes, clock)
+	}
+	return s.Schedule(processes)
+}
+
+// Notify returns a Scheduler that schedules with s and then calls onComplete for each process
+// in the order the processes complete, with the completion time in the result's ticks. A nil
+// onComplete is never called.
+func Notify(s Scheduler, onComplete func(p Process, completionTime int64)) Scheduler {
+	return notifying{Scheduler: s, onComplete: onComplete}
+}
+
+// notifying is a Scheduler that reports every completion to onComplete.
+type notifying struct {
+	Scheduler
+	onComplete func(p Process, completionTime int64)
+}
+
+func (n notifying) Schedule(processes []Process) ScheduleResult {
+	return n.scheduleClocked(processes, nil)
+}
+
+func (n notifying) scheduleClocked(processes []Process, clock *decisionClock) ScheduleResult {
+	r := scheduleWith(n.Scheduler, processes, clock)
+	if n.onComplete == nil {
+		return r
+	}
+	for _, i := range completionOrder(r) {
+		n.onComplete(r.Rows[i].Process, r.Rows[i].Completion)
+	}
+	return r
+}
+
+// checkpointing is a Scheduler that schedules only processes with work and completes zero-burst
+// processes at their arrival, as described by SchedulerConfig.Checkpoints.
+type checkpointing struct {
+	Scheduler
+}
+
+func (c checkpointing) Schedule(processes []Process) ScheduleResult {
+	return c.scheduleClocked(processes, nil)
+}
+
+func (c checkpointing) scheduleClocked(processes []Process, clock *decisionClock) ScheduleResult {
 	var (
-		currentTime     int64
-		totalWait       float64
-		totalTurnaround float64
+		work    []Process
+		workIdx []int
 	)
-	gantt := make([]TimeSlice, len(processes))
-	schedule := make([][]string, len(processes))
+	for i, p := range processes {
+		if p.BurstDuration > 0 {
+			work = append(work, p)
+			workIdx = append(workIdx, i)
+		}
+	}
 
-	for i, process := range processes {
-		waitTime := max(0, currentTime-process.ArrivalTime)
-		currentTime = max(currentTime, process.ArrivalTime) + process.BurstDuration
-		turnaroundTime := currentTime - process.ArrivalTime
+	r := scheduleWith(c.Scheduler, work, clock)
+	rows := make([]ProcessResult, len(processes))
+	for n, i := range workIdx {
+		rows[i] = r.Rows[n]
+	}
+	for i, p := range processes {
+		if p.BurstDuration > 0 {
+			continue
+		}
+		t := p.ArrivalTime * max(1, r.Resolution)
+		rows[i] = ProcessResult{Process: p, Completion: t}
+		r.Gantt = insertMarker(r.Gantt, TimeSlice{PID: p.ProcessID, Start: t, Stop: t})
+	}
+	r.Rows = rows
 
-		totalWait += float64(waitTime)
-		totalTurnaround += float64(turnaroundTime)
+	return r
+}
 
-		schedule[i] = []string{
-			process.ProcessID,
-			fmt.Sprintf("%d", process.Priority),
-			fmt.Sprintf("%d", process.BurstDuration),
-			fmt.Sprintf("%d", process.ArrivalTime),
-			fmt.Sprintf("%d", waitTime),
-			fmt.Sprintf("%d", turnaroundTime),
-			fmt.Sprintf("%d", currentTime),
+// insertMarker inserts the zero-width marker m into gantt at its time, after any markers already
+// there. A slice running across m is split around it.
+func insertMarker(gantt []TimeSlice, m TimeSlice) []TimeSlice {
+	k := len(gantt)
+	for i, slice := range gantt {
+		if slice.Start > m.Start || (slice.Start == m.Start && slice.Stop > slice.Start) {
+			k = i
+			break
 		}
+	}
 
-		gantt[i] = TimeSlice{
-			PID:   process.ProcessID,
-			Start: currentTime - process.BurstDuration,
-			Stop:  currentTime,
-		}
+	out := make([]TimeSlice, 0, len(gantt)+2)
+	out = append(out, gantt[:k]...)
+	if k > 0 && out[k-1].Stop > m.Start {
+		split := out[k-1]
+		out[k-1].Stop = m.Start
+		split.Start = m.Start
+		out = append(out, m, split)
+	} else {
+		out = append(out, m)
 	}
+	return append(out, gantt[k:]...)
+}
 
-	aveWait := totalWait / float64(len(processes))
-	aveTurnaround := totalTurnaround / float64(len(processes))
-	throughput := float64(len(processes)) / float64(currentTime)
+// completionOrder returns the indices of r.Rows in the order the processes completed.
+// Processes completing at the same time are in ProcessID order, then in input order.
+func completionOrder(r ScheduleResult) []int {
+	order := make([]int, len(r.Rows))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		a, b := r.Rows[order[i]], r.Rows[order[j]]
+		if a.Completion != b.Completion {
+			return a.Completion < b.Completion
+		}
+		return a.Process.ProcessID < b.Process.ProcessID
+	})
+	return order
+}
 
-	outputTitle(w, title)
-	outputGantt(w, gantt)
-	outputSchedule(w, schedule, aveWait, aveTurnaround, throughput)
+// CompletionOrder returns the ProcessIDs of r's processes in the order they completed, with
+// simultaneous completions in ProcessID order.
+func CompletionOrder(r ScheduleResult) []string {
+	pids := make([]string, 0, len(r.Rows))
+	for _, i := range completionOrder(r) {
+		pids = append(pids, r.Rows[i].Process.ProcessID)
+	}
+	return pids
 }
 
-func max(a, b int64) int64 {
-	if a > b {
-		return a
+// morePriority reports whether a is more urgent than b under the configured priority convention.
+func (c SchedulerConfig) morePriority(a, b Process) bool {
+	if c.HigherPriorityFirst {
+		return a.Priority > b.Priority
 	}
-	return b
+	return a.Priority < b.Priority
 }
 
-func SJFPrioritySchedule(w io.Writer, title string, processes []Process) {
-	fmt.Fprintf(w, "------ %s ------\n", title)
+// warmupPrefix marks a Gantt slice in which the CPU pays the switch penalty of the process named
+// by the rest of the PID before running it.
+const warmupPrefix = "~"
 
-	var currentTime int64 = 0
-	var totalWait, totalTurnaround float64
-	var completed int = 0
+// processTiming is the outcome of one process in a simulation.
+type processTiming struct {
+	Waiting    int64
+	Turnaround int64
+	Completion int64
+	Blocked    int64
+}
 
-	sort.Slice(processes, func(i, j int) bool {
-		return processes[i].ArrivalTime < processes[j].ArrivalTime
-	})
+// simulate runs processes on a single CPU, dispatching the ready queue in arrival order and
+// preempting the running process after quantum time units. A quantum <= 0 never preempts,
+// which makes the simulation first-come, first-serve. Processes that arrive while another
+// runs join the ready queue ahead of it when it is preempted. The first pinned process in the
+// ready queue is dispatched ahead of the queue's head.
+//
+// If penalty is set, dispatching a process other than the one that last ran first spends
+// penalty ticks in a warmup slice. The process counts as waiting during its warmup.
+func simulate(processes []Process, quantum int64, penalty func(p Process, offCPU int64) int64, boundary Boundary, clock *decisionClock) ScheduleResult {
+	arr := newArrivals(processes)
+	remaining := make([]int64, len(processes))
+	offSince := make([]int64, len(processes))
+	for i := range processes {
+		remaining[i] = processes[i].BurstDuration
+		offSince[i] = processes[i].ArrivalTime
+	}
 
-	for completed < len(processes) {
-		var available []int
-		for i, p := range processes {
-			if !p.Completed && p.ArrivalTime <= currentTime {
-				available = append(available, i)
+	var (
+		now       int64
+		last      = -1 // the process that last ran.
+		ready     []int
+		queueArea int64 // integral of the ready queue length over time.
+		timings   = make([]processTiming, len(processes))
+		gantt     = make([]TimeSlice, 0)
+		dog       watchdog
+		stuck     = unfinished(processes, remaining)
+	)
+	enqueue := func(i int) {
+		// Processes that arrived during the last run have been waiting since arrival.
+		queueArea += now - processes[i].ArrivalTime
+		ready = append(ready, i)
+	}
+	admit := func() {
+		if boundary == DecisionFirst {
+			arr.releaseBefore(now, enqueue)
+		} else {
+			arr.release(now, enqueue)
+		}
+	}
+	for done := 0; done < len(processes); {
+		admit()
+		if len(ready) == 0 {
+			arr.release(now, enqueue)
+		}
+		if len(ready) == 0 {
+			// CPU is idle until the next arrival.
+			now = arr.idleUntil(now)
+			continue
+		}
+		dog.observe(now, done, stuck)
+		t0 := clock.start()
+		k := firstPinned(ready, processes)
+		i := ready[k]
+		ready = append(ready[:k], ready[k+1:]...)
+		clock.stop(t0)
+
+		if penalty != nil && last >= 0 && last != i {
+			if cost := penalty(processes[i], now-offSince[i]); cost > 0 {
+				gantt = append(gantt, TimeSlice{
+					PID:   warmupPrefix + processes[i].ProcessID,
+					Start: now,
+					Stop:  now + cost,
+				})
+				queueArea += int64(len(ready)+1) * cost
+				now += cost
+				admit()
 			}
 		}
+		last = i
 
-		sort.Slice(available, func(i, j int) bool {
-			if processes[available[i]].BurstDuration == processes[available[j]].BurstDuration {
-				return processes[available[i]].Priority < processes[available[j]].Priority
-			}
-			return processes[available[i]].BurstDuration < processes[available[j]].BurstDuration
+		run := remaining[i]
+		if quantum > 0 && quantum < run {
+			run = quantum
+		}
+		gantt = append(gantt, TimeSlice{
+			PID:   processes[i].ProcessID,
+			Start: now,
+			Stop:  now + run,
 		})
+		queueArea += int64(len(ready)) * run
+		now += run
+		remaining[i] -= run
+		offSince[i] = now
 
-		if len(available) == 0 {
-			currentTime++
+		if remaining[i] == 0 {
+			arr.complete(i)
+		}
+		admit()
+		if remaining[i] > 0 {
+			ready = append(ready, i)
 			continue
 		}
+		done++
+		timings[i].Completion = now
+		timings[i].Turnaround = now - processes[i].ArrivalTime
+		timings[i].Waiting = timings[i].Turnaround - processes[i].BurstDuration
+	}
 
-		idx := available[0]
-		p := &processes[idx]
+	return newScheduleResult(processes, gantt, timings).withQueueArea(queueArea)
+}
 
-		waitTime := currentTime - p.ArrivalTime
-		turnaroundTime := waitTime + p.BurstDuration
+// nonPreemptive runs processes on a single CPU, each to completion. Whenever the CPU is free it
+// runs the arrived process ready picks, and idles until the next arrival if none has arrived.
+func nonPreemptive(processes []Process, ready picker, boundary Boundary, clock *decisionClock) ScheduleResult {
+	arr := newArrivals(processes)
 
-		totalWait += float64(waitTime)
-		totalTurnaround += float64(turnaroundTime)
-		p.Completed = true
-		completed++
+	var (
+		now       int64
+		queueArea int64 // integral of the ready queue length over time.
+		timings   = make([]processTiming, len(processes))
+		gantt     = make([]TimeSlice, 0, len(processes))
+	)
+	admit := func(i int) {
+		// Processes that arrived during the last run have been waiting since arrival.
+		queueArea += now - processes[i].ArrivalTime
+		ready.enqueue(i)
+	}
+	for range processes {
+		t0 := clock.start()
+		if boundary == DecisionFirst {
+			arr.releaseBefore(now, admit)
+		}
+		if boundary != DecisionFirst || ready.Len() == 0 {
+			arr.release(now, admit)
+		}
+		for ready.Len() == 0 {
+			// CPU is idle until the next arrival.
+			now = max(now, arr.idleUntil(now))
+			arr.release(now, admit)
+		}
+		i := ready.next(now)
+		clock.stop(t0)
+		queueArea += int64(ready.Len()) * processes[i].BurstDuration
+		p := processes[i]
+		gantt = append(gantt, TimeSlice{
+			PID:   p.ProcessID,
+			Start: now,
+			Stop:  now + p.BurstDuration,
+		})
+		timings[i] = processTiming{
+			Waiting:    now - p.ArrivalTime,
+			Turnaround: now - p.ArrivalTime + p.BurstDuration,
+			Completion: now + p.BurstDuration,
+		}
+		now += p.BurstDuration
+		arr.complete(i)
+	}
 
-		currentTime += p.BurstDuration
+	return newScheduleResult(processes, gantt, timings).withQueueArea(queueArea)
+}
 
-		fmt.Fprintf(w, "Process %s completed at %d, Wait: %d, Turnaround: %d\n", p.ProcessID, currentTime, waitTime, turnaroundTime)
+// compactGantt removes the zero-width slices from gantt, in place, that a simulation may leave
+// when a process is dispatched for no time. A zero-width slice of a process with no work to do
+// marks when it ran and is kept, as are checkpoint markers.
+func compactGantt(processes []Process, gantt []TimeSlice) []TimeSlice {
+	work := make(map[string]bool, len(processes))
+	for _, p := range processes {
+		if p.BurstDuration > 0 {
+			work[p.ProcessID] = true
+		}
 	}
+	kept := gantt[:0]
+	for _, slice := range gantt {
+		if slice.Start == slice.Stop && work[strings.TrimPrefix(slice.PID, warmupPrefix)] {
+			continue
+		}
+		kept = append(kept, slice)
+	}
+	return kept
+}
 
-	avgWait := totalWait / float64(len(processes))
-	avgTurnaround := totalTurnaround / float64(len(processes))
-	throughput := float64(len(processes)) / float64(currentTime)
+// sortedIndices returns the indices of processes stably sorted by less, leaving processes as is.
+func sortedIndices(processes []Process, less func(a, b Process) bool) []int {
+	order := make([]int, len(processes))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return less(processes[order[i]], processes[order[j]])
+	})
+	return order
+}
 
-	fmt.Fprintf(w, "Average wait time: %.2f\n", avgWait)
-	fmt.Fprintf(w, "Average turnaround time: %.2f\n", avgTurnaround)
-	fmt.Fprintf(w, "Throughput: %.2f processes/unit time\n", throughput)
+// newScheduleResult collects the timings of processes into a ScheduleResult, with gantt
+// compacted by compactGantt.
+func newScheduleResult(processes []Process, gantt []TimeSlice, timings []processTiming) ScheduleResult {
+	var (
+		totalWait       float64
+		totalTurnaround float64
+		r               = ScheduleResult{
+			Gantt: compactGantt(processes, gantt),
+			Rows:  make([]ProcessResult, len(processes)),
+		}
+	)
+	for i := range processes {
+		totalWait += float64(timings[i].Waiting)
+		totalTurnaround += float64(timings[i].Turnaround)
+		r.Makespan = max(r.Makespan, timings[i].Completion)
+
+		r.Rows[i] = ProcessResult{
+			Process:    processes[i],
+			Waiting:    timings[i].Waiting,
+			Turnaround: timings[i].Turnaround,
+			Completion: timings[i].Completion,
+			Blocked:    timings[i].Blocked,
+		}
+	}
+
+	var (
+		last     = make(map[int]string) // the last process on each core.
+		busyTill int64
+		idleGaps int
+	)
+	for _, slice := range gantt {
+		if slice.Start == slice.Stop {
+			continue
+		}
+		pid := strings.TrimPrefix(slice.PID, warmupPrefix)
+		if prev, ok := last[slice.Core]; ok && pid != prev {
+			r.ContextSwitches++
+		}
+		last[slice.Core] = pid
+		if slice.Start > busyTill {
+			r.IdleTime += slice.Start - busyTill
+			idleGaps++
+		}
+		busyTill = max(busyTill, slice.Stop)
+	}
+	if idleGaps > 0 {
+		r.AvgIdleGap = float64(r.IdleTime) / float64(idleGaps)
+	}
+
+	if len(processes) == 0 {
+		return r
+	}
+	count := float64(len(processes))
+	r.AvgWait = totalWait / count
+	r.AvgTurnaround = totalTurnaround / count
+	if r.Makespan > 0 {
+		r.Throughput = count / float64(r.Makespan)
+	}
+
+	return r
 }
 
-func RRSchedule(w io.Writer, title string, processes []Process) {}
+// withQueueArea sets r.AvgQueueLength from the integral of the ready queue length over time.
+func (r ScheduleResult) withQueueArea(area int64) ScheduleResult {
+	if r.Makespan > 0 {
+		r.AvgQueueLength = float64(area) / float64(r.Makespan)
+	}
+	return r
+}
+
+// Slowdowns returns each process's normalized turnaround, its turnaround divided by its burst,
+// in the order of r's rows: 1 means it never waited, and 3 that it took three times as long as
+// it ran. A process with no burst has a slowdown of 1.
+func (r ScheduleResult) Slowdowns() []float64 {
+	slowdowns := make([]float64, len(r.Rows))
+	for i, row := range r.Rows {
+		slowdowns[i] = 1
+		if burst := row.Process.BurstDuration * max(1, r.Resolution); burst > 0 {
+			slowdowns[i] = float64(row.Turnaround) / float64(burst)
+		}
+	}
+	return slowdowns
+}
+
+// Stretch is r's makespan relative to the work in it, the sum of the bursts, with every core
+// counted for the whole makespan. It is at least 1 once any process has a burst, and exactly 1
+// when the CPUs were never idle and paid no switch penalty; 0 means there was no work.
+func (r ScheduleResult) Stretch() float64 {
+	var work int64
+	for _, row := range r.Rows {
+		work += row.Process.BurstDuration
+	}
+	if work == 0 {
+		return 0
+	}
+	return float64(r.Makespan*max(1, int64(r.Cores))) / float64(work*max(1, r.Resolution))
+}
+
+// ValueThroughput is the Value of r's processes completed per time unit, the value-weighted
+// counterpart of Throughput. It is 0 for an empty schedule.
+func (r ScheduleResult) ValueThroughput() float64 {
+	if r.Makespan == 0 {
+		return 0
+	}
+	var value float64
+	for _, row := range r.Rows {
+		value += row.Process.Value
+	}
+	return value * float64(max(1, r.Resolution)) / float64(r.Makespan)
+}
+
+// outputAlgorithmSummary writes the summary lines particular to s's algorithm, after
+// outputResult's: the total utility a UtilityAccrual schedule accrued, or each process's final
+// effective priority under Decay.
+func outputAlgorithmSummary(w io.Writer, s Scheduler, r ScheduleResult) {
+	switch s := s.(type) {
+	case UtilityAccrual:
+		_, _ = fmt.Fprintf(w, "Total utility: %.2f\n", TotalUtility(r, s.Utility))
+	case Decay:
+		_, _ = fmt.Fprint(w, "Final effective priority:")
+		for i, priority := range s.Priorities(r) {
+			_, _ = fmt.Fprintf(w, " %s %.2f", r.Rows[i].Process.ProcessID, priority)
+		}
+		_, _ = fmt.Fprintln(w)
+	}
+}
+
+// outputResult renders r as a GANTT chart and a table of timing.
+func outputResult(w io.Writer, title string, r ScheduleResult, opts RenderOptions) {
+	order := make([]int, len(r.Rows))
+	for i := range order {
+		order[i] = i
+	}
+	if opts.ByCompletion {
+		order = completionOrder(r)
+	}
+	schedule := make([][]string, len(r.Rows))
+	slowdowns := r.Slowdowns()
+	for n, i := range order {
+		row := r.Rows[i]
+		schedule[n] = []string{
+			fmt.Sprint(row.Process.ProcessID),
+			fmt.Sprint(row.Process.Priority),
+			fmt.Sprint(row.Process.BurstDuration),
+			fmt.Sprint(row.Process.ArrivalTime),
+			formatTime(row.Waiting, r.Resolution),
+			formatTime(row.Turnaround, r.Resolution),
+			formatTime(row.Completion, r.Resolution),
+		}
+		if opts.SlowdownAlert > 0 {
+			alert := ""
+			if slowdowns[i] > opts.SlowdownAlert {
+				alert = "badly delayed"
+			}
+			schedule[n] = append(schedule[n], fmt.Sprintf("%.2f", slowdowns[i]), alert)
+		}
+	}
+
+	outputTitle(w, title)
+	if !opts.NoGantt {
+		switch {
+		case r.Cores > 1:
+			outputCoreGantts(w, r)
+		case opts.Animate > 0:
+			animateGantt(w, r.Gantt, r.Resolution, opts.Animate, opts.Clock)
+		default:
+			outputGantt(w, r.Gantt, r.Resolution)
+		}
+		if opts.TimeAxis && len(r.Gantt) > 0 {
+			outputTimeAxis(w, r, opts.TickInterval, opts.ArrivalMarkers)
+		}
+	}
+	if !opts.NoTable {
+		outputSchedule(w, schedule, r, opts)
+	}
+}
 
 //endregion
@@ -0,0 +1,851 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSJFPriority_Schedule(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: "P0", ArrivalTime: 0, BurstDuration: 4, Priority: 1},
+		{ProcessID: "P1", ArrivalTime: 1, BurstDuration: 2, Priority: 1},
+		{ProcessID: "P2", ArrivalTime: 1, BurstDuration: 2, Priority: 3},
+	}
+	tests := []struct {
+		name string
+		cfg  SchedulerConfig
+		want []TimeSlice
+	}{
+		{
+			name: "lower value first",
+			want: []TimeSlice{
+				{PID: "P0", Start: 0, Stop: 4},
+				{PID: "P1", Start: 4, Stop: 6},
+				{PID: "P2", Start: 6, Stop: 8},
+			},
+		},
+		{
+			name: "higher value first",
+			cfg:  SchedulerConfig{HigherPriorityFirst: true},
+			want: []TimeSlice{
+				{PID: "P0", Start: 0, Stop: 4},
+				{PID: "P2", Start: 4, Stop: 6},
+				{PID: "P1", Start: 6, Stop: 8},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := SJFPriority{tt.cfg}.Schedule(processes)
+			if diff := cmp.Diff(got.Gantt, tt.want); diff != "" {
+				t.Errorf(diff)
+			}
+		})
+	}
+}
+
+func TestRR_ScheduleResolution(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: "P0", ArrivalTime: 0, BurstDuration: 1},
+		{ProcessID: "P1", ArrivalTime: 0, BurstDuration: 1},
+	}
+	tests := []struct {
+		name           string
+		rr             RR
+		wantGantt      []TimeSlice
+		wantTurnaround float64
+		wantThroughput float64
+	}{
+		{
+			name: "whole units",
+			rr:   RR{Quantum: 1},
+			wantGantt: []TimeSlice{
+				{PID: "P0", Start: 0, Stop: 1},
+				{PID: "P1", Start: 1, Stop: 2},
+			},
+			wantTurnaround: 1.5,
+			wantThroughput: 1,
+		},
+		{
+			name: "whole quantum in tenths",
+			rr:   RR{Quantum: 10, Resolution: 10},
+			wantGantt: []TimeSlice{
+				{PID: "P0", Start: 0, Stop: 10},
+				{PID: "P1", Start: 10, Stop: 20},
+			},
+			wantTurnaround: 1.5,
+			wantThroughput: 1,
+		},
+		{
+			name: "half unit quantum",
+			rr:   RR{Quantum: 5, Resolution: 10},
+			wantGantt: []TimeSlice{
+				{PID: "P0", Start: 0, Stop: 5},
+				{PID: "P1", Start: 5, Stop: 10},
+				{PID: "P0", Start: 10, Stop: 15},
+				{PID: "P1", Start: 15, Stop: 20},
+			},
+			wantTurnaround: 1.75,
+			wantThroughput: 1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := tt.rr.Schedule(processes)
+			if diff := cmp.Diff(got.Gantt, tt.wantGantt); diff != "" {
+				t.Errorf(diff)
+			}
+			if got.AvgTurnaround != tt.wantTurnaround {
+				t.Errorf("AvgTurnaround = %v, want %v", got.AvgTurnaround, tt.wantTurnaround)
+			}
+			if got.Throughput != tt.wantThroughput {
+				t.Errorf("Throughput = %v, want %v", got.Throughput, tt.wantThroughput)
+			}
+			if diff := cmp.Diff(got.Rows[0].Process, processes[0]); diff != "" {
+				t.Errorf("rows not in user units: %s", diff)
+			}
+		})
+	}
+}
+
+func TestNotify(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: "P0", ArrivalTime: 0, BurstDuration: 5},
+		{ProcessID: "P1", ArrivalTime: 1, BurstDuration: 1},
+		{ProcessID: "P2", ArrivalTime: 2, BurstDuration: 2},
+	}
+	hrrn, err := ParseScheduler("hrrn:preemptive")
+	if err != nil {
+		t.Fatal(err)
+	}
+	type completion struct {
+		PID  string
+		Time int64
+	}
+	tests := []struct {
+		s    Scheduler
+		want []completion
+	}{
+		{s: FCFS{}, want: []completion{{"P0", 5}, {"P1", 6}, {"P2", 8}}},
+		{s: SJF{}, want: []completion{{"P0", 5}, {"P1", 6}, {"P2", 8}}},
+		{s: SJFPriority{}, want: []completion{{"P0", 5}, {"P1", 6}, {"P2", 8}}},
+		{s: RR{Quantum: 2}, want: []completion{{"P1", 3}, {"P2", 5}, {"P0", 8}}},
+		{s: hrrn, want: []completion{{"P1", 3}, {"P2", 5}, {"P0", 8}}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.s.Name(), func(t *testing.T) {
+			t.Parallel()
+			var got []completion
+			Notify(tt.s, func(p Process, completionTime int64) {
+				got = append(got, completion{p.ProcessID, completionTime})
+			}).Schedule(processes)
+			if diff := cmp.Diff(got, tt.want); diff != "" {
+				t.Errorf(diff)
+			}
+
+			// Without a callback the scheduler runs as usual.
+			_ = Notify(tt.s, nil).Schedule(processes)
+		})
+	}
+}
+
+func TestSchedulerConfig_Checkpoints(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: "P0", ArrivalTime: 0, BurstDuration: 5},
+		{ProcessID: "C1", ArrivalTime: 2, BurstDuration: 0},
+		{ProcessID: "P2", ArrivalTime: 1, BurstDuration: 3},
+		{ProcessID: "C3", ArrivalTime: 8, BurstDuration: 0},
+	}
+	got := schedulerFor(fcfs, SchedulerConfig{Checkpoints: true}).Schedule(processes)
+
+	wantGantt := []TimeSlice{
+		{PID: "P0", Start: 0, Stop: 2},
+		{PID: "C1", Start: 2, Stop: 2},
+		{PID: "P0", Start: 2, Stop: 5},
+		{PID: "P2", Start: 5, Stop: 8},
+		{PID: "C3", Start: 8, Stop: 8},
+	}
+	if diff := cmp.Diff(got.Gantt, wantGantt); diff != "" {
+		t.Errorf("Gantt: %s", diff)
+	}
+	wantRows := []ProcessResult{
+		{Process: processes[0], Waiting: 0, Turnaround: 5, Completion: 5},
+		{Process: processes[1], Completion: 2},
+		{Process: processes[2], Waiting: 4, Turnaround: 7, Completion: 8},
+		{Process: processes[3], Completion: 8},
+	}
+	if diff := cmp.Diff(got.Rows, wantRows); diff != "" {
+		t.Errorf("Rows: %s", diff)
+	}
+	if got.AvgWait != 2 || got.AvgTurnaround != 6 || got.Throughput != 0.25 || got.Makespan != 8 {
+		t.Errorf("summary = %v/%v/%v/%v, want 2/6/0.25/8", got.AvgWait, got.AvgTurnaround, got.Throughput, got.Makespan)
+	}
+
+	onlyMarkers := schedulerFor(sjf, SchedulerConfig{Checkpoints: true}).Schedule(processes[1:2])
+	if onlyMarkers.Throughput != 0 || onlyMarkers.AvgWait != 0 {
+		t.Errorf("checkpoint-only workload: throughput %v, wait %v", onlyMarkers.Throughput, onlyMarkers.AvgWait)
+	}
+
+	var w bytes.Buffer
+	outputGantt(&w, got.Gantt, got.Resolution)
+	want := "Gantt schedule\n|  P0   |  ^C1  |  P0   |  P2   |  ^C3  |\n0       2       2       5       8       8\n\n"
+	if diff := cmp.Diff(w.String(), want); diff != "" {
+		t.Errorf("outputGantt: %s", diff)
+	}
+}
+
+func TestScheduleResult_AvgQueueLength(t *testing.T) {
+	t.Parallel()
+	schedulers := []Scheduler{
+		FCFS{}, SJF{}, SJFPriority{}, RR{Quantum: 3}, RR{Quantum: 5, Resolution: 10},
+		UtilityAccrual{}, HRRN{}, HRRN{Preemptive: true}, FairShare{Quantum: 2}, MultiCore{Cores: 3},
+		Decay{Quantum: 2, Usage: 1, Recovery: 0.5}, Custom{Select: SelectShortest},
+		SRTF{}, PreemptivePriority{}, MLFQ{Quanta: []int64{1, 2, 4}, Boost: 10},
+		MultiCore{Cores: 3, Dispatch: RoundRobinDispatch}, MultiCore{Cores: 3, Dispatch: LeastLoaded},
+		MultiCore{Cores: 3, Gang: true}, MultiCore{Cores: 3, Backfill: true}, GangRR{Cores: 3, Quantum: 2}, EDF{}, EDF{Cores: 3},
+		RRIO{Quantum: 2},
+	}
+	for _, s := range schedulers {
+		s := s
+		t.Run(s.Name(), func(t *testing.T) {
+			t.Parallel()
+			for seed := int64(0); seed < 10; seed++ {
+				r := s.Schedule(GenerateProcesses(12, seed))
+				if littles := r.Throughput * r.AvgWait; math.Abs(r.AvgQueueLength-littles) > 1e-9 {
+					t.Errorf("seed %d: measured L = %v, Little's Law L = %v", seed, r.AvgQueueLength, littles)
+				}
+			}
+		})
+	}
+}
+
+func TestSchedule_NoZeroWidthSlices(t *testing.T) {
+	t.Parallel()
+	schedulers := []Scheduler{
+		FCFS{}, SJF{}, SJFPriority{}, RR{Quantum: 1}, RR{Quantum: 5, Resolution: 10},
+		RR{Quantum: 2, SwitchPenalty: func(Process, int64) int64 { return 1 }},
+		HRRN{}, HRRN{Preemptive: true}, UtilityAccrual{}, FairShare{Quantum: 2}, MultiCore{Cores: 3},
+		Decay{Quantum: 1, Usage: 2, Recovery: 1}, SRTF{}, PreemptivePriority{}, GangRR{Cores: 3, Quantum: 2},
+		EDF{}, EDF{Cores: 3}, RRIO{Quantum: 2}, Custom{Select: SelectShortest},
+		CustomPreemptive{Select: SelectShortest}, MLFQ{Quanta: []int64{1, 2, 4}, Boost: 10}, schedulerFor(fcfs, SchedulerConfig{Checkpoints: true}),
+	}
+	for _, s := range schedulers {
+		s := s
+		t.Run(s.Name(), func(t *testing.T) {
+			t.Parallel()
+			for seed := int64(0); seed < 10; seed++ {
+				processes := GenerateProcesses(12, seed)
+				processes[seed%12].BurstDuration = 0
+				r := s.Schedule(processes)
+				for _, slice := range r.Gantt {
+					if slice.Start == slice.Stop && slice.PID != processes[seed%12].ProcessID {
+						t.Errorf("seed %d: zero-width slice %s", seed, sliceString(slice))
+					}
+				}
+			}
+		})
+	}
+}
+
+func Test_compactGantt(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: "P0", BurstDuration: 3},
+		{ProcessID: "C1", BurstDuration: 0},
+	}
+	gantt := []TimeSlice{
+		{PID: "P0", Start: 0, Stop: 0},
+		{PID: "~P0", Start: 0, Stop: 0},
+		{PID: "C1", Start: 0, Stop: 0},
+		{PID: "P0", Start: 0, Stop: 3},
+		{PID: "P0", Start: 3, Stop: 3},
+	}
+	want := []TimeSlice{
+		{PID: "C1", Start: 0, Stop: 0},
+		{PID: "P0", Start: 0, Stop: 3},
+	}
+	if diff := cmp.Diff(want, compactGantt(processes, gantt)); diff != "" {
+		t.Errorf(diff)
+	}
+}
+
+func TestRR_ScheduleSwitchPenalty(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: "P0", ArrivalTime: 0, BurstDuration: 3},
+		{ProcessID: "P1", ArrivalTime: 0, BurstDuration: 2},
+	}
+	cfg := SchedulerConfig{
+		Quantum:       2,
+		SwitchPenalty: func(p Process, offCPU int64) int64 { return offCPU / 2 },
+	}
+	got := schedulerFor(rr, cfg).Schedule(processes)
+
+	wantGantt := []TimeSlice{
+		{PID: "P0", Start: 0, Stop: 2},
+		{PID: "~P1", Start: 2, Stop: 3},
+		{PID: "P1", Start: 3, Stop: 5},
+		{PID: "~P0", Start: 5, Stop: 6},
+		{PID: "P0", Start: 6, Stop: 7},
+	}
+	if diff := cmp.Diff(got.Gantt, wantGantt); diff != "" {
+		t.Errorf("Gantt: %s", diff)
+	}
+	wantRows := []ProcessResult{
+		{Process: processes[0], Waiting: 4, Turnaround: 7, Completion: 7},
+		{Process: processes[1], Waiting: 3, Turnaround: 5, Completion: 5},
+	}
+	if diff := cmp.Diff(got.Rows, wantRows); diff != "" {
+		t.Errorf("Rows: %s", diff)
+	}
+	if littles := got.Throughput * got.AvgWait; math.Abs(got.AvgQueueLength-littles) > 1e-9 {
+		t.Errorf("measured L = %v, Little's Law L = %v", got.AvgQueueLength, littles)
+	}
+	wantEvents := []Event{
+		{Time: 0, Type: EventArrive, PID: "P0"},
+		{Time: 0, Type: EventArrive, PID: "P1"},
+		{Time: 0, Type: EventDispatch, PID: "P0"},
+		{Time: 2, Type: EventPreempt, PID: "P0"},
+		{Time: 2, Type: EventDispatch, PID: "P1"},
+		{Time: 5, Type: EventComplete, PID: "P1"},
+		{Time: 5, Type: EventDispatch, PID: "P0"},
+		{Time: 7, Type: EventComplete, PID: "P0"},
+	}
+	if diff := cmp.Diff(Events(got), wantEvents); diff != "" {
+		t.Errorf("Events: %s", diff)
+	}
+
+	// FCFS never preempts, so it pays no penalty.
+	if diff := cmp.Diff(schedulerFor(fcfs, cfg).Schedule(processes), FCFS{}.Schedule(processes)); diff != "" {
+		t.Errorf("FCFS: %s", diff)
+	}
+}
+
+func TestProcess_Pinned(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: "P0", ArrivalTime: 0, BurstDuration: 4},
+		{ProcessID: "P1", ArrivalTime: 1, BurstDuration: 1},
+		{ProcessID: "P2", ArrivalTime: 1, BurstDuration: 3, Pinned: true},
+		{ProcessID: "P3", ArrivalTime: 1, BurstDuration: 2, Pinned: true},
+	}
+	// The pinned processes run after P0, which they do not preempt, in the order the scheduler
+	// would run them anyway: in arrival order, shortest first, or round-robin.
+	inOrder := []TimeSlice{
+		{PID: "P0", Start: 0, Stop: 4},
+		{PID: "P2", Start: 4, Stop: 7},
+		{PID: "P3", Start: 7, Stop: 9},
+		{PID: "P1", Start: 9, Stop: 10},
+	}
+	shortest := []TimeSlice{
+		{PID: "P0", Start: 0, Stop: 4},
+		{PID: "P3", Start: 4, Stop: 6},
+		{PID: "P2", Start: 6, Stop: 9},
+		{PID: "P1", Start: 9, Stop: 10},
+	}
+	roundRobin := []TimeSlice{
+		{PID: "P0", Start: 0, Stop: 2},
+		{PID: "P2", Start: 2, Stop: 4},
+		{PID: "P3", Start: 4, Stop: 6},
+		{PID: "P2", Start: 6, Stop: 7},
+		{PID: "P1", Start: 7, Stop: 8},
+		{PID: "P0", Start: 8, Stop: 10},
+	}
+	tests := []struct {
+		name string
+		s    Scheduler
+		want []TimeSlice
+	}{
+		{name: "fcfs", s: FCFS{}, want: inOrder},
+		{name: "sjf", s: SJF{}, want: shortest},
+		{name: "rr", s: RR{Quantum: 2}, want: roundRobin},
+		{name: "hrrn", s: HRRN{}, want: shortest},
+		{name: "hrrn preemptive", s: HRRN{Preemptive: true}, want: shortest},
+		{name: "decay", s: Decay{Quantum: 2}, want: roundRobin},
+		{name: "fair", s: FairShare{Quantum: 2}, want: roundRobin},
+		{name: "ua", s: UtilityAccrual{}, want: shortest},
+		{name: "edf", s: EDF{}, want: inOrder},
+		{name: "rr-io", s: RRIO{Quantum: 2}, want: roundRobin},
+		{name: "mlfq", s: MLFQ{Quanta: []int64{2}}, want: roundRobin},
+		{name: "multicore", s: MultiCore{Cores: 1}, want: inOrder},
+		{name: "multicore backfill", s: MultiCore{Cores: 1, Backfill: true}, want: inOrder},
+		{name: "gang-rr", s: GangRR{Cores: 1, Quantum: 2}, want: roundRobin},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := tt.s.Schedule(processes)
+			if diff := cmp.Diff(got.Gantt, tt.want); diff != "" {
+				t.Errorf(diff)
+			}
+		})
+	}
+}
+
+func TestProcess_PinnedGroupsAndCores(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: "A", ArrivalTime: 0, BurstDuration: 4, GroupID: "a"},
+		{ProcessID: "B", ArrivalTime: 0, BurstDuration: 4, GroupID: "b"},
+		{ProcessID: "C", ArrivalTime: 1, BurstDuration: 2, GroupID: "c", Pinned: true},
+		{ProcessID: "D", ArrivalTime: 1, BurstDuration: 2, GroupID: "d", Deadline: 3},
+	}
+	// C's group takes the next turn, ahead of B's.
+	turns := []TimeSlice{
+		{PID: "A", Start: 0, Stop: 2},
+		{PID: "C", Start: 2, Stop: 4},
+		{PID: "B", Start: 4, Stop: 6},
+		{PID: "D", Start: 6, Stop: 8},
+		{PID: "A", Start: 8, Stop: 10},
+		{PID: "B", Start: 10, Stop: 12},
+	}
+	tests := []struct {
+		name string
+		s    Scheduler
+		want []TimeSlice
+	}{
+		{name: "fair", s: FairShare{Quantum: 2}, want: turns},
+		{name: "gang-rr", s: GangRR{Cores: 1, Quantum: 2}, want: turns},
+		{
+			// D's earlier deadline would preempt B, but it cannot go ahead of the pinned C.
+			name: "edf",
+			s:    EDF{Cores: 2},
+			want: []TimeSlice{
+				{PID: "A", Start: 0, Stop: 4},
+				{PID: "B", Start: 0, Stop: 4, Core: 1},
+				{PID: "C", Start: 4, Stop: 6},
+				{PID: "D", Start: 4, Stop: 6, Core: 1},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := tt.s.Schedule(processes)
+			if diff := cmp.Diff(got.Gantt, tt.want); diff != "" {
+				t.Errorf(diff)
+			}
+		})
+	}
+}
+
+func Test_outputResultByCompletion(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: "P0", ArrivalTime: 0, BurstDuration: 5},
+		{ProcessID: "P1", ArrivalTime: 1, BurstDuration: 1},
+		{ProcessID: "P2", ArrivalTime: 2, BurstDuration: 2},
+	}
+	r := RR{Quantum: 2}.Schedule(processes)
+	tests := []struct {
+		name string
+		opts RenderOptions
+		want []string
+	}{
+		{name: "input order", want: []string{"P0", "P1", "P2"}},
+		{name: "completion order", opts: RenderOptions{ByCompletion: true}, want: []string{"P1", "P2", "P0"}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var w bytes.Buffer
+			outputResult(&w, "Round-robin", r, tt.opts)
+			var got []string
+			for _, line := range strings.Split(w.String(), "\n") {
+				if fields := strings.Fields(line); strings.Count(line, "|") == 8 && fields[1] != "ID" {
+					got = append(got, fields[1])
+				}
+			}
+			if diff := cmp.Diff(got, tt.want); diff != "" {
+				t.Errorf(diff)
+			}
+		})
+	}
+}
+
+func Test_outputResultParts(t *testing.T) {
+	t.Parallel()
+	r := FCFS{}.Schedule([]Process{
+		{ProcessID: "P0", ArrivalTime: 0, BurstDuration: 2},
+		{ProcessID: "P1", ArrivalTime: 1, BurstDuration: 1},
+	})
+	tests := []struct {
+		name                 string
+		opts                 RenderOptions
+		wantGantt, wantTable bool
+	}{
+		{name: "both", wantGantt: true, wantTable: true},
+		{name: "no gantt", opts: RenderOptions{NoGantt: true, TimeAxis: true}, wantTable: true},
+		{name: "no table", opts: RenderOptions{NoTable: true}, wantGantt: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var w bytes.Buffer
+			outputResult(&w, "First-come, first-serve", r, tt.opts)
+			got := w.String()
+			if !strings.Contains(got, "First-come, first-serve") {
+				t.Errorf("title missing:\n%s", got)
+			}
+			if gantt := strings.Contains(got, "Gantt schedule") || strings.Contains(got, "|  P0  |"); gantt != tt.wantGantt {
+				t.Errorf("GANTT chart printed = %v, want %v:\n%s", gantt, tt.wantGantt, got)
+			}
+			if table := strings.Contains(got, "Schedule table") || strings.Contains(got, "Average wait"); table != tt.wantTable {
+				t.Errorf("table printed = %v, want %v:\n%s", table, tt.wantTable, got)
+			}
+		})
+	}
+}
+
+func TestScheduleResult_IdleTime(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: "P0", ArrivalTime: 2, BurstDuration: 1},
+		{ProcessID: "P1", ArrivalTime: 5, BurstDuration: 1},
+		{ProcessID: "P2", ArrivalTime: 6, BurstDuration: 2},
+	}
+	tests := []struct {
+		s        Scheduler
+		wantIdle int64
+	}{
+		{s: FCFS{}, wantIdle: 4},
+		{s: RR{Quantum: 5, Resolution: 10}, wantIdle: 40},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.s.Name(), func(t *testing.T) {
+			t.Parallel()
+			got := tt.s.Schedule(processes)
+			// Idle over [0, 2) and [3, 5).
+			if got.IdleTime != tt.wantIdle || got.AvgIdleGap != 2 {
+				t.Errorf("IdleTime, AvgIdleGap = %d, %v, want %d, 2", got.IdleTime, got.AvgIdleGap, tt.wantIdle)
+			}
+		})
+	}
+}
+
+func TestScheduleResult_ThroughputZeroMakespan(t *testing.T) {
+	t.Parallel()
+	processes := []Process{{ProcessID: "a"}, {ProcessID: "b", ArrivalTime: 0}}
+	for _, s := range []Scheduler{FCFS{}, SJF{}, RR{Quantum: 2, Resolution: 10}, SRTF{}, MultiCore{Cores: 2}, RRIO{Quantum: 2}} {
+		r := s.Schedule(processes)
+		if r.Throughput != 0 {
+			t.Errorf("%s: Throughput = %v, want 0", s.Name(), r.Throughput)
+		}
+		if _, err := json.Marshal(r); err != nil {
+			t.Errorf("%s: %v", s.Name(), err)
+		}
+		if err := ExportRun(io.Discard, s.Name(), processes, SchedulerConfig{}, r); err != nil {
+			t.Errorf("%s: ExportRun: %v", s.Name(), err)
+		}
+	}
+}
+
+func TestScheduleResult_Stretch(t *testing.T) {
+	t.Parallel()
+	// Idle over [0, 2) and [3, 5) doubles the makespan of 4 units of work.
+	idle := []Process{
+		{ProcessID: "P0", ArrivalTime: 2, BurstDuration: 1},
+		{ProcessID: "P1", ArrivalTime: 5, BurstDuration: 1},
+		{ProcessID: "P2", ArrivalTime: 6, BurstDuration: 2},
+	}
+	// Two cores over a makespan of 4 hold 8 units, and the work is 6.
+	uneven := []Process{
+		{ProcessID: "P0", BurstDuration: 4},
+		{ProcessID: "P1", BurstDuration: 2},
+	}
+	tests := []struct {
+		name      string
+		s         Scheduler
+		processes []Process
+		want      float64
+	}{
+		{name: "packed", s: FCFS{}, processes: uneven, want: 1},
+		{name: "idle", s: FCFS{}, processes: idle, want: 2},
+		{name: "ticks", s: RR{Quantum: 5, Resolution: 10}, processes: idle, want: 2},
+		{name: "cores", s: MultiCore{Cores: 2}, processes: uneven, want: 4.0 / 3},
+		{name: "no work", s: FCFS{}, processes: []Process{{ProcessID: "P0"}}, want: 0},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := tt.s.Schedule(tt.processes).Stretch(); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Stretch() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScheduleResult_Slowdowns(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: "P0", ArrivalTime: 0, BurstDuration: 4},
+		{ProcessID: "P1", ArrivalTime: 0, BurstDuration: 1},
+		{ProcessID: "Z", ArrivalTime: 0, BurstDuration: 0},
+	}
+	r := FCFS{}.Schedule(processes)
+	if diff := cmp.Diff([]float64{1, 5, 1}, r.Slowdowns()); diff != "" {
+		t.Errorf(diff)
+	}
+	// Under RR with a quantum of 1 unit, in ticks of a tenth, P1 waits only for P0's first slice.
+	if diff := cmp.Diff([]float64{1.25, 2, 1}, RR{Quantum: 10, Resolution: 10}.Schedule(processes).Slowdowns()); diff != "" {
+		t.Errorf("RR: %s", diff)
+	}
+
+	var w bytes.Buffer
+	outputResult(&w, "FCFS", r, RenderOptions{SlowdownAlert: 2})
+	for _, want := range []string{
+		"| SLOWDOWN |     ALERT     |",
+		"| P0 |        0 |     4 |       0 |    0 |          4 |    4 |     1.00 |               |",
+		"| P1 |        0 |     1 |       0 |    4 |          5 |    5 |     5.00 | badly delayed |",
+	} {
+		if !strings.Contains(w.String(), want) {
+			t.Errorf("table is missing %q:\n%s", want, w.String())
+		}
+	}
+	w.Reset()
+	outputResult(&w, "FCFS", r, RenderOptions{})
+	if strings.Contains(w.String(), "SLOWDOWN") {
+		t.Errorf("table without an alert has slowdowns:\n%s", w.String())
+	}
+}
+
+func TestScheduleResult_ValueThroughput(t *testing.T) {
+	t.Parallel()
+	// 3 + 0 + 1.5 of value over a makespan of 6 units.
+	processes := []Process{
+		{ProcessID: "P0", ArrivalTime: 0, BurstDuration: 2, Value: 3},
+		{ProcessID: "P1", ArrivalTime: 1, BurstDuration: 3},
+		{ProcessID: "P2", ArrivalTime: 2, BurstDuration: 1, Value: 1.5},
+	}
+	for _, s := range []Scheduler{FCFS{}, RR{Quantum: 5, Resolution: 10}, MultiCore{Cores: 3, Backfill: true}} {
+		r := s.Schedule(processes)
+		want := 4.5 / (float64(r.Makespan) / float64(max(1, r.Resolution)))
+		if got := r.ValueThroughput(); math.Abs(got-want) > 1e-9 {
+			t.Errorf("%s: ValueThroughput() = %v, want %v", s.Name(), got, want)
+		}
+	}
+	if got := (FCFS{}).Schedule(processes).ValueThroughput(); got != 0.75 {
+		t.Errorf("fcfs: ValueThroughput() = %v, want 0.75", got)
+	}
+	if got := (ScheduleResult{}).ValueThroughput(); got != 0 {
+		t.Errorf("empty: ValueThroughput() = %v, want 0", got)
+	}
+
+	var w bytes.Buffer
+	outputResult(&w, "Value", FCFS{}.Schedule(processes), RenderOptions{})
+	if !strings.Contains(w.String(), "Throughput: 0.50\nValue throughput: 0.75\n") {
+		t.Errorf("output is missing the value throughput:\n%s", w.String())
+	}
+}
+
+func TestCompletionOrder(t *testing.T) {
+	t.Parallel()
+	// On three cores, Pb and Pa complete together at 2, after P1 at 1 and before P0 at 4.
+	processes := []Process{
+		{ProcessID: "P0", ArrivalTime: 0, BurstDuration: 4},
+		{ProcessID: "Pb", ArrivalTime: 0, BurstDuration: 2},
+		{ProcessID: "Pa", ArrivalTime: 1, BurstDuration: 1},
+		{ProcessID: "P1", ArrivalTime: 0, BurstDuration: 1},
+	}
+	r := MultiCore{Cores: 3}.Schedule(processes)
+	if diff := cmp.Diff(CompletionOrder(r), []string{"P1", "Pa", "Pb", "P0"}); diff != "" {
+		t.Errorf(diff)
+	}
+	var w bytes.Buffer
+	outputResult(&w, "Completion", r, RenderOptions{})
+	if !strings.Contains(w.String(), "Completion order: P1, Pa, Pb, P0\n") {
+		t.Errorf("output is missing the completion order:\n%s", w.String())
+	}
+}
+
+func TestScheduleResult_RowsKeepIdentity(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: "P3", ArrivalTime: 4, BurstDuration: 1, Priority: 2},
+		{ProcessID: "P1", ArrivalTime: 0, BurstDuration: 6, Priority: 3},
+		{ProcessID: "P2", ArrivalTime: 1, BurstDuration: 3, Priority: 1, Meta: map[string]string{"job": "42"}},
+		{ProcessID: "P0", ArrivalTime: 1, BurstDuration: 2, Priority: 1},
+	}
+	input := append([]Process(nil), processes...)
+	schedulers := []Scheduler{
+		FCFS{}, SJF{}, SJFPriority{}, RR{Quantum: 2}, RR{Quantum: 5, Resolution: 10},
+		HRRN{}, HRRN{Preemptive: true}, UtilityAccrual{}, FairShare{Quantum: 2}, MultiCore{Cores: 2},
+		Decay{Quantum: 1, Usage: 1, Recovery: 0.5}, GangRR{Cores: 2, Quantum: 2}, EDF{Cores: 2}, RRIO{Quantum: 2},
+		MLFQ{Quanta: []int64{1, 2, 4}, Boost: 10}, PowerDown{Scheduler: SJF{}, WakeLatency: 1},
+	}
+	for _, s := range schedulers {
+		s := s
+		t.Run(s.Name(), func(t *testing.T) {
+			t.Parallel()
+			r := s.Schedule(processes)
+			if diff := cmp.Diff(processes, input); diff != "" {
+				t.Fatalf("input modified: %s", diff)
+			}
+			lastStop := make(map[string]int64)
+			for _, slice := range r.Gantt {
+				lastStop[slice.PID] = max(lastStop[slice.PID], slice.Stop)
+			}
+			scale := max(1, r.Resolution)
+			for i, row := range r.Rows {
+				p := processes[i]
+				if diff := cmp.Diff(row.Process, p); diff != "" {
+					t.Errorf("row %d is %s, want %s", i, row.Process.ProcessID, p.ProcessID)
+					continue
+				}
+				if row.Completion != lastStop[p.ProcessID] {
+					t.Errorf("%s: completion %d, but its last slice stops at %d", p.ProcessID, row.Completion, lastStop[p.ProcessID])
+				}
+				if row.Turnaround != row.Completion-p.ArrivalTime*scale {
+					t.Errorf("%s: turnaround %d does not match completion %d and arrival %d", p.ProcessID, row.Turnaround, row.Completion, p.ArrivalTime)
+				}
+			}
+		})
+	}
+}
+
+func TestProcess_DependsOn(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: "P0", ArrivalTime: 0, BurstDuration: 1},
+		{ProcessID: "P1", ArrivalTime: 0, BurstDuration: 1, DependsOn: []string{"P2"}},
+		{ProcessID: "P2", ArrivalTime: 3, BurstDuration: 2},
+	}
+	// P1 has arrived but waits, with the CPU idle, until P2 arrives and completes.
+	want := []TimeSlice{
+		{PID: "P0", Start: 0, Stop: 1},
+		{PID: "P2", Start: 3, Stop: 5},
+		{PID: "P1", Start: 5, Stop: 6},
+	}
+	cycle := []Process{
+		{ProcessID: "P0", ArrivalTime: 0, BurstDuration: 1, DependsOn: []string{"P1"}},
+		{ProcessID: "P1", ArrivalTime: 0, BurstDuration: 1, DependsOn: []string{"P0"}},
+	}
+	schedulers := []Scheduler{
+		FCFS{}, SJF{}, SJFPriority{}, RR{Quantum: 2}, HRRN{}, HRRN{Preemptive: true},
+		UtilityAccrual{}, FairShare{Quantum: 2}, MultiCore{Cores: 1}, Decay{Quantum: 2}, SRTF{},
+	}
+	for _, s := range schedulers {
+		s := s
+		t.Run(s.Name(), func(t *testing.T) {
+			t.Parallel()
+			got := s.Schedule(processes)
+			if diff := cmp.Diff(got.Gantt, want); diff != "" {
+				t.Errorf(diff)
+			}
+			if got.Rows[1].Waiting != 5 {
+				t.Errorf("P1 waited %d, want 5", got.Rows[1].Waiting)
+			}
+			// The CPU idles over [1, 3) with P1 blocked on P2, which is not a violation.
+			if v := WorkConservationViolations(processes, got.Gantt); len(v) > 0 {
+				t.Errorf("idle while ready: %+v", v)
+			}
+
+			// A cycle, which ValidateProcesses rejects, still finishes.
+			if r := s.Schedule(cycle); r.Makespan != 2 {
+				t.Errorf("cycle: makespan %d, want 2", r.Makespan)
+			}
+		})
+	}
+}
+
+func TestBoundary(t *testing.T) {
+	t.Parallel()
+	// P2 arrives as P0 completes.
+	sjfWorkload := []Process{
+		{ProcessID: "P0", ArrivalTime: 0, BurstDuration: 4},
+		{ProcessID: "P1", ArrivalTime: 1, BurstDuration: 3},
+		{ProcessID: "P2", ArrivalTime: 4, BurstDuration: 1},
+	}
+	// P1 arrives as P0 is preempted.
+	rrWorkload := []Process{
+		{ProcessID: "P0", ArrivalTime: 0, BurstDuration: 4},
+		{ProcessID: "P1", ArrivalTime: 2, BurstDuration: 2},
+	}
+	tests := []struct {
+		name      string
+		algo      Algorithm
+		boundary  Boundary
+		processes []Process
+		want      []string
+	}{
+		{name: "sjf arrivals first", algo: sjf, processes: sjfWorkload, want: []string{"P0", "P2", "P1"}},
+		{name: "sjf decision first", algo: sjf, boundary: DecisionFirst, processes: sjfWorkload, want: []string{"P0", "P1", "P2"}},
+		{name: "rr arrivals first", algo: rr, processes: rrWorkload, want: []string{"P0", "P1", "P0"}},
+		{name: "rr decision first", algo: rr, boundary: DecisionFirst, processes: rrWorkload, want: []string{"P0", "P0", "P1"}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			r := schedulerFor(tt.algo, SchedulerConfig{Quantum: 2, Boundary: tt.boundary}).Schedule(tt.processes)
+			var got []string
+			for _, slice := range r.Gantt {
+				got = append(got, slice.PID)
+			}
+			if diff := cmp.Diff(got, tt.want); diff != "" {
+				t.Errorf(diff)
+			}
+			if littles := r.Throughput * r.AvgWait; math.Abs(r.AvgQueueLength-littles) > 1e-9 {
+				t.Errorf("measured L = %v, Little's Law L = %v", r.AvgQueueLength, littles)
+			}
+		})
+	}
+}
+
+func TestSchedulerEquivalences(t *testing.T) {
+	t.Parallel()
+	simultaneous := func(processes []Process) []Process {
+		for i := range processes {
+			processes[i].ArrivalTime = 0
+		}
+		return processes
+	}
+	tests := []struct {
+		name  string
+		a, b  Scheduler
+		alter func([]Process) []Process // applied to each workload, if set.
+	}{
+		{name: "rr with a huge quantum is fcfs", a: RR{Quantum: math.MaxInt64}, b: FCFS{}},
+		{name: "srtf on simultaneous arrivals is sjf", a: SRTF{}, b: SJF{}, alter: simultaneous},
+		{name: "custom first come is fcfs", a: Custom{Select: SelectFirstCome}, b: FCFS{}},
+		{name: "custom shortest is sjf", a: Custom{Select: SelectShortest}, b: SJF{}},
+		{name: "custom preemptive shortest is srtf", a: CustomPreemptive{Select: SelectShortest}, b: SRTF{}},
+		{name: "one backfilling core is fcfs", a: MultiCore{Cores: 1, Backfill: true}, b: MultiCore{Cores: 1}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			for seed := int64(0); seed < 20; seed++ {
+				processes := GenerateProcesses(10, seed)
+				if tt.alter != nil {
+					processes = tt.alter(processes)
+				}
+				if diff := cmp.Diff(tt.a.Schedule(processes), tt.b.Schedule(processes)); diff != "" {
+					t.Errorf("seed %d: %s", seed, diff)
+				}
+			}
+		})
+	}
+}
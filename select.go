@@ -0,0 +1,237 @@
+package main
+
+// SelectFunc chooses the process to run next from ready, the processes that have arrived and
+// are waiting for the CPU in the order they became ready, at time now. It returns an index into
+// ready; an index out of range runs ready[0]. ready is never empty, and changes to it are
+// discarded.
+type SelectFunc func(ready []Process, now int64) int
+
+// Custom schedules processes non-preemptively with a caller's selection rule: whenever the CPU
+// is free it runs the process Select chooses, each to completion. If any ready processes are
+// pinned, Select chooses among only those. Boundary is as in SchedulerConfig.
+//
+// SelectFirstCome, SelectShortest and SelectSJFPriority are the rules of FCFS, SJF and
+// SJFPriority, and Custom with one schedules exactly as the built-in does. The built-ins keep a
+// heap in the same order instead, so that a decision takes O(log n) time rather than a pass
+// over the whole ready queue.
+type Custom struct {
+	Select   SelectFunc
+	Boundary Boundary
+}
+
+func (Custom) Name() string { return "custom" }
+
+func (s Custom) Schedule(processes []Process) ScheduleResult {
+	return s.scheduleClocked(processes, nil)
+}
+
+func (s Custom) scheduleClocked(processes []Process, clock *decisionClock) ScheduleResult {
+	return nonPreemptive(processes, &selectQueue{processes: processes, choose: s.Select}, s.Boundary, clock)
+}
+
+// selectFirst returns the SelectFunc that picks the first process in ready that sorts first by
+// less.
+func selectFirst(less func(a, b Process) bool) SelectFunc {
+	return func(ready []Process, _ int64) int {
+		best := 0
+		for k := range ready {
+			if less(ready[k], ready[best]) {
+				best = k
+			}
+		}
+		return best
+	}
+}
+
+// SelectFirstCome is the FCFS rule: the process that has been ready longest.
+func SelectFirstCome(ready []Process, now int64) int { return 0 }
+
+// SelectShortest is the SJF rule: the shortest burst, ties going to the process ready longest.
+var SelectShortest SelectFunc = selectFirst(shorter)
+
+// SelectSJFPriority is the SJFPriority rule under cfg: the shortest burst, then the most
+// urgent priority, then the process ready longest.
+func SelectSJFPriority(cfg SchedulerConfig) SelectFunc {
+	return selectFirst(cfg.shorterThenPriority)
+}
+
+// CustomPreemptive schedules processes preemptively with a caller's selection rule. Select is
+// called again at every reevaluation point: when a process arrives, when the running process
+// completes, and when the CPU is free. Whenever it chooses a process other than the running
+// one, the running process is preempted and goes back to waiting.
+//
+// Select is offered the running process first, if there is one, and then the waiting
+// processes in the order they became ready, each with BurstDuration set to the burst it has
+// left, so SelectShortest is shortest-remaining-time-first. A preempted process waits again in
+// arrival order. A rule that keeps the earlier process on ties never preempts for an equal one.
+// If any waiting processes are pinned, Select chooses between the running process and those.
+type CustomPreemptive struct {
+	Select SelectFunc
+}
+
+func (CustomPreemptive) Name() string { return "custom-preemptive" }
+
+func (s CustomPreemptive) Schedule(processes []Process) ScheduleResult {
+	return s.scheduleClocked(processes, nil)
+}
+
+func (s CustomPreemptive) scheduleClocked(processes []Process, clock *decisionClock) ScheduleResult {
+	return preemptive(processes, s.Select, clock)
+}
+
+// SRTF schedules processes shortest-remaining-time-first: SJF that preempts the running
+// process when a process arrives with a strictly shorter remaining burst. It is
+// CustomPreemptive with SelectShortest, and so scans the ready processes at each decision
+// rather than keeping them on a heap: it trades the O(log n) pick of SJF's readyQueue for
+// sharing the one preemptive engine, and its tie-breaking, with the other preemptive schedulers.
+type SRTF struct{}
+
+func (SRTF) Name() string { return "srtf" }
+
+func (s SRTF) Schedule(processes []Process) ScheduleResult {
+	return s.scheduleClocked(processes, nil)
+}
+
+func (s SRTF) scheduleClocked(processes []Process, clock *decisionClock) ScheduleResult {
+	return preemptive(processes, SelectShortest, clock)
+}
+
+// PreemptivePriority schedules processes by priority alone, preempting the running process
+// when a process arrives with a strictly more urgent priority under HigherPriorityFirst. It is
+// CustomPreemptive with SelectPriority; the other configuration is ignored.
+type PreemptivePriority struct {
+	SchedulerConfig
+}
+
+func (PreemptivePriority) Name() string { return "priority-preemptive" }
+
+func (s PreemptivePriority) Schedule(processes []Process) ScheduleResult {
+	return s.scheduleClocked(processes, nil)
+}
+
+func (s PreemptivePriority) scheduleClocked(processes []Process, clock *decisionClock) ScheduleResult {
+	return preemptive(processes, SelectPriority(s.SchedulerConfig), clock)
+}
+
+// SelectPriority is the rule of PreemptivePriority under cfg: the most urgent priority, ties
+// going to the earlier process.
+func SelectPriority(cfg SchedulerConfig) SelectFunc {
+	return selectFirst(cfg.morePriority)
+}
+
+// preemptive runs processes on a single CPU, reevaluating choose at every arrival and
+// completion as CustomPreemptive describes.
+func preemptive(processes []Process, choose SelectFunc, clock *decisionClock) ScheduleResult {
+	arr := newArrivals(processes)
+	remaining := make([]int64, len(processes))
+	for i := range processes {
+		remaining[i] = processes[i].BurstDuration
+	}
+
+	var (
+		now       int64
+		running   = -1
+		waiting   []int // released and neither running nor complete.
+		queueArea int64 // integral of the ready queue length over time.
+		timings   = make([]processTiming, len(processes))
+		gantt     = make([]TimeSlice, 0, len(processes))
+		dog       watchdog
+		stuck     = unfinished(processes, remaining)
+	)
+	offer := func(i int) Process {
+		p := processes[i]
+		p.BurstDuration = remaining[i]
+		return p
+	}
+	for done := 0; done < len(processes); {
+		arr.release(now, func(i int) {
+			// Processes held back by a prerequisite have been waiting since arrival.
+			queueArea += now - processes[i].ArrivalTime
+			waiting = append(waiting, i)
+		})
+		if running < 0 && len(waiting) == 0 {
+			// CPU is idle until the next arrival.
+			now = arr.idleUntil(now)
+			continue
+		}
+
+		dog.observe(now, done, stuck)
+		t0 := clock.start()
+		var candidates []int // positions in waiting, or -1 for the running process.
+		for n, i := range waiting {
+			if processes[i].Pinned {
+				candidates = append(candidates, n)
+			}
+		}
+		if len(candidates) == 0 {
+			for n := range waiting {
+				candidates = append(candidates, n)
+			}
+		}
+		if running >= 0 {
+			candidates = append([]int{-1}, candidates...)
+		}
+		offered := make([]Process, len(candidates))
+		for k, n := range candidates {
+			if n < 0 {
+				offered[k] = offer(running)
+			} else {
+				offered[k] = offer(waiting[n])
+			}
+		}
+		k := choose(offered, now)
+		if k < 0 || k >= len(candidates) {
+			k = 0
+		}
+		if n := candidates[k]; n >= 0 {
+			next := waiting[n]
+			waiting = append(waiting[:n], waiting[n+1:]...)
+			if running >= 0 {
+				waiting = insertByArrival(waiting, running, processes)
+			}
+			running = next
+		}
+		clock.stop(t0)
+
+		run := remaining[running]
+		if t, ok := arr.nextArrival(); ok && t-now < run {
+			run = t - now
+		}
+		if n := len(gantt); n > 0 && gantt[n-1].PID == processes[running].ProcessID && gantt[n-1].Stop == now {
+			gantt[n-1].Stop += run
+		} else {
+			gantt = append(gantt, TimeSlice{PID: processes[running].ProcessID, Start: now, Stop: now + run})
+		}
+		queueArea += int64(len(waiting)) * run
+		now += run
+		remaining[running] -= run
+		if remaining[running] > 0 {
+			continue
+		}
+
+		done++
+		arr.complete(running)
+		p := processes[running]
+		timings[running] = processTiming{
+			Waiting:    now - p.ArrivalTime - p.BurstDuration,
+			Turnaround: now - p.ArrivalTime,
+			Completion: now,
+		}
+		running = -1
+	}
+
+	return newScheduleResult(processes, gantt, timings).withQueueArea(queueArea)
+}
+
+// insertByArrival returns waiting with i put back after every process that arrived no later
+// than it.
+func insertByArrival(waiting []int, i int, processes []Process) []int {
+	n := len(waiting)
+	for n > 0 && processes[waiting[n-1]].ArrivalTime > processes[i].ArrivalTime {
+		n--
+	}
+	waiting = append(waiting, 0)
+	copy(waiting[n+1:], waiting[n:])
+	waiting[n] = i
+	return waiting
+}
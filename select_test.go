@@ -0,0 +1,174 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// TestCustom_BuiltIns checks that each built-in non-preemptive scheduler, which keeps its own
+// heap, decides exactly as Custom with its SelectFunc, so the two cannot drift apart.
+func TestCustom_BuiltIns(t *testing.T) {
+	t.Parallel()
+	higherFirst := SchedulerConfig{HigherPriorityFirst: true}
+	decisionFirst := SchedulerConfig{Boundary: DecisionFirst}
+	tests := []struct {
+		name    string
+		custom  Custom
+		builtIn Scheduler
+	}{
+		{name: "fcfs", custom: Custom{Select: SelectFirstCome}, builtIn: FCFS{}},
+		{name: "fcfs decision first", custom: Custom{Select: SelectFirstCome, Boundary: DecisionFirst}, builtIn: FCFS{Boundary: DecisionFirst}},
+		{name: "sjf", custom: Custom{Select: SelectShortest}, builtIn: SJF{}},
+		{name: "sjf decision first", custom: Custom{Select: SelectShortest, Boundary: DecisionFirst}, builtIn: SJF{Boundary: DecisionFirst}},
+		{name: "sjfp", custom: Custom{Select: SelectSJFPriority(SchedulerConfig{})}, builtIn: SJFPriority{}},
+		{name: "sjfp higher first", custom: Custom{Select: SelectSJFPriority(higherFirst)}, builtIn: SJFPriority{higherFirst}},
+		{name: "sjfp decision first", custom: Custom{Select: SelectSJFPriority(decisionFirst), Boundary: DecisionFirst}, builtIn: SJFPriority{decisionFirst}},
+	}
+	// Workloads with clustered and simultaneous arrivals and equal bursts, where the tie-breaking
+	// rules decide, as well as spread-out ones.
+	workloads := []GenerateOptions{
+		{},
+		{Arrivals: Poisson, ArrivalRate: 3},
+		{Arrivals: Bursty, ClusterSize: 8, ClusterGap: 5},
+		{Bursts: Exponential, MeanBurst: 2},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			for seed := int64(0); seed < 50; seed++ {
+				for _, opts := range workloads {
+					processes := GenerateProcessesWith(1+int(seed%40), seed, opts)
+					processes[seed%int64(len(processes))].Pinned = seed%3 != 0
+					if diff := cmp.Diff(tt.custom.Schedule(processes), tt.builtIn.Schedule(processes)); diff != "" {
+						t.Errorf("seed %d, %+v: %s", seed, opts, diff)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestCustom_Schedule(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: "P0", ArrivalTime: 0, BurstDuration: 2},
+		{ProcessID: "P1", ArrivalTime: 1, BurstDuration: 3},
+		{ProcessID: "P2", ArrivalTime: 1, BurstDuration: 5},
+		{ProcessID: "P3", ArrivalTime: 1, BurstDuration: 1},
+	}
+	longest := func(ready []Process, now int64) int {
+		best := 0
+		for k, p := range ready {
+			if p.BurstDuration > ready[best].BurstDuration {
+				best = k
+			}
+		}
+		return best
+	}
+	got := Custom{Select: longest}.Schedule(processes)
+	want := []TimeSlice{
+		{PID: "P0", Start: 0, Stop: 2},
+		{PID: "P2", Start: 2, Stop: 7},
+		{PID: "P1", Start: 7, Stop: 10},
+		{PID: "P3", Start: 10, Stop: 11},
+	}
+	if diff := cmp.Diff(got.Gantt, want); diff != "" {
+		t.Errorf(diff)
+	}
+
+	// An index out of range runs the process ready longest.
+	outOfRange := func([]Process, int64) int { return 99 }
+	if diff := cmp.Diff(Custom{Select: outOfRange}.Schedule(processes), FCFS{}.Schedule(processes)); diff != "" {
+		t.Errorf("out of range: %s", diff)
+	}
+}
+
+func TestCustomPreemptive_SRTF(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: "P0", ArrivalTime: 0, BurstDuration: 8},
+		{ProcessID: "P1", ArrivalTime: 1, BurstDuration: 4},
+		{ProcessID: "P2", ArrivalTime: 2, BurstDuration: 9},
+		{ProcessID: "P3", ArrivalTime: 3, BurstDuration: 5},
+	}
+	// A rule written from scratch: the least burst left, keeping the earlier process on ties.
+	leastRemaining := func(ready []Process, now int64) int {
+		best := 0
+		for k, p := range ready {
+			if p.BurstDuration < ready[best].BurstDuration {
+				best = k
+			}
+		}
+		return best
+	}
+	custom := CustomPreemptive{Select: leastRemaining}
+	got := custom.Schedule(processes)
+	want := []TimeSlice{
+		{PID: "P0", Start: 0, Stop: 1},
+		{PID: "P1", Start: 1, Stop: 5},
+		{PID: "P3", Start: 5, Stop: 10},
+		{PID: "P0", Start: 10, Stop: 17},
+		{PID: "P2", Start: 17, Stop: 26},
+	}
+	if diff := cmp.Diff(got.Gantt, want); diff != "" {
+		t.Errorf(diff)
+	}
+	if got.AvgWait != 6.5 {
+		t.Errorf("AvgWait = %v, want 6.5", got.AvgWait)
+	}
+
+	for seed := int64(0); seed < 10; seed++ {
+		processes := GenerateProcesses(15, seed)
+		if diff := cmp.Diff(custom.Schedule(processes), SRTF{}.Schedule(processes)); diff != "" {
+			t.Errorf("seed %d: %s", seed, diff)
+		}
+	}
+}
+
+func TestPreemptivePriority_Schedule(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: "P0", ArrivalTime: 0, BurstDuration: 5, Priority: 3},
+		{ProcessID: "P1", ArrivalTime: 1, BurstDuration: 3, Priority: 1},
+		{ProcessID: "P2", ArrivalTime: 2, BurstDuration: 1, Priority: 2},
+		{ProcessID: "P3", ArrivalTime: 4, BurstDuration: 1, Priority: 1},
+	}
+	tests := []struct {
+		name string
+		cfg  SchedulerConfig
+		want []TimeSlice
+	}{
+		{
+			// P1 completes at 4 as P3 arrives, and P3 is more urgent than P2.
+			name: "lower first",
+			want: []TimeSlice{
+				{PID: "P0", Start: 0, Stop: 1},
+				{PID: "P1", Start: 1, Stop: 4},
+				{PID: "P3", Start: 4, Stop: 5},
+				{PID: "P2", Start: 5, Stop: 6},
+				{PID: "P0", Start: 6, Stop: 10},
+			},
+		},
+		{
+			name: "higher first",
+			cfg:  SchedulerConfig{HigherPriorityFirst: true},
+			want: []TimeSlice{
+				{PID: "P0", Start: 0, Stop: 5},
+				{PID: "P2", Start: 5, Stop: 6},
+				{PID: "P1", Start: 6, Stop: 9},
+				{PID: "P3", Start: 9, Stop: 10},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if diff := cmp.Diff(PreemptivePriority{tt.cfg}.Schedule(processes).Gantt, tt.want); diff != "" {
+				t.Errorf(diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// SharingResult is the outcome of ProcessorSharing. Completions are in the order the processes
+// were given, and every time is in time units, generally fractional.
+type SharingResult struct {
+	Processes     []Process
+	Completions   []float64
+	AvgWait       float64
+	AvgTurnaround float64
+}
+
+// ProcessorSharing schedules processes on an ideal processor-sharing CPU, on which every ready
+// process runs at once, each at 1/n of full speed while n are ready. It is the limit of
+// round-robin as the quantum goes to 0, with no switch cost, so it is the baseline for how fair
+// RR can be. A process is ready once it has arrived and every process it depends on has
+// completed, and its wait is its turnaround less its burst.
+func ProcessorSharing(processes []Process) SharingResult {
+	const epsilon = 1e-9
+	arr := newArrivals(processes)
+	remaining := make([]float64, len(processes))
+	for i, p := range processes {
+		remaining[i] = float64(p.BurstDuration)
+	}
+	res := SharingResult{Processes: processes, Completions: make([]float64, len(processes))}
+
+	var (
+		now   float64
+		ready []int
+	)
+	for done := 0; done < len(processes); {
+		// Arrivals are whole, so a process has arrived by now if it arrived by its floor.
+		arr.release(int64(math.Floor(now+epsilon)), func(i int) { ready = append(ready, i) })
+		if len(ready) == 0 {
+			now = math.Max(now, float64(arr.idleUntil(int64(math.Floor(now+epsilon)))))
+			continue
+		}
+		// Run until the next completion or arrival, whichever is first.
+		least := remaining[ready[0]]
+		for _, i := range ready[1:] {
+			least = math.Min(least, remaining[i])
+		}
+		dt := least * float64(len(ready))
+		if next, ok := arr.nextArrival(); ok {
+			dt = math.Min(dt, float64(next)-now)
+		}
+		now += dt
+		share := dt / float64(len(ready))
+		kept := ready[:0]
+		for _, i := range ready {
+			remaining[i] -= share
+			if remaining[i] > epsilon {
+				kept = append(kept, i)
+				continue
+			}
+			res.Completions[i] = now
+			arr.complete(i)
+			done++
+		}
+		ready = kept
+	}
+
+	for i, p := range processes {
+		turnaround := res.Completions[i] - float64(p.ArrivalTime)
+		res.AvgTurnaround += turnaround
+		res.AvgWait += turnaround - float64(p.BurstDuration)
+	}
+	if len(processes) > 0 {
+		res.AvgTurnaround /= float64(len(processes))
+		res.AvgWait /= float64(len(processes))
+	}
+	return res
+}
+
+// outputProcessorSharing writes the completions of ps, to two decimal places, and its averages
+// next to r's, the schedule of the same processes, to show how close r comes to ideal sharing.
+func outputProcessorSharing(w io.Writer, ps SharingResult, r ScheduleResult) {
+	parts := make([]string, len(ps.Processes))
+	for i, p := range ps.Processes {
+		parts[i] = p.ProcessID + " " + strconv.FormatFloat(math.Round(100*ps.Completions[i])/100, 'f', -1, 64)
+	}
+	_, _ = fmt.Fprintf(w, "Processor sharing completions: %s\n", strings.Join(parts, ", "))
+	_, _ = fmt.Fprintf(w, "Processor sharing: average wait %.2f, turnaround %.2f; this schedule: %.2f, %.2f\n",
+		ps.AvgWait, ps.AvgTurnaround, r.AvgWait, r.AvgTurnaround)
+}
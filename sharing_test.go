@@ -0,0 +1,87 @@
+package main
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestProcessorSharing(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		processes []Process
+		want      []float64
+	}{
+		{name: "none"},
+		{
+			// A and B share the CPU until C arrives at 1 and all three share it.
+			name: "arrivals",
+			processes: []Process{
+				{ProcessID: "A", ArrivalTime: 0, BurstDuration: 2},
+				{ProcessID: "B", ArrivalTime: 0, BurstDuration: 4},
+				{ProcessID: "C", ArrivalTime: 1, BurstDuration: 1},
+			},
+			want: []float64{5, 7, 4},
+		},
+		{
+			name: "idle gap and zero burst",
+			processes: []Process{
+				{ProcessID: "A", ArrivalTime: 0, BurstDuration: 1},
+				{ProcessID: "Z", ArrivalTime: 1, BurstDuration: 0},
+				{ProcessID: "B", ArrivalTime: 3, BurstDuration: 2},
+			},
+			want: []float64{1, 1, 5},
+		},
+		{
+			// B only becomes ready when A completes, at a fraction of a time unit.
+			name: "dependency",
+			processes: []Process{
+				{ProcessID: "A", ArrivalTime: 0, BurstDuration: 1},
+				{ProcessID: "C", ArrivalTime: 0, BurstDuration: 3},
+				{ProcessID: "D", ArrivalTime: 1, BurstDuration: 3},
+				{ProcessID: "B", ArrivalTime: 0, BurstDuration: 1, DependsOn: []string{"A"}},
+			},
+			want: []float64{2.5, 7.5, 8, 5.5},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := ProcessorSharing(tt.processes)
+			if diff := cmp.Diff(tt.want, got.Completions, cmpopts.EquateApprox(0, 1e-9), cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf(diff)
+			}
+		})
+	}
+}
+
+func TestProcessorSharing_RRLimit(t *testing.T) {
+	t.Parallel()
+	// A round-robin quantum of 1/100 of a time unit completes each process within a few
+	// quanta per competitor of ideal sharing.
+	processes := GenerateProcesses(8, 3)
+	ps := ProcessorSharing(processes)
+	rr := RR{Quantum: 1, Resolution: 100}.Schedule(processes)
+	for i, row := range rr.Rows {
+		if got := float64(row.Completion) / 100; math.Abs(got-ps.Completions[i]) > 0.1 {
+			t.Errorf("%s: RR completes at %v, processor sharing at %v", row.Process.ProcessID, got, ps.Completions[i])
+		}
+	}
+
+	var w bytes.Buffer
+	outputProcessorSharing(&w, ProcessorSharing([]Process{
+		{ProcessID: "A", ArrivalTime: 0, BurstDuration: 2},
+		{ProcessID: "B", ArrivalTime: 0, BurstDuration: 4},
+		{ProcessID: "C", ArrivalTime: 1, BurstDuration: 1},
+	}), ScheduleResult{AvgWait: 2, AvgTurnaround: 4.33})
+	want := "Processor sharing completions: A 5, B 7, C 4\n" +
+		"Processor sharing: average wait 2.67, turnaround 5.00; this schedule: 2.00, 4.33\n"
+	if diff := cmp.Diff(want, w.String()); diff != "" {
+		t.Errorf(diff)
+	}
+}
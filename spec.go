@@ -0,0 +1,340 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParseScheduler returns the Scheduler described by spec, an algorithm name followed by
+// colon-separated parameters, each either key=value or a bare key for a true flag:
+//
+//	fcfs
+//	sjf
+//	sjfp:higher-priority-first   (also spelled priority)
+//	priority:preemptive   (priority alone, preemptive)
+//	srtf
+//	rr:quantum=4:resolution=10   (quantum defaults to 2)
+//	hrrn:preemptive
+//	ua
+//	fair:quantum=2:weights=web=2,batch=1   (quantum defaults to 2, weights to 1)
+//	decay:quantum=2:usage=1:recovery=0.5   (the defaults)
+//	multicore:cores=4:dispatch=least-loaded   (cores defaults to 2, dispatch to first-free)
+//	multicore:cores=4:backfill   (also gang, for one queue without backfilling)
+//	gang-rr:cores=4:quantum=2   (cores defaults to 2, quantum to 2)
+//	edf:cores=2   (cores defaults to 1)
+//	rr-io:quantum=4   (quantum defaults to 2)
+//	mlfq:quanta=2,4,8:boost=50   (quanta defaults to 2,4,8, boost to 0, never)
+func ParseScheduler(spec string) (Scheduler, error) {
+	fields := strings.Split(strings.TrimSpace(spec), ":")
+	name, params := strings.ToLower(fields[0]), specParams{}
+	for _, field := range fields[1:] {
+		key, value, ok := strings.Cut(field, "=")
+		if !ok {
+			value = "true"
+		}
+		if _, dup := params[key]; dup {
+			return nil, fmt.Errorf("%w: %s: parameter %q given twice", ErrInvalidArgs, name, key)
+		}
+		params[key] = value
+	}
+
+	var (
+		s   Scheduler
+		err error
+	)
+	switch name {
+	case fcfs.String():
+		s = FCFS{}
+	case sjf.String():
+		s = SJF{}
+	case sjfp.String(), "priority":
+		var cfg SchedulerConfig
+		if cfg.HigherPriorityFirst, err = params.bool("higher-priority-first"); err != nil {
+			break
+		}
+		var preempt bool
+		if preempt, err = params.bool("preemptive"); err != nil {
+			break
+		}
+		switch {
+		case preempt && name == "priority":
+			s = PreemptivePriority{cfg}
+		case preempt:
+			return nil, fmt.Errorf("%w: %s: preemptive SJF with priority is not supported; use srtf or priority:preemptive", ErrInvalidArgs, name)
+		default:
+			s = SJFPriority{cfg}
+		}
+	case "srtf":
+		s = SRTF{}
+	case rr.String():
+		r := RR{Quantum: 2}
+		if r.Quantum, err = params.int("quantum", r.Quantum); err != nil {
+			break
+		}
+		if r.Resolution, err = params.int("resolution", 1); err != nil {
+			break
+		}
+		if r.Quantum <= 0 || r.Resolution <= 0 {
+			return nil, fmt.Errorf("%w: %s: quantum and resolution must be positive", ErrInvalidArgs, name)
+		}
+		s = r
+	case "hrrn":
+		var h HRRN
+		if h.Preemptive, err = params.bool("preemptive"); err != nil {
+			break
+		}
+		s = h
+	case "ua":
+		s = UtilityAccrual{}
+	case "fair":
+		f := FairShare{Quantum: 2}
+		if f.Quantum, err = params.int("quantum", f.Quantum); err != nil {
+			break
+		}
+		if f.Weights, err = params.weights("weights"); err != nil {
+			break
+		}
+		if f.Quantum <= 0 {
+			return nil, fmt.Errorf("%w: %s: quantum must be positive", ErrInvalidArgs, name)
+		}
+		s = f
+	case "decay":
+		d := Decay{Quantum: 2, Usage: 1, Recovery: 0.5}
+		if d.Quantum, err = params.int("quantum", d.Quantum); err != nil {
+			break
+		}
+		if d.Usage, err = params.float("usage", d.Usage); err != nil {
+			break
+		}
+		if d.Recovery, err = params.float("recovery", d.Recovery); err != nil {
+			break
+		}
+		if d.Quantum <= 0 {
+			return nil, fmt.Errorf("%w: %s: quantum must be positive", ErrInvalidArgs, name)
+		}
+		if d.Usage < 0 || d.Recovery < 0 {
+			return nil, fmt.Errorf("%w: %s: usage and recovery must not be negative", ErrInvalidArgs, name)
+		}
+		s = d
+	case "multicore":
+		var cores int64
+		if cores, err = params.int("cores", 2); err != nil {
+			break
+		}
+		if cores <= 0 {
+			return nil, fmt.Errorf("%w: %s: cores must be positive", ErrInvalidArgs, name)
+		}
+		m := MultiCore{Cores: int(cores)}
+		if m.Dispatch, err = params.dispatch("dispatch"); err != nil {
+			break
+		}
+		if m.Gang, err = params.bool("gang"); err != nil {
+			break
+		}
+		if m.Backfill, err = params.bool("backfill"); err != nil {
+			break
+		}
+		s = m
+	case "edf":
+		var cores int64
+		if cores, err = params.int("cores", 1); err != nil {
+			break
+		}
+		if cores <= 0 {
+			return nil, fmt.Errorf("%w: %s: cores must be positive", ErrInvalidArgs, name)
+		}
+		s = EDF{Cores: int(cores)}
+	case "gang-rr":
+		g := GangRR{Quantum: 2}
+		var cores int64
+		if cores, err = params.int("cores", 2); err != nil {
+			break
+		}
+		if g.Quantum, err = params.int("quantum", g.Quantum); err != nil {
+			break
+		}
+		if cores <= 0 {
+			return nil, fmt.Errorf("%w: %s: cores must be positive", ErrInvalidArgs, name)
+		}
+		if g.Quantum <= 0 {
+			return nil, fmt.Errorf("%w: %s: quantum must be positive", ErrInvalidArgs, name)
+		}
+		g.Cores = int(cores)
+		s = g
+	case "rr-io":
+		r := RRIO{}
+		if r.Quantum, err = params.int("quantum", 2); err != nil {
+			break
+		}
+		if r.Quantum <= 0 {
+			return nil, fmt.Errorf("%w: %s: quantum must be positive", ErrInvalidArgs, name)
+		}
+		s = r
+	case "mlfq":
+		m := MLFQ{}
+		if m.Quanta, err = params.ints("quanta", []int64{2, 4, 8}); err != nil {
+			break
+		}
+		if m.Boost, err = params.int("boost", 0); err != nil {
+			break
+		}
+		if m.Boost < 0 {
+			return nil, fmt.Errorf("%w: %s: boost must not be negative", ErrInvalidArgs, name)
+		}
+		s = m
+	default:
+		return nil, fmt.Errorf("%w: unknown algorithm %q", ErrInvalidArgs, name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrInvalidArgs, name, err)
+	}
+	for key := range params {
+		return nil, fmt.Errorf("%w: %s: unknown parameter %q", ErrInvalidArgs, name, key)
+	}
+	return s, nil
+}
+
+// Procs returns the processes described by specs, in order, each a process ID, a colon and a
+// comma-separated list of key=value parameters, as a terser way to write a workload inline in
+// Go than a Process literal:
+//
+//	P1:arr=0,burst=5,prio=2
+//
+// burst is required; arr (the arrival), prio (the priority) and deadline default to 0, and
+// group names the process's GroupID. It returns an error for a malformed spec or if the
+// processes fail ValidateProcesses.
+func Procs(specs ...string) ([]Process, error) {
+	processes := make([]Process, len(specs))
+	for i, spec := range specs {
+		id, list, ok := strings.Cut(strings.TrimSpace(spec), ":")
+		if !ok || id == "" {
+			return nil, fmt.Errorf("%w: %q is not ID:key=value,...", ErrInvalidProcess, spec)
+		}
+		params := specParams{}
+		for _, field := range strings.Split(list, ",") {
+			key, value, ok := strings.Cut(strings.TrimSpace(field), "=")
+			if !ok {
+				return nil, fmt.Errorf("%w: %s: %q is not key=value", ErrInvalidProcess, id, field)
+			}
+			if _, dup := params[key]; dup {
+				return nil, fmt.Errorf("%w: %s: parameter %q given twice", ErrInvalidProcess, id, key)
+			}
+			params[key] = value
+		}
+		if _, ok := params["burst"]; !ok {
+			return nil, fmt.Errorf("%w: %s: no burst", ErrInvalidProcess, id)
+		}
+
+		p := Process{ProcessID: id, GroupID: params["group"]}
+		delete(params, "group")
+		fields := []struct {
+			key string
+			dst *int64
+		}{{"burst", &p.BurstDuration}, {"arr", &p.ArrivalTime}, {"prio", &p.Priority}, {"deadline", &p.Deadline}}
+		for _, f := range fields {
+			var err error
+			if *f.dst, err = params.int(f.key, 0); err != nil {
+				return nil, fmt.Errorf("%w: %s: %v", ErrInvalidProcess, id, err)
+			}
+		}
+		for key := range params {
+			return nil, fmt.Errorf("%w: %s: unknown parameter %q", ErrInvalidProcess, id, key)
+		}
+		processes[i] = p
+	}
+	return processes, ValidateProcesses(processes)
+}
+
+// specParams holds the parameters of a scheduler spec. Lookups remove the parameters they read,
+// so any left over are unknown.
+type specParams map[string]string
+
+func (p specParams) int(key string, def int64) (int64, error) {
+	value, ok := p[key]
+	if !ok {
+		return def, nil
+	}
+	delete(p, key)
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("parameter %s=%q is not an integer", key, value)
+	}
+	return n, nil
+}
+
+// ints parses a comma-separated list of positive integers.
+func (p specParams) ints(key string, def []int64) ([]int64, error) {
+	value, ok := p[key]
+	if !ok {
+		return def, nil
+	}
+	delete(p, key)
+	var list []int64
+	for _, field := range strings.Split(value, ",") {
+		n, err := strconv.ParseInt(field, 10, 64)
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("parameter %s: %q is not a positive integer", key, field)
+		}
+		list = append(list, n)
+	}
+	return list, nil
+}
+
+func (p specParams) float(key string, def float64) (float64, error) {
+	value, ok := p[key]
+	if !ok {
+		return def, nil
+	}
+	delete(p, key)
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("parameter %s=%q is not a number", key, value)
+	}
+	return f, nil
+}
+
+func (p specParams) bool(key string) (bool, error) {
+	value, ok := p[key]
+	if !ok {
+		return false, nil
+	}
+	delete(p, key)
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("parameter %s=%q is not a boolean", key, value)
+	}
+	return b, nil
+}
+
+func (p specParams) dispatch(key string) (DispatchPolicy, error) {
+	value, ok := p[key]
+	if !ok {
+		return FirstFree, nil
+	}
+	delete(p, key)
+	var d DispatchPolicy
+	if err := d.Set(value); err != nil {
+		return 0, fmt.Errorf("parameter %s=%q is not first-free, round-robin or least-loaded", key, value)
+	}
+	return d, nil
+}
+
+// weights parses a comma-separated list of group=weight pairs.
+func (p specParams) weights(key string) (map[string]int64, error) {
+	value, ok := p[key]
+	if !ok {
+		return nil, nil
+	}
+	delete(p, key)
+	weights := make(map[string]int64)
+	for _, pair := range strings.Split(value, ",") {
+		group, weight, ok := strings.Cut(pair, "=")
+		n, err := strconv.ParseInt(weight, 10, 64)
+		if !ok || err != nil || n <= 0 {
+			return nil, fmt.Errorf("parameter %s: %q is not group=positive weight", key, pair)
+		}
+		weights[group] = n
+	}
+	return weights, nil
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseScheduler(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		spec    string
+		want    Scheduler
+		wantErr string
+	}{
+		{spec: "fcfs", want: FCFS{}},
+		{spec: "SJF", want: SJF{}},
+		{spec: "sjfp", want: SJFPriority{}},
+		{spec: "priority:higher-priority-first", want: SJFPriority{SchedulerConfig{HigherPriorityFirst: true}}},
+		{spec: "rr", want: RR{Quantum: 2, Resolution: 1}},
+		{spec: "rr:quantum=4:resolution=10", want: RR{Quantum: 4, Resolution: 10}},
+		{spec: "hrrn:preemptive", want: HRRN{Preemptive: true}},
+		{spec: "hrrn:preemptive=false", want: HRRN{}},
+		{spec: "ua", want: UtilityAccrual{}},
+		{spec: "fair", want: FairShare{Quantum: 2}},
+		{spec: "fair:quantum=3:weights=web=2,batch=1", want: FairShare{Quantum: 3, Weights: map[string]int64{"web": 2, "batch": 1}}},
+		{spec: "decay", want: Decay{Quantum: 2, Usage: 1, Recovery: 0.5}},
+		{spec: "decay:quantum=1:usage=2:recovery=0.25", want: Decay{Quantum: 1, Usage: 2, Recovery: 0.25}},
+		{spec: "decay:usage=fast", wantErr: `invalid args: decay: parameter usage="fast" is not a number`},
+		{spec: "decay:recovery=-1", wantErr: "invalid args: decay: usage and recovery must not be negative"},
+		{spec: "multicore", want: MultiCore{Cores: 2}},
+		{spec: "multicore:cores=4", want: MultiCore{Cores: 4}},
+		{spec: "multicore:cores=3:dispatch=least-loaded", want: MultiCore{Cores: 3, Dispatch: LeastLoaded}},
+		{spec: "multicore:dispatch=random", wantErr: `invalid args: multicore: parameter dispatch="random" is not first-free, round-robin or least-loaded`},
+		{spec: "multicore:cores=4:backfill", want: MultiCore{Cores: 4, Backfill: true}},
+		{spec: "multicore:gang=true", want: MultiCore{Cores: 2, Gang: true}},
+		{spec: "multicore:cores=0", wantErr: "invalid args: multicore: cores must be positive"},
+		{spec: "edf", want: EDF{Cores: 1}},
+		{spec: "edf:cores=3", want: EDF{Cores: 3}},
+		{spec: "gang-rr", want: GangRR{Cores: 2, Quantum: 2}},
+		{spec: "gang-rr:cores=4:quantum=1", want: GangRR{Cores: 4, Quantum: 1}},
+		{spec: "gang-rr:cores=0", wantErr: "invalid args: gang-rr: cores must be positive"},
+		{spec: "rr-io", want: RRIO{Quantum: 2}},
+		{spec: "rr-io:quantum=0", wantErr: "invalid args: rr-io: quantum must be positive"},
+		{spec: "rr-io:quantum=-3", wantErr: "invalid args: rr-io: quantum must be positive"},
+		{spec: "fair:quantum=0", wantErr: "invalid args: fair: quantum must be positive"},
+		{spec: "fair:quantum=-3", wantErr: "invalid args: fair: quantum must be positive"},
+		{spec: "decay:quantum=0", wantErr: "invalid args: decay: quantum must be positive"},
+		{spec: "decay:quantum=-3", wantErr: "invalid args: decay: quantum must be positive"},
+		{spec: "gang-rr:quantum=0", wantErr: "invalid args: gang-rr: quantum must be positive"},
+		{spec: "gang-rr:quantum=-3", wantErr: "invalid args: gang-rr: quantum must be positive"},
+		{spec: "fair:weights=web", wantErr: `invalid args: fair: parameter weights: "web" is not group=positive weight`},
+		{spec: "mlfq", want: MLFQ{Quanta: []int64{2, 4, 8}}},
+		{spec: "mlfq:quanta=2,4,8:boost=50", want: MLFQ{Quanta: []int64{2, 4, 8}, Boost: 50}},
+		{spec: "mlfq:quanta=1", want: MLFQ{Quanta: []int64{1}}},
+		{spec: "mlfq:quanta=2,0", wantErr: `invalid args: mlfq: parameter quanta: "0" is not a positive integer`},
+		{spec: "mlfq:boost=-1", wantErr: "invalid args: mlfq: boost must not be negative"},
+		{spec: "priority:preemptive", want: PreemptivePriority{}},
+		{spec: "priority:preemptive:higher-priority-first", want: PreemptivePriority{SchedulerConfig{HigherPriorityFirst: true}}},
+		{spec: "sjfp:preemptive", wantErr: "invalid args: sjfp: preemptive SJF with priority is not supported; use srtf or priority:preemptive"},
+		{spec: "srtf", want: SRTF{}},
+		{spec: "rr:quantum=four", wantErr: `invalid args: rr: parameter quantum="four" is not an integer`},
+		{spec: "rr:quantum=0", wantErr: "invalid args: rr: quantum and resolution must be positive"},
+		{spec: "rr:quantum=1:quantum=2", wantErr: `invalid args: rr: parameter "quantum" given twice`},
+		{spec: "hrrn:preemptive=maybe", wantErr: `invalid args: hrrn: parameter preemptive="maybe" is not a boolean`},
+		{spec: "fcfs:quantum=2", wantErr: `invalid args: fcfs: unknown parameter "quantum"`},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.spec, func(t *testing.T) {
+			t.Parallel()
+			got, err := ParseScheduler(tt.spec)
+			if tt.wantErr != "" {
+				if !errors.Is(err, ErrInvalidArgs) {
+					t.Fatalf("error %v is not ErrInvalidArgs", err)
+				}
+				if diff := cmp.Diff(err.Error(), tt.wantErr); diff != "" {
+					t.Errorf(diff)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(got, tt.want); diff != "" {
+				t.Errorf(diff)
+			}
+		})
+	}
+}
+
+func TestProcs(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		specs   []string
+		want    []Process
+		wantErr string
+	}{
+		{name: "none", want: []Process{}},
+		{
+			name:  "all parameters",
+			specs: []string{"P1:arr=0,burst=5,prio=2", " P2:burst=3, arr=4, deadline=10, group=web"},
+			want: []Process{
+				{ProcessID: "P1", BurstDuration: 5, Priority: 2},
+				{ProcessID: "P2", ArrivalTime: 4, BurstDuration: 3, Deadline: 10, GroupID: "web"},
+			},
+		},
+		{name: "no colon", specs: []string{"P1"}, wantErr: `invalid process: "P1" is not ID:key=value,...`},
+		{name: "no id", specs: []string{":burst=1"}, wantErr: `invalid process: ":burst=1" is not ID:key=value,...`},
+		{name: "no burst", specs: []string{"P1:arr=2"}, wantErr: "invalid process: P1: no burst"},
+		{name: "bare key", specs: []string{"P1:burst"}, wantErr: `invalid process: P1: "burst" is not key=value`},
+		{name: "not a number", specs: []string{"P1:burst=five"}, wantErr: `invalid process: P1: parameter burst="five" is not an integer`},
+		{name: "twice", specs: []string{"P1:burst=1,burst=2"}, wantErr: `invalid process: P1: parameter "burst" given twice`},
+		{name: "unknown", specs: []string{"P1:burst=1,weight=2"}, wantErr: `invalid process: P1: unknown parameter "weight"`},
+		{name: "negative", specs: []string{"P1:burst=-1"}, wantErr: "invalid process: P1: negative arrival or burst"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := Procs(tt.specs...)
+			if tt.wantErr != "" {
+				if !errors.Is(err, ErrInvalidProcess) {
+					t.Fatalf("error %v is not ErrInvalidProcess", err)
+				}
+				if diff := cmp.Diff(err.Error(), tt.wantErr); diff != "" {
+					t.Errorf(diff)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(got, tt.want); diff != "" {
+				t.Errorf(diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"math"
+	"slices"
+)
+
+// UtilityFunc is the utility accrued by completing p at completion.
+type UtilityFunc func(p Process, completion int64) float64
+
+// DecayingUtility is the default UtilityFunc: a process accrues 1 if it completes by its
+// Deadline, or has no deadline, and 1/(1+d*late) if it completes late time units after it,
+// where d is the process's own UtilityDecay, or 1 if it has none.
+func DecayingUtility(p Process, completion int64) float64 {
+	if p.Deadline == 0 || completion <= p.Deadline {
+		return 1
+	}
+	d := p.UtilityDecay
+	if d <= 0 {
+		d = 1
+	}
+	return 1 / (1 + d*float64(completion-p.Deadline))
+}
+
+// UtilityAccrual schedules processes to accrue as much utility as it can, as a soft real-time
+// generalization of earliest-deadline-first: a process that misses its deadline still has some
+// value, given by Utility (DecayingUtility if nil), which decays at each process's own
+// UtilityDecay. Each process runs to completion. Whenever
+// the CPU is free it greedily runs the ready process with the highest utility density, the
+// utility of completing it next divided by its burst, falling back to arrival order on ties.
+// Maximizing total utility exactly is NP-hard, so this is a heuristic.
+type UtilityAccrual struct {
+	Utility UtilityFunc
+}
+
+func (UtilityAccrual) Name() string { return "ua" }
+
+func (s UtilityAccrual) utility() UtilityFunc {
+	if s.Utility == nil {
+		return DecayingUtility
+	}
+	return s.Utility
+}
+
+func (s UtilityAccrual) Schedule(processes []Process) ScheduleResult {
+	return s.scheduleClocked(processes, nil)
+}
+
+func (s UtilityAccrual) scheduleClocked(processes []Process, clock *decisionClock) ScheduleResult {
+	utility := s.utility()
+	arr := newArrivals(processes)
+	density := func(p Process, now int64) float64 {
+		u := utility(p, now+p.BurstDuration)
+		if p.BurstDuration == 0 && u > 0 {
+			return math.Inf(1)
+		}
+		return u / float64(max(1, p.BurstDuration))
+	}
+
+	var (
+		now       int64
+		ready     []int
+		queueArea int64 // integral of the ready queue length over time.
+		timings   = make([]processTiming, len(processes))
+		gantt     = make([]TimeSlice, 0, len(processes))
+	)
+	admit := func(i int) {
+		// Processes that arrived during the last run have been waiting since arrival.
+		queueArea += now - processes[i].ArrivalTime
+		ready = append(ready, i)
+	}
+	for range processes {
+		arr.release(now, admit)
+		for len(ready) == 0 {
+			// CPU is idle until the next arrival.
+			now = max(now, arr.idleUntil(now))
+			arr.release(now, admit)
+		}
+
+		t0 := clock.start()
+		candidates := eligible(ready, processes, -1)
+		i := candidates[0]
+		for _, j := range candidates {
+			if density(processes[j], now) > density(processes[i], now) {
+				i = j
+			}
+		}
+		k := slices.Index(ready, i)
+		ready = append(ready[:k], ready[k+1:]...)
+		clock.stop(t0)
+
+		p := processes[i]
+		queueArea += int64(len(ready)) * p.BurstDuration
+		gantt = append(gantt, TimeSlice{
+			PID:   p.ProcessID,
+			Start: now,
+			Stop:  now + p.BurstDuration,
+		})
+		timings[i] = processTiming{
+			Waiting:    now - p.ArrivalTime,
+			Turnaround: now - p.ArrivalTime + p.BurstDuration,
+			Completion: now + p.BurstDuration,
+		}
+		now += p.BurstDuration
+		arr.complete(i)
+	}
+
+	return newScheduleResult(processes, gantt, timings).withQueueArea(queueArea)
+}
+
+// TotalUtility is the utility r accrues under utility, or DecayingUtility if nil.
+func TotalUtility(r ScheduleResult, utility UtilityFunc) float64 {
+	if utility == nil {
+		utility = DecayingUtility
+	}
+	var total float64
+	for _, row := range r.Rows {
+		total += utility(row.Process, row.Completion)
+	}
+	return total
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestUtilityAccrual_Schedule(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: "P0", ArrivalTime: 0, BurstDuration: 6, Deadline: 20},
+		{ProcessID: "P1", ArrivalTime: 1, BurstDuration: 2, Deadline: 4},
+		{ProcessID: "P2", ArrivalTime: 1, BurstDuration: 2, Deadline: 5},
+	}
+	r := UtilityAccrual{}.Schedule(processes)
+
+	// P2 is less late than P1 when both could go next, so it is worth more.
+	want := []TimeSlice{
+		{PID: "P0", Start: 0, Stop: 6},
+		{PID: "P2", Start: 6, Stop: 8},
+		{PID: "P1", Start: 8, Stop: 10},
+	}
+	if diff := cmp.Diff(r.Gantt, want); diff != "" {
+		t.Errorf(diff)
+	}
+	if got, want := TotalUtility(r, nil), 1+1.0/4+1.0/7; math.Abs(got-want) > 1e-9 {
+		t.Errorf("TotalUtility = %v, want %v", got, want)
+	}
+	if fcfs := TotalUtility(FCFS{}.Schedule(processes), nil); fcfs >= TotalUtility(r, nil) {
+		t.Errorf("FCFS accrued %v, no less than utility accrual", fcfs)
+	}
+
+	// A custom utility that only values P1 runs it first.
+	onlyP1 := func(p Process, completion int64) float64 {
+		if p.ProcessID == "P1" {
+			return 1
+		}
+		return 0
+	}
+	r = UtilityAccrual{Utility: onlyP1}.Schedule(processes)
+	if r.Gantt[1].PID != "P1" {
+		t.Errorf("custom utility: got %v", r.Gantt)
+	}
+}
+
+func TestDecayingUtility(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name       string
+		p          Process
+		completion int64
+		want       float64
+	}{
+		{name: "no deadline", p: Process{}, completion: 10, want: 1},
+		{name: "on time", p: Process{Deadline: 5}, completion: 5, want: 1},
+		{name: "late", p: Process{Deadline: 5}, completion: 8, want: 1.0 / 4},
+		{name: "slow decay", p: Process{Deadline: 5, UtilityDecay: 0.5}, completion: 9, want: 1.0 / 3},
+		{name: "fast decay", p: Process{Deadline: 5, UtilityDecay: 3}, completion: 6, want: 1.0 / 4},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if diff := cmp.Diff(DecayingUtility(tt.p, tt.completion), tt.want); diff != "" {
+				t.Errorf(diff)
+			}
+		})
+	}
+}
+
+func TestUtilityAccrual_Summary(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: "P0", ArrivalTime: 0, BurstDuration: 6, Deadline: 20},
+		{ProcessID: "P1", ArrivalTime: 1, BurstDuration: 2, Deadline: 4},
+		{ProcessID: "P2", ArrivalTime: 1, BurstDuration: 2, Deadline: 5},
+	}
+	var w bytes.Buffer
+	if err := SafeSchedule(UtilityAccrual{}, &w, "Utility", processes); err != nil {
+		t.Fatal(err)
+	}
+	// 1 + 1/4 + 1/7, as in TestUtilityAccrual_Schedule.
+	if !strings.Contains(w.String(), "Total utility: 1.39\n") {
+		t.Errorf("output is missing the total utility:\n%s", w.String())
+	}
+
+	w.Reset()
+	if err := SafeSchedule(FCFS{}, &w, "FCFS", processes); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(w.String(), "Total utility") {
+		t.Errorf("FCFS output has a total utility:\n%s", w.String())
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrNoProgress is wrapped by the error a simulation's watchdog panics with when the simulation
+// stops making progress; SafeSchedule returns it.
+var ErrNoProgress = errors.New("simulation made no progress")
+
+// stallLimit is how many decisions in a row a preemptive engine may make without simulated time
+// advancing or a process completing before its watchdog aborts the simulation.
+const stallLimit = 1000
+
+// watchdog detects a simulation loop that has stopped making progress. It is an assertion, not
+// a guard on input: every decision of the engines that preempt, simulate, preemptive, RRIO,
+// Decay, FairShare, GangRR, EDF and MLFQ, as written runs a slice up to the next arrival, the
+// end of a quantum, the next I/O burst or completion, whatever the workload, quantum or
+// selection rule, so no input reaches stallLimit. It turns a regression that would hang a
+// simulation, such as a slice clamped to no time, into an error instead.
+type watchdog struct {
+	now     int64
+	done    int
+	stalled int // decisions in a row at now with done processes complete.
+}
+
+// observe records a decision made at now with done processes complete. Once stallLimit
+// decisions in a row have come at the same time with no process completing, it panics with an
+// ErrNoProgress error naming the processes stuck, which stuck returns.
+func (w *watchdog) observe(now int64, done int, stuck func() []string) {
+	if w.stalled > 0 && now == w.now && done == w.done {
+		w.stalled++
+	} else {
+		w.now, w.done, w.stalled = now, done, 1
+	}
+	if w.stalled >= stallLimit {
+		panic(fmt.Errorf("%w: %d decisions at time %d without a process completing; stuck: %s",
+			ErrNoProgress, w.stalled, now, strings.Join(stuck(), ", ")))
+	}
+}
+
+// unfinished returns a function listing, for watchdog.observe, the IDs of the processes that
+// still have time left to run in remaining.
+func unfinished(processes []Process, remaining []int64) func() []string {
+	return func() []string {
+		var pids []string
+		for i, p := range processes {
+			if remaining[i] > 0 {
+				pids = append(pids, p.ProcessID)
+			}
+		}
+		return pids
+	}
+}
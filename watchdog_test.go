@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// stalledEngine is a Scheduler whose loop never advances time or completes a process, standing
+// in for an engine regression the watchdog should catch. It counts its decisions in decisions.
+type stalledEngine struct{ decisions *int }
+
+func (stalledEngine) Name() string { return "stalled" }
+
+func (s stalledEngine) Schedule(processes []Process) ScheduleResult {
+	remaining := make([]int64, len(processes))
+	for i, p := range processes {
+		remaining[i] = p.BurstDuration
+	}
+	var dog watchdog
+	for {
+		*s.decisions++
+		dog.observe(3, 0, unfinished(processes, remaining))
+	}
+}
+
+func TestWatchdog(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: "P0", ArrivalTime: 0, BurstDuration: 5},
+		{ProcessID: "P1", ArrivalTime: 1, BurstDuration: 0},
+		{ProcessID: "P2", ArrivalTime: 3, BurstDuration: 2},
+	}
+	var (
+		w         bytes.Buffer
+		decisions int
+	)
+	err := SafeSchedule(stalledEngine{&decisions}, &w, "Stalled", processes)
+	if !errors.Is(err, ErrNoProgress) || !errors.Is(err, ErrSchedulerPanic) {
+		t.Fatalf("error = %v, want %v and %v", err, ErrNoProgress, ErrSchedulerPanic)
+	}
+	if decisions != stallLimit {
+		t.Errorf("stopped after %d decisions, want %d", decisions, stallLimit)
+	}
+	want := fmt.Sprintf("scheduler panicked: stalled: simulation made no progress: %d decisions at time 3 without a process completing; stuck: P0, P2 "+
+		"(3 processes, arrivals 0 to 3, bursts 0 to 5)", stallLimit)
+	if err.Error() != want {
+		t.Errorf("error = %q, want %q", err, want)
+	}
+
+	// Time advancing or a process completing just short of the limit starts the count again.
+	none := func() []string { return nil }
+	var advancing, completing watchdog
+	for n := 0; n < stallLimit-1; n++ {
+		advancing.observe(0, 0, none)
+		completing.observe(0, 0, none)
+	}
+	if advancing.stalled != stallLimit-1 {
+		t.Fatalf("stalled = %d after %d decisions, want %d", advancing.stalled, stallLimit-1, stallLimit-1)
+	}
+	advancing.observe(1, 0, none)
+	completing.observe(0, 1, none)
+	if advancing.stalled != 1 {
+		t.Errorf("time advancing: stalled = %d, want 1", advancing.stalled)
+	}
+	if completing.stalled != 1 {
+		t.Errorf("process completing: stalled = %d, want 1", completing.stalled)
+	}
+}
+
+// TestWatchdog_Engines checks that the engines the watchdog asserts on make progress on the
+// workloads closest to stalling: many processes completing at one instant, and a selection
+// rule that changes its mind at every decision.
+func TestWatchdog_Engines(t *testing.T) {
+	t.Parallel()
+	var checkpoints, contended []Process
+	for i := 0; i < 2*stallLimit; i++ {
+		checkpoints = append(checkpoints, Process{ProcessID: fmt.Sprintf("C%d", i)})
+		contended = append(contended, Process{ProcessID: fmt.Sprintf("P%d", i), ArrivalTime: int64(i % 3), BurstDuration: 2})
+	}
+	var flips int
+	flipFlop := func(ready []Process, now int64) int {
+		flips++
+		return flips % len(ready)
+	}
+	schedulers := []Scheduler{
+		RR{Quantum: 2}, RR{Quantum: 1, Resolution: 10}, SRTF{}, RRIO{Quantum: 2},
+		Decay{Quantum: 1, Usage: 1, Recovery: 0.5}, FairShare{Quantum: 1}, GangRR{Cores: 2, Quantum: 1},
+		EDF{Cores: 2}, MLFQ{Quanta: []int64{1, 2}, Boost: 3},
+	}
+	for _, s := range schedulers {
+		for _, processes := range [][]Process{checkpoints, contended} {
+			if err := SafeSchedule(s, &bytes.Buffer{}, s.Name(), processes); err != nil {
+				t.Errorf("%s: %v", s.Name(), err)
+			}
+		}
+	}
+	if err := SafeSchedule(CustomPreemptive{Select: flipFlop}, &bytes.Buffer{}, "custom", contended); err != nil {
+		t.Errorf("flip-flopping rule: %v", err)
+	}
+}